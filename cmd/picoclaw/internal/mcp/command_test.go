@@ -15,6 +15,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/sipeed/picoclaw/pkg/config"
+	picomcp "github.com/sipeed/picoclaw/pkg/mcp"
 )
 
 func TestNewMCPCommand(t *testing.T) {
@@ -33,6 +34,7 @@ func TestNewMCPCommand(t *testing.T) {
 		"edit",
 		"test",
 		"show",
+		"debug",
 	}
 
 	subcommands := cmd.Commands()
@@ -337,6 +339,31 @@ func TestSaveValidatedConfigNormalizesStreamableHTTPAlias(t *testing.T) {
 	assert.Equal(t, "streamable-http", cfg.Tools.MCP.Servers["context7"].Type)
 }
 
+func TestSaveValidatedConfigAcceptsServerTuningFields(t *testing.T) {
+	configPath := setupMCPConfigEnv(t)
+
+	cfg := config.DefaultConfig()
+	cfg.Tools.MCP.Enabled = true
+	cfg.Tools.MCP.Servers = map[string]config.MCPServerConfig{
+		"filesystem": {
+			Enabled:        true,
+			Command:        "npx",
+			IncludeTools:   []string{"read_*"},
+			ExcludeTools:   []string{"read_secret"},
+			Roots:          []string{"projects"},
+			TimeoutSeconds: 30,
+		},
+	}
+
+	require.NoError(t, saveValidatedConfig(cfg))
+
+	server := readMCPConfig(t, configPath).Tools.MCP.Servers["filesystem"]
+	assert.Equal(t, []string{"read_*"}, server.IncludeTools)
+	assert.Equal(t, []string{"read_secret"}, server.ExcludeTools)
+	assert.Equal(t, []string{"projects"}, server.Roots)
+	assert.Equal(t, 30, server.TimeoutSeconds)
+}
+
 func TestMCPRemoveRemovesLastServerAndDisablesMCP(t *testing.T) {
 	configPath := setupMCPConfigEnv(t)
 	writeMCPConfig(t, configPath, &config.Config{
@@ -487,13 +514,54 @@ func TestMCPTestUsesProbe(t *testing.T) {
 	serverProbe = func(_ context.Context, name string, _ config.MCPServerConfig, workspacePath string) (probeResult, error) {
 		assert.Equal(t, "filesystem", name)
 		assert.Equal(t, readMCPConfig(t, configPath).WorkspacePath(), workspacePath)
-		return probeResult{ToolCount: 2}, nil
+		return probeResult{ToolCount: 2, ProtocolVersion: "2025-06-18"}, nil
 	}
 
 	cmd := NewMCPCommand()
 	output, err := executeCommand(cmd, []string{"test", "filesystem"}, "")
 	require.NoError(t, err)
 	assert.Contains(t, output, `MCP server "filesystem" reachable (2 tools)`)
+	assert.Contains(t, output, "Protocol version: 2025-06-18")
+}
+
+func TestMCPDebugPrintsTraffic(t *testing.T) {
+	configPath := setupMCPConfigEnv(t)
+	writeMCPConfig(t, configPath, &config.Config{
+		Tools: config.ToolsConfig{
+			MCP: config.MCPConfig{
+				ToolConfig: config.ToolConfig{Enabled: true},
+				Servers: map[string]config.MCPServerConfig{
+					"filesystem": {
+						Enabled: true,
+						Type:    "stdio",
+						Command: "npx",
+					},
+				},
+			},
+		},
+	})
+
+	originalProbe := serverTrafficProbe
+	defer func() { serverTrafficProbe = originalProbe }()
+	serverTrafficProbe = func(
+		_ context.Context,
+		name string,
+		_ config.MCPServerConfig,
+		_ string,
+	) ([]picomcp.TrafficEntry, error) {
+		assert.Equal(t, "filesystem", name)
+		return []picomcp.TrafficEntry{
+			{Direction: picomcp.TrafficSent, Message: `{"jsonrpc":"2.0","id":1,"method":"initialize"}`},
+			{Direction: picomcp.TrafficReceived, Message: `{"jsonrpc":"2.0","id":1,"result":{}}`},
+		}, nil
+	}
+
+	cmd := NewMCPCommand()
+	output, err := executeCommand(cmd, []string{"debug", "filesystem"}, "")
+	require.NoError(t, err)
+	assert.Contains(t, output, `send {"jsonrpc":"2.0","id":1,"method":"initialize"}`)
+	assert.Contains(t, output, `recv {"jsonrpc":"2.0","id":1,"result":{}}`)
+	assert.Contains(t, output, `2 messages exchanged with MCP server "filesystem"`)
 }
 
 func TestMCPAddDeferredFlag(t *testing.T) {
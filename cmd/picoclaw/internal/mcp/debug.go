@@ -0,0 +1,82 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/sipeed/picoclaw/pkg/config"
+	picomcp "github.com/sipeed/picoclaw/pkg/mcp"
+)
+
+var serverTrafficProbe = defaultServerTrafficProbe
+
+// defaultServerTrafficProbe connects to a server with traffic recording
+// enabled and returns the handshake and tool listing exchange.
+func defaultServerTrafficProbe(
+	ctx context.Context,
+	name string,
+	server config.MCPServerConfig,
+	workspacePath string,
+) ([]picomcp.TrafficEntry, error) {
+	mgr := picomcp.NewManager()
+	defer func() { _ = mgr.Close() }()
+
+	server.Enabled = true
+	server.DebugTraffic = true
+	mcpCfg := config.MCPConfig{
+		ToolConfig: config.ToolConfig{Enabled: true},
+		Servers: map[string]config.MCPServerConfig{
+			name: server,
+		},
+	}
+
+	if err := mgr.LoadFromMCPConfig(ctx, mcpCfg, workspacePath); err != nil {
+		return nil, err
+	}
+
+	return mgr.ServerTraffic(name)
+}
+
+func newDebugCommand() *cobra.Command {
+	var timeout time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "debug <name>",
+		Short: "Connect to an MCP server and dump the handshake's JSON-RPC traffic",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := loadConfig()
+			if err != nil {
+				return err
+			}
+
+			name := args[0]
+			server, exists := cfg.Tools.MCP.Servers[name]
+			if !exists {
+				return fmt.Errorf("MCP server %q not found", name)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+
+			entries, err := serverTrafficProbe(ctx, name, server, cfg.WorkspacePath())
+			if err != nil {
+				return fmt.Errorf("failed to reach MCP server %q: %w", name, err)
+			}
+
+			out := cmd.OutOrStdout()
+			for _, entry := range entries {
+				fmt.Fprintf(out, "%s %-4s %s\n", entry.Time.Format("15:04:05.000"), entry.Direction, entry.Message)
+			}
+			fmt.Fprintf(out, "%d messages exchanged with MCP server %q (secrets redacted).\n", len(entries), name)
+			return nil
+		},
+	}
+
+	cmd.Flags().DurationVar(&timeout, "timeout", 5*time.Second, "Connection timeout")
+
+	return cmd
+}
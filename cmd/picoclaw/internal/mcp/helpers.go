@@ -23,7 +23,8 @@ import (
 )
 
 type probeResult struct {
-	ToolCount int
+	ToolCount       int
+	ProtocolVersion string
 }
 
 var (
@@ -72,6 +73,32 @@ const mcpConfigSchemaJSON = `{
                   "headers": {
                     "type": "object",
                     "additionalProperties": { "type": "string" }
+                  },
+                  "include_tools": {
+                    "type": "array",
+                    "items": { "type": "string" }
+                  },
+                  "exclude_tools": {
+                    "type": "array",
+                    "items": { "type": "string" }
+                  },
+                  "roots": {
+                    "type": "array",
+                    "items": { "type": "string" }
+                  },
+                  "tool_prefix": { "type": "string" },
+                  "max_inline_text_chars": { "type": "integer" },
+                  "subscribe_resources": {
+                    "type": "array",
+                    "items": { "type": "string" }
+                  },
+                  "max_retries": { "type": "integer" },
+                  "keep_alive_seconds": { "type": "integer" },
+                  "timeout_seconds": { "type": "integer" },
+                  "debug_traffic": { "type": "boolean" },
+                  "stderr_log_level": {
+                    "type": "string",
+                    "enum": ["debug", "info", "warn", "error", "off"]
                   }
                 },
                 "required": ["enabled"],
@@ -353,7 +380,7 @@ func defaultServerProbe(
 		return probeResult{}, fmt.Errorf("server %q did not register a connection", name)
 	}
 
-	return probeResult{ToolCount: len(conn.Tools)}, nil
+	return probeResult{ToolCount: len(conn.Tools), ProtocolVersion: conn.ProtocolVersion}, nil
 }
 
 func confirmOverwrite(r io.Reader, w io.Writer, name string) (bool, error) {
@@ -36,6 +36,9 @@ func newTestCommand() *cobra.Command {
 			}
 
 			fmt.Fprintf(cmd.OutOrStdout(), "✓ MCP server %q reachable (%d tools).\n", name, result.ToolCount)
+			if result.ProtocolVersion != "" {
+				fmt.Fprintf(cmd.OutOrStdout(), "  Protocol version: %s\n", result.ProtocolVersion)
+			}
 			return nil
 		},
 	}
@@ -62,6 +62,10 @@ type AgentLoop struct {
 	pendingStops   sync.Map
 	mu             sync.RWMutex
 
+	// fileWriteObserver is told about every file the filesystem tools write
+	// or delete; see SetFileWriteObserver.
+	fileWriteObserver atomic.Pointer[func(path string)]
+
 	// workerSem limits concurrent turn processing workers.
 	workerSem chan struct{}
 
@@ -127,6 +127,10 @@ func registerSharedTools(
 			continue
 		}
 
+		for _, tool := range agent.Tools.GetAll() {
+			tools.ApplyWriteObserver(tool, agent.Workspace, al.noteFileWrite)
+		}
+
 		if cfg.Tools.IsToolEnabled("web") {
 			searchTool, err := tools.NewWebSearchTool(tools.WebSearchToolOptionsFromConfig(cfg))
 			if err != nil {
@@ -247,6 +251,7 @@ func registerSharedTools(
 				nil,
 				allowReadPaths,
 			)
+			tools.ApplyFilePolicy(sendFileTool, agent.FilePolicy)
 			agent.Tools.Register(sendFileTool)
 		}
 
@@ -262,9 +267,16 @@ func registerSharedTools(
 				nil,
 				allowReadPaths,
 			)
+			tools.ApplyFilePolicy(loadImageTool, agent.FilePolicy)
 			agent.Tools.Register(loadImageTool)
 		}
 
+		// Runtime MCP server management is opt-in because it lets the agent
+		// launch any server defined in config, including disabled ones.
+		if cfg.Tools.IsToolEnabled("mcp") && cfg.Tools.MCP.AdminTool {
+			agent.Tools.Register(tools.NewMCPAdminTool(al))
+		}
+
 		// Skill discovery and installation tools
 		skills_enabled := cfg.Tools.IsToolEnabled("skills")
 		find_skills_enable := cfg.Tools.IsToolEnabled("find_skills")
@@ -60,6 +60,23 @@ func (al *AgentLoop) SetReloadFunc(fn func() error) {
 	al.reloadFunc = fn
 }
 
+// SetFileWriteObserver sets the function called with the absolute path of
+// every file the agents' filesystem tools write or delete. The file watch
+// service uses it to skip the agent's own writes.
+func (al *AgentLoop) SetFileWriteObserver(fn func(path string)) {
+	if fn == nil {
+		al.fileWriteObserver.Store(nil)
+		return
+	}
+	al.fileWriteObserver.Store(&fn)
+}
+
+func (al *AgentLoop) noteFileWrite(path string) {
+	if fn := al.fileWriteObserver.Load(); fn != nil {
+		(*fn)(path)
+	}
+}
+
 func (al *AgentLoop) RecordLastChannel(channel string) error {
 	if al.state == nil {
 		return nil
@@ -9,9 +9,16 @@ package agent
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
 	"sync"
+	"time"
 
+	sdkmcp "github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/sipeed/picoclaw/pkg/bus"
 	"github.com/sipeed/picoclaw/pkg/config"
+	"github.com/sipeed/picoclaw/pkg/constants"
 	"github.com/sipeed/picoclaw/pkg/logger"
 	"github.com/sipeed/picoclaw/pkg/mcp"
 	"github.com/sipeed/picoclaw/pkg/tools"
@@ -61,6 +68,17 @@ func (r *mcpRuntime) takeManager() *mcp.Manager {
 	return manager
 }
 
+// getOrCreateManager returns the active manager, creating one with create when
+// MCP initialization left none (e.g. every server was disabled at startup).
+func (r *mcpRuntime) getOrCreateManager(create func() *mcp.Manager) *mcp.Manager {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.manager == nil {
+		r.manager = create()
+	}
+	return r.manager
+}
+
 func (r *mcpRuntime) hasManager() bool {
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -107,15 +125,9 @@ func (al *AgentLoop) ensureMCPInitialized(ctx context.Context) error {
 	}
 
 	al.mcp.initOnce.Do(func() {
-		mcpManager := mcp.NewManager(mcp.WithRuntimeEvents(al.runtimeEvents))
-
-		defaultAgent := al.registry.GetDefaultAgent()
-		workspacePath := al.cfg.WorkspacePath()
-		if defaultAgent != nil && defaultAgent.Workspace != "" {
-			workspacePath = defaultAgent.Workspace
-		}
+		mcpManager := al.newMCPManager()
 
-		if err := mcpManager.LoadFromMCPConfig(ctx, mcpCfg, workspacePath); err != nil {
+		if err := mcpManager.LoadFromMCPConfig(ctx, mcpCfg, al.mcpWorkspacePath()); err != nil {
 			al.mcp.setInitErr(fmt.Errorf("failed to load MCP servers: %w", err))
 			logger.WarnCF("agent", "Failed to load MCP servers, MCP tools will not be available",
 				map[string]any{
@@ -139,70 +151,7 @@ func (al *AgentLoop) ensureMCPInitialized(ctx context.Context) error {
 
 		for serverName, conn := range servers {
 			uniqueTools += len(conn.Tools)
-
-			// Determine whether this server's tools should be deferred (hidden).
-			// Per-server "deferred" field takes precedence over the global Discovery.Enabled.
-			serverCfg := mcpCfg.Servers[serverName]
-			registerAsHidden := serverIsDeferred(al.cfg.Tools.MCP.Discovery.Enabled, serverCfg)
-			registeredToolsByAgent := make(map[string]map[string]struct{}, len(agentIDs))
-
-			for _, tool := range conn.Tools {
-				for _, agentID := range agentIDs {
-					agent, ok := al.registry.GetAgent(agentID)
-					if !ok {
-						continue
-					}
-					if !agent.AllowsMCPServer(serverName) {
-						logger.DebugCF("agent", "Skipped MCP tool registration by agent mcpServers allowlist",
-							map[string]any{
-								"agent_id": agentID,
-								"server":   serverName,
-								"tool":     tool.Name,
-							})
-						continue
-					}
-
-					mcpTool := tools.NewMCPTool(mcpManager, serverName, tool)
-					toolName := mcpTool.Name()
-					mcpTool.SetWorkspace(agent.Workspace)
-					mcpTool.SetMaxInlineTextRunes(al.cfg.Tools.MCP.GetMaxInlineTextChars())
-					mcpTool.SetEventPublisher(al.runtimeEvents)
-
-					if registerAsHidden {
-						agent.Tools.RegisterHidden(mcpTool)
-					} else {
-						agent.Tools.Register(mcpTool)
-					}
-					if !toolRegistryIncludes(agent.Tools, toolName) {
-						continue
-					}
-
-					recordRegisteredMCPTool(registeredToolsByAgent, agentID, toolName)
-					totalRegistrations++
-					logger.DebugCF("agent", "Registered MCP tool",
-						map[string]any{
-							"agent_id": agentID,
-							"server":   serverName,
-							"tool":     tool.Name,
-							"name":     toolName,
-							"deferred": registerAsHidden,
-						})
-				}
-			}
-
-			for _, agentID := range agentIDs {
-				agent, ok := al.registry.GetAgent(agentID)
-				if !ok {
-					continue
-				}
-				registerMCPServerPromptContributor(
-					agentID,
-					agent,
-					serverName,
-					len(registeredToolsByAgent[agentID]),
-					registerAsHidden,
-				)
-			}
+			totalRegistrations += al.registerMCPServerTools(mcpManager, mcpCfg.Servers[serverName], serverName, conn)
 		}
 		logger.InfoCF("agent", "MCP tools registered successfully",
 			map[string]any{
@@ -263,12 +212,361 @@ func (al *AgentLoop) ensureMCPInitialized(ctx context.Context) error {
 			}
 		}
 
+		mcpManager.StartHealthChecks(
+			time.Duration(al.cfg.Tools.MCP.GetHealthCheckInterval()) * time.Second,
+		)
 		al.mcp.setManager(mcpManager)
 	})
 
 	return al.mcp.getInitErr()
 }
 
+func (al *AgentLoop) newMCPManager() *mcp.Manager {
+	var manager *mcp.Manager
+	manager = mcp.NewManager(
+		mcp.WithRuntimeEvents(al.runtimeEvents),
+		mcp.WithResourceUpdateHandler(al.notifyMCPResourceUpdated),
+		mcp.WithToolsChangedHandler(func(serverName string, stale, fresh *mcp.ServerConnection) {
+			al.replaceMCPServerTools(manager, serverName, stale, fresh)
+		}),
+	)
+	return manager
+}
+
+// replaceMCPServerTools swaps the tools registered for a server whose tool
+// set changed when it was restarted.
+func (al *AgentLoop) replaceMCPServerTools(
+	mcpManager *mcp.Manager,
+	serverName string,
+	stale, fresh *mcp.ServerConnection,
+) {
+	al.unregisterMCPServerTools(mcpManager, serverName, stale)
+	registrations := al.registerMCPServerTools(mcpManager, fresh.Config, serverName, fresh)
+	logger.InfoCF("agent", "Re-registered MCP tools after server restart",
+		map[string]any{
+			"server":        serverName,
+			"tools":         len(fresh.Tools),
+			"registrations": registrations,
+		})
+}
+
+// notifyMCPResourceUpdated tells the agent about a changed MCP resource with
+// an inbound system message addressed to the last active channel, the way
+// the file watch service reports file changes.
+func (al *AgentLoop) notifyMCPResourceUpdated(serverName, uri string) {
+	if al.bus == nil || al.state == nil {
+		return
+	}
+	lastChannel := al.state.GetLastChannel()
+	platform, chatID, _ := strings.Cut(lastChannel, ":")
+	if platform == "" || chatID == "" || constants.IsInternalChannel(platform) {
+		logger.DebugCF("agent", "No last channel, skipping MCP resource update notification",
+			map[string]any{
+				"server":   serverName,
+				"resource": uri,
+			})
+		return
+	}
+
+	// The handler runs on the MCP session's notification goroutine.
+	go func() {
+		pubCtx, pubCancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer pubCancel()
+		err := al.bus.PublishInbound(pubCtx, bus.InboundMessage{
+			Context: bus.InboundContext{
+				Channel:  "system",
+				ChatID:   lastChannel,
+				ChatType: "direct",
+				SenderID: "mcp:" + serverName,
+			},
+			Content: fmt.Sprintf("MCP resource updated on server %q: %s", serverName, uri),
+		})
+		if err != nil {
+			logger.WarnCF("agent", "Failed to publish MCP resource update notification",
+				map[string]any{
+					"server":   serverName,
+					"resource": uri,
+					"error":    err.Error(),
+				})
+		}
+	}()
+}
+
+// mcpWorkspacePath is the workspace MCP servers resolve relative paths and
+// roots against.
+func (al *AgentLoop) mcpWorkspacePath() string {
+	defaultAgent := al.registry.GetDefaultAgent()
+	if defaultAgent != nil && defaultAgent.Workspace != "" {
+		return defaultAgent.Workspace
+	}
+	return al.cfg.WorkspacePath()
+}
+
+// registerMCPServerTools registers a connected server's tools with every agent
+// allowed to use it and returns the number of registrations.
+func (al *AgentLoop) registerMCPServerTools(
+	mcpManager *mcp.Manager,
+	serverCfg config.MCPServerConfig,
+	serverName string,
+	conn *mcp.ServerConnection,
+) int {
+	agentIDs := al.registry.ListAgentIDs()
+	totalRegistrations := 0
+
+	// Determine whether this server's tools should be deferred (hidden).
+	// Per-server "deferred" field takes precedence over the global Discovery.Enabled.
+	registerAsHidden := serverIsDeferred(al.cfg.Tools.MCP.Discovery.Enabled, serverCfg)
+	registeredToolsByAgent := make(map[string]map[string]struct{}, len(agentIDs))
+
+	for _, tool := range conn.Tools {
+		for _, agentID := range agentIDs {
+			agent, ok := al.registry.GetAgent(agentID)
+			if !ok {
+				continue
+			}
+			if !agent.AllowsMCPServer(serverName) {
+				logger.DebugCF("agent", "Skipped MCP tool registration by agent mcpServers allowlist",
+					map[string]any{
+						"agent_id": agentID,
+						"server":   serverName,
+						"tool":     tool.Name,
+					})
+				continue
+			}
+
+			mcpTool := newMCPServerTool(mcpManager, serverCfg, serverName, tool)
+			toolName := mcpTool.Name()
+			if owner := mcpToolNameOwner(agent.Tools, toolName); owner != "" && owner != serverName {
+				logger.WarnCF("agent", "Skipped MCP tool registration: name already in use",
+					map[string]any{
+						"agent_id": agentID,
+						"server":   serverName,
+						"tool":     tool.Name,
+						"name":     toolName,
+						"owner":    owner,
+					})
+				continue
+			}
+			mcpTool.SetWorkspace(agent.Workspace)
+			mcpTool.SetMaxInlineTextRunes(al.cfg.Tools.MCP.GetServerMaxInlineTextChars(serverCfg))
+			mcpTool.SetEventPublisher(al.runtimeEvents)
+
+			if registerAsHidden {
+				agent.Tools.RegisterHidden(mcpTool)
+			} else {
+				agent.Tools.Register(mcpTool)
+			}
+			if !toolRegistryIncludes(agent.Tools, toolName) {
+				continue
+			}
+
+			recordRegisteredMCPTool(registeredToolsByAgent, agentID, toolName)
+			totalRegistrations++
+			logger.DebugCF("agent", "Registered MCP tool",
+				map[string]any{
+					"agent_id": agentID,
+					"server":   serverName,
+					"tool":     tool.Name,
+					"name":     toolName,
+					"deferred": registerAsHidden,
+				})
+		}
+	}
+
+	for _, agentID := range agentIDs {
+		agent, ok := al.registry.GetAgent(agentID)
+		if !ok {
+			continue
+		}
+		registerMCPServerPromptContributor(
+			agentID,
+			agent,
+			serverName,
+			len(registeredToolsByAgent[agentID]),
+			registerAsHidden,
+		)
+	}
+
+	return totalRegistrations
+}
+
+// StartMCPServer connects a configured MCP server at runtime and registers its
+// tools with every agent allowed to use it. The server must be defined in
+// tools.mcp.servers but may be disabled there.
+func (al *AgentLoop) StartMCPServer(ctx context.Context, name string) (int, error) {
+	if !al.cfg.Tools.IsToolEnabled("mcp") {
+		return 0, fmt.Errorf("MCP is disabled")
+	}
+
+	mcpCfg := filterMCPConfigServers(al.cfg.Tools.MCP, al.registry.allowedMCPServers())
+	serverCfg, ok := mcpCfg.Servers[name]
+	if !ok {
+		return 0, fmt.Errorf("MCP server %q is not configured for any agent", name)
+	}
+	serverCfg.Enabled = true
+
+	mcpManager := al.mcp.getOrCreateManager(func() *mcp.Manager {
+		manager := al.newMCPManager()
+		manager.StartHealthChecks(
+			time.Duration(al.cfg.Tools.MCP.GetHealthCheckInterval()) * time.Second,
+		)
+		return manager
+	})
+	if err := mcpManager.StartServer(ctx, name, serverCfg, al.mcpWorkspacePath()); err != nil {
+		return 0, err
+	}
+	conn, ok := mcpManager.GetServer(name)
+	if !ok {
+		return 0, fmt.Errorf("MCP server %q did not register a connection", name)
+	}
+
+	registrations := al.registerMCPServerTools(mcpManager, serverCfg, name, conn)
+	logger.InfoCF("agent", "Started MCP server at runtime",
+		map[string]any{
+			"server":        name,
+			"tools":         len(conn.Tools),
+			"registrations": registrations,
+		})
+	return len(conn.Tools), nil
+}
+
+// StopMCPServer unregisters a running server's tools from every agent and
+// shuts the server down.
+func (al *AgentLoop) StopMCPServer(name string) error {
+	mcpManager := al.mcp.getManager()
+	if mcpManager == nil {
+		return fmt.Errorf("MCP server %q is not running", name)
+	}
+	conn, ok := mcpManager.GetServer(name)
+	if !ok {
+		return fmt.Errorf("MCP server %q is not running", name)
+	}
+
+	// Remove the tools first so no new calls are routed to a closing session.
+	al.unregisterMCPServerTools(mcpManager, name, conn)
+
+	if err := mcpManager.DisconnectServer(name); err != nil {
+		return err
+	}
+	logger.InfoCF("agent", "Stopped MCP server at runtime",
+		map[string]any{
+			"server": name,
+		})
+	return nil
+}
+
+// unregisterMCPServerTools removes a connection's tools from every agent.
+func (al *AgentLoop) unregisterMCPServerTools(mcpManager *mcp.Manager, name string, conn *mcp.ServerConnection) {
+	for _, agentID := range al.registry.ListAgentIDs() {
+		agent, ok := al.registry.GetAgent(agentID)
+		if !ok {
+			continue
+		}
+		for _, tool := range conn.Tools {
+			// A colliding name may belong to a builtin or another server,
+			// whose registration must stay.
+			toolName := newMCPServerTool(mcpManager, conn.Config, name, tool).Name()
+			if mcpToolNameOwner(agent.Tools, toolName) == name {
+				agent.Tools.Unregister(toolName)
+			}
+		}
+		if agent.ContextBuilder != nil {
+			// A zero tool count makes the contributor emit nothing.
+			_ = agent.ContextBuilder.RegisterPromptContributor(mcpServerPromptContributor{serverName: name})
+		}
+	}
+}
+
+// SubscribeMCPResource subscribes to updates for a resource on a running
+// server. Each update is sent to the agent as a system message.
+func (al *AgentLoop) SubscribeMCPResource(ctx context.Context, server, uri string) error {
+	mcpManager := al.mcp.getManager()
+	if mcpManager == nil {
+		return fmt.Errorf("MCP server %q is not running", server)
+	}
+	return mcpManager.SubscribeResource(ctx, server, uri)
+}
+
+// UnsubscribeMCPResource stops updates for a resource subscribed with
+// SubscribeMCPResource.
+func (al *AgentLoop) UnsubscribeMCPResource(ctx context.Context, server, uri string) error {
+	mcpManager := al.mcp.getManager()
+	if mcpManager == nil {
+		return fmt.Errorf("MCP server %q is not running", server)
+	}
+	return mcpManager.UnsubscribeResource(ctx, server, uri)
+}
+
+// MCPServerTraffic returns the JSON-RPC messages recorded for a running server
+// that has debug_traffic enabled, oldest first.
+func (al *AgentLoop) MCPServerTraffic(name string) ([]tools.MCPTrafficEntry, error) {
+	mcpManager := al.mcp.getManager()
+	if mcpManager == nil {
+		return nil, fmt.Errorf("MCP server %q is not running", name)
+	}
+	entries, err := mcpManager.ServerTraffic(name)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]tools.MCPTrafficEntry, 0, len(entries))
+	for _, entry := range entries {
+		out = append(out, tools.MCPTrafficEntry{
+			Time:      entry.Time,
+			Direction: entry.Direction,
+			Message:   entry.Message,
+		})
+	}
+	return out, nil
+}
+
+// MCPServerStderr returns the most recent stderr lines of a running stdio
+// server, oldest first.
+func (al *AgentLoop) MCPServerStderr(name string) ([]string, error) {
+	mcpManager := al.mcp.getManager()
+	if mcpManager == nil {
+		return nil, fmt.Errorf("MCP server %q is not running", name)
+	}
+	return mcpManager.ServerStderr(name)
+}
+
+// MCPServerStatuses reports every MCP server configured for at least one
+// agent, sorted by name.
+func (al *AgentLoop) MCPServerStatuses() []tools.MCPServerStatus {
+	mcpCfg := filterMCPConfigServers(al.cfg.Tools.MCP, al.registry.allowedMCPServers())
+	mcpManager := al.mcp.getManager()
+
+	toolStats := make(map[string][]tools.MCPToolStats)
+	if mcpManager != nil {
+		for _, metrics := range mcpManager.ToolMetrics() {
+			toolStats[metrics.Server] = append(toolStats[metrics.Server], tools.MCPToolStats{
+				Tool:          metrics.Tool,
+				Calls:         metrics.Calls,
+				Errors:        metrics.Errors,
+				AvgLatency:    metrics.AvgLatency(),
+				MaxLatency:    metrics.MaxLatency,
+				BytesSent:     metrics.BytesSent,
+				BytesReceived: metrics.BytesReceived,
+			})
+		}
+	}
+
+	statuses := make([]tools.MCPServerStatus, 0, len(mcpCfg.Servers))
+	for name := range mcpCfg.Servers {
+		status := tools.MCPServerStatus{Name: name, ToolStats: toolStats[name]}
+		if mcpManager != nil {
+			if conn, ok := mcpManager.GetServer(name); ok {
+				status.Connected = true
+				status.ToolCount = len(conn.Tools)
+			}
+		}
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Name < statuses[j].Name
+	})
+	return statuses
+}
+
 func registerMCPServerPromptContributor(
 	agentID string,
 	agent *AgentInstance,
@@ -303,6 +601,37 @@ func recordRegisteredMCPTool(
 	registeredToolsByAgent[agentID][toolName] = struct{}{}
 }
 
+// newMCPServerTool wraps an MCP tool, applying the server's tool_prefix.
+func newMCPServerTool(
+	mcpManager *mcp.Manager,
+	serverCfg config.MCPServerConfig,
+	serverName string,
+	tool *sdkmcp.Tool,
+) *tools.MCPTool {
+	mcpTool := tools.NewMCPTool(mcpManager, serverName, tool)
+	if serverCfg.ToolPrefix != nil {
+		mcpTool.SetNamePrefix(*serverCfg.ToolPrefix)
+	}
+	return mcpTool
+}
+
+// mcpToolNameOwner describes what already holds name in registry: the MCP
+// server name for MCP tools, "builtin" for anything else, or "" when the
+// name is free.
+func mcpToolNameOwner(registry *tools.ToolRegistry, name string) string {
+	if registry == nil {
+		return ""
+	}
+	existing, ok := registry.GetRegistered(name)
+	if !ok {
+		return ""
+	}
+	if mcpTool, ok := existing.(*tools.MCPTool); ok {
+		return mcpTool.ServerName()
+	}
+	return "builtin"
+}
+
 func toolRegistryIncludes(registry *tools.ToolRegistry, name string) bool {
 	if registry == nil {
 		return false
@@ -12,6 +12,8 @@ import (
 	"strings"
 	"testing"
 
+	sdkmcp "github.com/modelcontextprotocol/go-sdk/mcp"
+
 	"github.com/sipeed/picoclaw/pkg/config"
 	"github.com/sipeed/picoclaw/pkg/mcp"
 	agenttools "github.com/sipeed/picoclaw/pkg/tools"
@@ -172,6 +174,66 @@ func TestToolRegistryIncludesReportsOnlyRegisteredTools(t *testing.T) {
 	}
 }
 
+func TestMCPToolNameOwnerDetectsCollisions(t *testing.T) {
+	registry := agenttools.NewToolRegistry()
+	registry.Register(&allowlistTestTool{name: "search"})
+
+	bare := ""
+	githubTool := newMCPServerTool(nil, config.MCPServerConfig{ToolPrefix: &bare}, "github", &sdkmcp.Tool{Name: "search"})
+	if got := githubTool.Name(); got != "search" {
+		t.Fatalf("Name() = %q, want bare tool name", got)
+	}
+	if owner := mcpToolNameOwner(registry, githubTool.Name()); owner != "builtin" {
+		t.Fatalf("owner = %q, want builtin", owner)
+	}
+
+	gh := "gh"
+	prefixed := newMCPServerTool(nil, config.MCPServerConfig{ToolPrefix: &gh}, "github", &sdkmcp.Tool{Name: "search"})
+	if got := prefixed.Name(); got != "gh_search" {
+		t.Fatalf("Name() = %q, want %q", got, "gh_search")
+	}
+	if owner := mcpToolNameOwner(registry, prefixed.Name()); owner != "" {
+		t.Fatalf("owner = %q, want free name", owner)
+	}
+
+	registry.Register(prefixed)
+	if owner := mcpToolNameOwner(registry, "gh_search"); owner != "github" {
+		t.Fatalf("owner = %q, want github", owner)
+	}
+}
+
+func TestUnregisterMCPServerToolsKeepsCollidingNames(t *testing.T) {
+	al, _, _, _, cleanup := newTestAgentLoop(t)
+	defer cleanup()
+	defer al.Close()
+
+	agent := al.registry.GetDefaultAgent()
+	bare := ""
+	bareCfg := config.MCPServerConfig{ToolPrefix: &bare}
+	agent.Tools.Register(&allowlistTestTool{name: "search"})
+	agent.Tools.Register(newMCPServerTool(nil, bareCfg, "other", &sdkmcp.Tool{Name: "lookup"}))
+	agent.Tools.Register(newMCPServerTool(nil, bareCfg, "github", &sdkmcp.Tool{Name: "issues"}))
+
+	// github declares tools whose bare names collide with a builtin and with
+	// another server's tool; its registrations for those were skipped.
+	conn := &mcp.ServerConnection{
+		Name:   "github",
+		Config: bareCfg,
+		Tools:  []*sdkmcp.Tool{{Name: "search"}, {Name: "lookup"}, {Name: "issues"}},
+	}
+	al.unregisterMCPServerTools(nil, "github", conn)
+
+	if owner := mcpToolNameOwner(agent.Tools, "search"); owner != "builtin" {
+		t.Fatalf("search owner = %q, want the builtin to stay registered", owner)
+	}
+	if owner := mcpToolNameOwner(agent.Tools, "lookup"); owner != "other" {
+		t.Fatalf("lookup owner = %q, want the other server's tool to stay registered", owner)
+	}
+	if owner := mcpToolNameOwner(agent.Tools, "issues"); owner != "" {
+		t.Fatalf("issues owner = %q, want github's own tool removed", owner)
+	}
+}
+
 func TestFilterMCPConfigServersCaseInsensitivePreservesOriginalKeys(t *testing.T) {
 	mcpCfg := config.MCPConfig{
 		Servers: map[string]config.MCPServerConfig{
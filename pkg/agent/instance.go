@@ -5,7 +5,9 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/sipeed/picoclaw/pkg/config"
 	"github.com/sipeed/picoclaw/pkg/isolation"
@@ -44,6 +46,9 @@ type AgentInstance struct {
 	MCPServerAllowlist        map[string]struct{}
 	Candidates                []providers.FallbackCandidate
 	ImageCandidates           []providers.FallbackCandidate
+	// FilePolicy holds the read-only and deny rules applied to the agent's
+	// filesystem tools; nil when none are configured.
+	FilePolicy *tools.FilePolicy
 
 	// Router is non-nil when model routing is configured and the light model
 	// was successfully resolved. It scores each incoming message and decides
@@ -88,6 +93,10 @@ func NewAgentInstance(
 	// Compile path whitelist patterns from config.
 	allowReadPaths := buildAllowReadPatterns(cfg)
 	allowWritePaths := compilePatterns(cfg.Tools.AllowWritePaths)
+	var fileBackups *tools.FileBackups
+	if cfg.Tools.IsToolEnabled("undo_file") {
+		fileBackups = tools.NewFileBackups(workspace, cfg.Tools.UndoFile.MaxBackups)
+	}
 	agentToolAllowlist := resolveAgentToolAllowlist(definition)
 	agentMCPServerAllowlist := resolveAgentMCPServerAllowlist(definition)
 
@@ -106,9 +115,51 @@ func NewAgentInstance(
 	if cfg.Tools.IsToolEnabled("edit_file") {
 		toolsRegistry.Register(tools.NewEditFileTool(workspace, restrict, allowWritePaths))
 	}
+	if cfg.Tools.IsToolEnabled("multi_edit") {
+		toolsRegistry.Register(tools.NewMultiEditTool(workspace, restrict, allowWritePaths))
+	}
+	if cfg.Tools.IsToolEnabled("apply_patch") {
+		toolsRegistry.Register(tools.NewApplyPatchTool(workspace, restrict, allowWritePaths))
+	}
 	if cfg.Tools.IsToolEnabled("append_file") {
 		toolsRegistry.Register(tools.NewAppendFileTool(workspace, restrict, allowWritePaths))
 	}
+	if cfg.Tools.IsToolEnabled("write_session") {
+		toolsRegistry.Register(tools.NewWriteSessionTool(workspace, restrict, allowWritePaths))
+	}
+	if cfg.Tools.IsToolEnabled("delete_file") {
+		toolsRegistry.Register(tools.NewDeleteFileTool(workspace, restrict, allowWritePaths))
+	}
+	if cfg.Tools.IsToolEnabled("copy_file") {
+		toolsRegistry.Register(tools.NewCopyFileTool(workspace, restrict, allowWritePaths))
+	}
+	if cfg.Tools.IsToolEnabled("archive") {
+		toolsRegistry.Register(tools.NewArchiveTool(workspace, restrict, allowWritePaths))
+	}
+	if cfg.Tools.IsToolEnabled("set_permissions") {
+		toolsRegistry.Register(tools.NewSetPermissionsTool(workspace, restrict, allowWritePaths))
+	}
+	if cfg.Tools.IsToolEnabled("undo_file") {
+		toolsRegistry.Register(tools.NewUndoFileTool(workspace, restrict, fileBackups, allowWritePaths))
+	}
+	if cfg.Tools.IsToolEnabled("scratch") {
+		scratchTool := tools.NewScratchTool(
+			workspace,
+			time.Duration(cfg.Tools.Scratch.MaxAgeHours)*time.Hour,
+			int64(cfg.Tools.Scratch.MaxSizeMB)*1024*1024,
+		)
+		if removed, err := scratchTool.Cleanup(); err != nil {
+			logger.WarnCF("agent", "Scratch cleanup failed",
+				map[string]any{"workspace": workspace, "error": err.Error()})
+		} else if len(removed) > 0 {
+			logger.InfoCF("agent", "Removed expired scratch directories",
+				map[string]any{"workspace": workspace, "removed": removed})
+		}
+		toolsRegistry.Register(scratchTool)
+	}
+	if cfg.Tools.IsToolEnabled("snapshot") {
+		toolsRegistry.Register(tools.NewSnapshotTool(workspace, cfg.Tools.Snapshot.MaxSnapshots))
+	}
 	// Build write_file's copy from the registered editors so it steers the agent
 	// to edit_file/append_file only when those tools are actually available.
 	if cfg.Tools.IsToolEnabled("write_file") {
@@ -126,6 +177,25 @@ func NewAgentInstance(
 	if cfg.Tools.IsToolEnabled("list_dir") {
 		toolsRegistry.Register(tools.NewListDirTool(workspace, readRestrict, allowReadPaths))
 	}
+	if cfg.Tools.IsToolEnabled("find_files") {
+		toolsRegistry.Register(tools.NewFindFilesTool(workspace, readRestrict, allowReadPaths))
+	}
+	if cfg.Tools.IsToolEnabled("grep") {
+		toolsRegistry.Register(tools.NewGrepTool(workspace, readRestrict, allowReadPaths))
+	}
+	if cfg.Tools.IsToolEnabled("file_stat") {
+		toolsRegistry.Register(tools.NewStatTool(workspace, readRestrict, allowReadPaths))
+	}
+	if cfg.Tools.IsToolEnabled("tail_file") {
+		toolsRegistry.Register(tools.NewTailFileTool(
+			workspace, readRestrict, cfg.Tools.ReadFile.MaxReadFileSize, allowReadPaths,
+		))
+	}
+	if cfg.Tools.IsToolEnabled("query_data") {
+		toolsRegistry.Register(tools.NewDataQueryTool(
+			workspace, readRestrict, cfg.Tools.ReadFile.MaxReadFileSize, allowReadPaths,
+		))
+	}
 	if cfg.Tools.IsToolEnabled("exec") {
 		execTool, err := tools.NewExecToolWithConfig(workspace, restrict, cfg, allowReadPaths)
 		if err != nil {
@@ -136,6 +206,14 @@ func NewAgentInstance(
 		}
 	}
 
+	// Backups wrap the tools first so writes rejected by the policy are not
+	// backed up.
+	filePolicy := resolveAgentFilePolicy(workspace, agentCfg, defaults)
+	for _, tool := range toolsRegistry.GetAll() {
+		tools.ApplyFileBackups(tool, fileBackups)
+		tools.ApplyFilePolicy(tool, filePolicy)
+	}
+
 	sessionsDir := filepath.Join(workspace, "sessions")
 	sessions := initSessionStore(sessionsDir)
 
@@ -287,6 +365,7 @@ func NewAgentInstance(
 		MCPServerAllowlist:        agentMCPServerAllowlist,
 		Candidates:                candidates,
 		ImageCandidates:           imageCandidates,
+		FilePolicy:                filePolicy,
 		Router:                    router,
 		LightCandidates:           lightCandidates,
 		LightProvider:             lightProvider,
@@ -430,6 +509,22 @@ func (a *AgentInstance) AllowsMCPServer(serverName string) bool {
 	return ok
 }
 
+// resolveAgentFilePolicy merges the default file policy with the agent's own
+// rules. It returns nil when neither defines any.
+func resolveAgentFilePolicy(
+	workspace string,
+	agentCfg *config.AgentConfig,
+	defaults *config.AgentDefaults,
+) *tools.FilePolicy {
+	readOnly := slices.Clone(defaults.FilePolicy.ReadOnly)
+	deny := slices.Clone(defaults.FilePolicy.Deny)
+	if agentCfg != nil && agentCfg.FilePolicy != nil {
+		readOnly = append(readOnly, agentCfg.FilePolicy.ReadOnly...)
+		deny = append(deny, agentCfg.FilePolicy.Deny...)
+	}
+	return tools.NewFilePolicy(workspace, readOnly, deny)
+}
+
 func compilePatterns(patterns []string) []*regexp.Regexp {
 	compiled := make([]*regexp.Regexp, 0, len(patterns))
 	for _, p := range patterns {
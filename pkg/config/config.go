@@ -47,6 +47,7 @@ type Config struct {
 	Tools     ToolsConfig     `json:"tools"               yaml:",inline"`
 	Heartbeat HeartbeatConfig `json:"heartbeat"           yaml:"-"`
 	Devices   DevicesConfig   `json:"devices"             yaml:"-"`
+	Watch     WatchConfig     `json:"watch"               yaml:"-"`
 	Voice     VoiceConfig     `json:"voice"               yaml:"-"`
 	// BuildInfo contains build-time version information
 	BuildInfo BuildInfo `json:"build_info,omitempty" yaml:"-"`
@@ -312,13 +313,24 @@ func (m AgentModelConfig) MarshalJSON() ([]byte, error) {
 }
 
 type AgentConfig struct {
-	ID        string            `json:"id"`
-	Default   bool              `json:"default,omitempty"`
-	Name      string            `json:"name,omitempty"`
-	Workspace string            `json:"workspace,omitempty"`
-	Model     *AgentModelConfig `json:"model,omitempty"`
-	Skills    []string          `json:"skills,omitempty"`
-	Subagents *SubagentsConfig  `json:"subagents,omitempty"`
+	ID         string            `json:"id"`
+	Default    bool              `json:"default,omitempty"`
+	Name       string            `json:"name,omitempty"`
+	Workspace  string            `json:"workspace,omitempty"`
+	Model      *AgentModelConfig `json:"model,omitempty"`
+	Skills     []string          `json:"skills,omitempty"`
+	Subagents  *SubagentsConfig  `json:"subagents,omitempty"`
+	FilePolicy *FilePolicyConfig `json:"file_policy,omitempty"`
+}
+
+// FilePolicyConfig protects files from the filesystem tools even when
+// restrict_to_workspace is off. Entries are globs: `*.env` matches a file
+// name anywhere, `secrets/**` a workspace-relative path and `/etc/**` an
+// absolute one. A matching directory covers everything beneath it. An
+// agent's own file_policy is added to agents.defaults.file_policy.
+type FilePolicyConfig struct {
+	ReadOnly []string `json:"read_only,omitempty"`
+	Deny     []string `json:"deny,omitempty"`
 }
 
 type SubagentsConfig struct {
@@ -441,6 +453,7 @@ type AgentDefaults struct {
 	MaxParallelTurns          int                `json:"max_parallel_turns,omitempty"     env:"PICOCLAW_AGENTS_DEFAULTS_MAX_PARALLEL_TURNS"` // Max concurrent turns (0 or 1 = sequential)
 	SubTurn                   SubTurnConfig      `json:"subturn"                                                                                      envPrefix:"PICOCLAW_AGENTS_DEFAULTS_SUBTURN_"`
 	ToolFeedback              ToolFeedbackConfig `json:"tool_feedback,omitempty"`
+	FilePolicy                FilePolicyConfig   `json:"file_policy,omitempty"`
 	SplitOnMarker             bool               `json:"split_on_marker"                  env:"PICOCLAW_AGENTS_DEFAULTS_SPLIT_ON_MARKER"` // split messages on <|[SPLIT]|> marker
 	ContextManager            string             `json:"context_manager,omitempty"        env:"PICOCLAW_AGENTS_DEFAULTS_CONTEXT_MANAGER"`
 	ContextManagerConfig      json.RawMessage    `json:"context_manager_config,omitempty" env:"PICOCLAW_AGENTS_DEFAULTS_CONTEXT_MANAGER_CONFIG"`
@@ -760,6 +773,14 @@ type DevicesConfig struct {
 	MonitorUSB bool `json:"monitor_usb" env:"PICOCLAW_DEVICES_MONITOR_USB"`
 }
 
+// WatchConfig configures the file watch service, which tells the agent about
+// files created, modified or deleted under Paths.
+type WatchConfig struct {
+	Enabled  bool     `json:"enabled"  env:"PICOCLAW_WATCH_ENABLED"`
+	Paths    []string `json:"paths"    env:"PICOCLAW_WATCH_PATHS"`
+	Interval int      `json:"interval" env:"PICOCLAW_WATCH_INTERVAL"` // seconds, min 1
+}
+
 type VoiceConfig struct {
 	ModelName         string `json:"model_name,omitempty"         env:"PICOCLAW_VOICE_MODEL_NAME"`
 	TTSModelName      string `json:"tts_model_name,omitempty"     env:"PICOCLAW_VOICE_TTS_MODEL_NAME"`
@@ -1067,6 +1088,103 @@ type ExecConfig struct {
 	CustomDenyPatterns  []string `                                 json:"custom_deny_patterns"  env:"PICOCLAW_TOOLS_EXEC_CUSTOM_DENY_PATTERNS"`
 	CustomAllowPatterns []string `                                 json:"custom_allow_patterns" env:"PICOCLAW_TOOLS_EXEC_CUSTOM_ALLOW_PATTERNS"`
 	TimeoutSeconds      int      `                                 json:"timeout_seconds"       env:"PICOCLAW_TOOLS_EXEC_TIMEOUT_SECONDS"` // 0 means use default (60s)
+	FilterEnv           bool     `                                 json:"filter_env"            env:"PICOCLAW_TOOLS_EXEC_FILTER_ENV"`      // pass only PATH, HOME, locale... plus EnvAllowlist
+	EnvAllowlist        []string `                                 json:"env_allowlist"         env:"PICOCLAW_TOOLS_EXEC_ENV_ALLOWLIST"`
+
+	// Profile selects a safety profile; empty runs any command the guard allows.
+	Profile  string                 `json:"profile"            env:"PICOCLAW_TOOLS_EXEC_PROFILE"`
+	Profiles map[string]ExecProfile `json:"profiles,omitempty"`
+}
+
+// ExecProfile limits what the exec tool may run. Profiles are selected with
+// tools.exec.profile; entries under tools.exec.profiles add profiles or replace
+// the built-in read-only, developer and admin ones.
+type ExecProfile struct {
+	// AllowedCommands lists the commands each part of a shell command line may
+	// start with, either a binary name ("ls") or a binary and subcommand
+	// ("git status"). Empty allows any command.
+	AllowedCommands []string `json:"allowed_commands,omitempty"`
+	// DeniedFlags are arguments rejected anywhere in the command, such as
+	// "--force". A flag also matches its "--flag=value" form, a trailing "*"
+	// matches any flag with that prefix, and a single-letter flag matches
+	// inside a group like "-uo". An entry of the form "sort -o" or
+	// "git branch -d" applies to commands starting with those words only.
+	DeniedFlags []string `json:"denied_flags,omitempty"`
+	// AllowRedirects permits > and >> output redirection into files.
+	AllowRedirects bool `json:"allow_redirects,omitempty"`
+	// TimeoutSeconds replaces tools.exec.timeout_seconds when positive.
+	TimeoutSeconds int `json:"timeout_seconds,omitempty"`
+}
+
+const (
+	ExecProfileReadOnly  = "read-only"
+	ExecProfileDeveloper = "developer"
+	ExecProfileAdmin     = "admin"
+)
+
+// execReadOnlyCommands leaves out commands that write to a file given as a
+// plain argument, such as uniq's output file, since no flag marks it.
+var execReadOnlyCommands = []string{
+	"ls", "cat", "head", "tail", "wc", "grep", "rg", "find", "stat", "file", "du", "df", "pwd", "echo",
+	"printf", "date", "whoami", "uname", "which", "sort", "cut", "tr", "diff", "cmp", "jq",
+	"md5sum", "sha256sum", "tree", "basename", "dirname", "realpath", "true", "false", "test",
+	"git status", "git log", "git diff", "git show", "git branch --list", "git branch --show-current",
+	"git blame", "git rev-parse", "git ls-files",
+}
+
+// builtinExecProfiles are used unless tools.exec.profiles redefines them.
+var builtinExecProfiles = map[string]ExecProfile{
+	ExecProfileReadOnly: {
+		AllowedCommands: execReadOnlyCommands,
+		DeniedFlags: []string{
+			// find actions that delete, run programs or write files.
+			"-delete", "-exec", "-execdir", "-ok", "-okdir", "-fprint", "-fprint0", "-fprintf", "-fls",
+			// rg preprocessors run a program per file.
+			"--pre", "--pre-glob",
+			// Options that write their output to a file.
+			"--output", "sort -o", "tree -o", "file -C", "file --compile",
+			// git branch options that create, delete, rename or reconfigure
+			// branches; git already rejects most of them next to --list.
+			"git branch -d", "git branch -D", "git branch --delete", "git branch -m", "git branch -M",
+			"git branch --move", "git branch -c", "git branch -C", "git branch --copy", "git branch -f",
+			"git branch --force", "git branch -u", "git branch --set-upstream-to", "git branch --unset-upstream",
+			"git branch -t", "git branch --track", "git branch --edit-description",
+		},
+		TimeoutSeconds: 30,
+	},
+	ExecProfileDeveloper: {
+		AllowedCommands: append(slices.Clone(execReadOnlyCommands),
+			"git", "make", "go", "gofmt", "cargo", "rustc", "gcc", "g++", "cc", "cmake", "node", "npm", "npx",
+			"pnpm", "yarn", "python", "python3", "pip", "pip3", "uv", "pytest", "mkdir", "touch", "cp", "mv",
+			"sed", "awk", "tar", "gzip", "gunzip", "unzip", "zip", "curl", "wget", "uniq",
+		),
+		// "--force*" also covers --force-with-lease and --force-if-includes;
+		// "git -f" covers git push -f and git clean -fdx.
+		DeniedFlags:    []string{"--force*", "git -f", "--no-verify", "--hard"},
+		AllowRedirects: true,
+		TimeoutSeconds: 300,
+	},
+	ExecProfileAdmin: {
+		AllowRedirects: true,
+	},
+}
+
+// ActiveProfile returns the profile selected by Profile, or nil when no
+// profile is selected and exec runs any command the safety guard allows.
+func (c ExecConfig) ActiveProfile() (*ExecProfile, error) {
+	name := strings.TrimSpace(c.Profile)
+	if name == "" {
+		return nil, nil
+	}
+	if profile, ok := c.Profiles[name]; ok {
+		return &profile, nil
+	}
+	if profile, ok := builtinExecProfiles[name]; ok {
+		profile.AllowedCommands = slices.Clone(profile.AllowedCommands)
+		profile.DeniedFlags = slices.Clone(profile.DeniedFlags)
+		return &profile, nil
+	}
+	return nil, fmt.Errorf("unknown exec profile %q", name)
 }
 
 type SkillsToolsConfig struct {
@@ -1084,6 +1202,31 @@ type MediaCleanupConfig struct {
 	Interval   int `                                    json:"interval_minutes" env:"PICOCLAW_MEDIA_CLEANUP_INTERVAL"`
 }
 
+// UndoFileToolConfig enables undo_file. While it is enabled the filesystem
+// tools keep up to MaxBackups previous versions of each file they change.
+type UndoFileToolConfig struct {
+	ToolConfig `yaml:"-" envPrefix:"PICOCLAW_TOOLS_UNDO_FILE_"`
+
+	MaxBackups int `json:"max_backups" yaml:"-" env:"PICOCLAW_TOOLS_UNDO_FILE_MAX_BACKUPS"`
+}
+
+// ScratchToolConfig enables the scratch tool and sets the cleanup policy for
+// <workspace>/scratch.
+type ScratchToolConfig struct {
+	ToolConfig `yaml:"-" envPrefix:"PICOCLAW_TOOLS_SCRATCH_"`
+
+	MaxAgeHours int `json:"max_age_hours" yaml:"-" env:"PICOCLAW_TOOLS_SCRATCH_MAX_AGE_HOURS"`
+	MaxSizeMB   int `json:"max_size_mb"   yaml:"-" env:"PICOCLAW_TOOLS_SCRATCH_MAX_SIZE_MB"`
+}
+
+// SnapshotToolConfig enables the snapshot tool and sets how many workspace
+// snapshots it keeps.
+type SnapshotToolConfig struct {
+	ToolConfig `yaml:"-" envPrefix:"PICOCLAW_TOOLS_SNAPSHOT_"`
+
+	MaxSnapshots int `json:"max_snapshots" yaml:"-" env:"PICOCLAW_TOOLS_SNAPSHOT_MAX_SNAPSHOTS"`
+}
+
 type ReadFileToolConfig struct {
 	Enabled         bool   `json:"enabled"`
 	Mode            string `json:"mode"`
@@ -1124,11 +1267,26 @@ type ToolsConfig struct {
 	MediaCleanup    MediaCleanupConfig `json:"media_cleanup"     yaml:"-"`
 	MCP             MCPConfig          `json:"mcp"               yaml:"-"`
 	AppendFile      ToolConfig         `json:"append_file"       yaml:"-"                                                       envPrefix:"PICOCLAW_TOOLS_APPEND_FILE_"`
+	WriteSession    ToolConfig         `json:"write_session"     yaml:"-"                                                       envPrefix:"PICOCLAW_TOOLS_WRITE_SESSION_"`
+	DeleteFile      ToolConfig         `json:"delete_file"       yaml:"-"                                                       envPrefix:"PICOCLAW_TOOLS_DELETE_FILE_"`
+	CopyFile        ToolConfig         `json:"copy_file"         yaml:"-"                                                       envPrefix:"PICOCLAW_TOOLS_COPY_FILE_"`
+	Archive         ToolConfig         `json:"archive"           yaml:"-"                                                       envPrefix:"PICOCLAW_TOOLS_ARCHIVE_"`
+	SetPermissions  ToolConfig         `json:"set_permissions"   yaml:"-"                                                       envPrefix:"PICOCLAW_TOOLS_SET_PERMISSIONS_"`
+	UndoFile        UndoFileToolConfig `json:"undo_file"         yaml:"-"`
+	Scratch         ScratchToolConfig  `json:"scratch"           yaml:"-"`
+	Snapshot        SnapshotToolConfig `json:"snapshot"          yaml:"-"`
 	EditFile        ToolConfig         `json:"edit_file"         yaml:"-"                                                       envPrefix:"PICOCLAW_TOOLS_EDIT_FILE_"`
+	MultiEdit       ToolConfig         `json:"multi_edit"        yaml:"-"                                                       envPrefix:"PICOCLAW_TOOLS_MULTI_EDIT_"`
+	ApplyPatch      ToolConfig         `json:"apply_patch"       yaml:"-"                                                       envPrefix:"PICOCLAW_TOOLS_APPLY_PATCH_"`
 	FindSkills      ToolConfig         `json:"find_skills"       yaml:"-"                                                       envPrefix:"PICOCLAW_TOOLS_FIND_SKILLS_"`
 	I2C             ToolConfig         `json:"i2c"               yaml:"-"                                                       envPrefix:"PICOCLAW_TOOLS_I2C_"`
 	InstallSkill    ToolConfig         `json:"install_skill"     yaml:"-"                                                       envPrefix:"PICOCLAW_TOOLS_INSTALL_SKILL_"`
 	ListDir         ToolConfig         `json:"list_dir"          yaml:"-"                                                       envPrefix:"PICOCLAW_TOOLS_LIST_DIR_"`
+	FindFiles       ToolConfig         `json:"find_files"        yaml:"-"                                                       envPrefix:"PICOCLAW_TOOLS_FIND_FILES_"`
+	Grep            ToolConfig         `json:"grep"              yaml:"-"                                                       envPrefix:"PICOCLAW_TOOLS_GREP_"`
+	FileStat        ToolConfig         `json:"file_stat"         yaml:"-"                                                       envPrefix:"PICOCLAW_TOOLS_FILE_STAT_"`
+	TailFile        ToolConfig         `json:"tail_file"         yaml:"-"                                                       envPrefix:"PICOCLAW_TOOLS_TAIL_FILE_"`
+	QueryData       ToolConfig         `json:"query_data"        yaml:"-"                                                       envPrefix:"PICOCLAW_TOOLS_QUERY_DATA_"`
 	LoadImage       ToolConfig         `json:"load_image"        yaml:"-"                                                       envPrefix:"PICOCLAW_TOOLS_LOAD_IMAGE_"`
 	Message         MessageToolsConfig `json:"message"           yaml:"-"`
 	ReadFile        ReadFileToolConfig `json:"read_file"         yaml:"-"                                                       envPrefix:"PICOCLAW_TOOLS_READ_FILE_"`
@@ -1269,6 +1427,73 @@ type MCPServerConfig struct {
 	URL string `json:"url,omitempty"`
 	// Headers are HTTP headers to send with requests (sse/http only)
 	Headers map[string]string `json:"headers,omitempty"`
+	// IncludeTools limits the exposed tools to names matching at least one of
+	// these glob patterns (e.g. "get_*"). When empty, every tool is included.
+	IncludeTools []string `json:"include_tools,omitempty"`
+	// ExcludeTools hides tools whose names match any of these glob patterns.
+	// Exclusion is applied after IncludeTools.
+	ExcludeTools []string `json:"exclude_tools,omitempty"`
+	// ToolPrefix replaces the default "mcp_<server>_" prefix of this server's
+	// tool names. An empty string registers tools under their bare names. A tool
+	// whose name collides with one already registered by another source is
+	// skipped.
+	ToolPrefix *string `json:"tool_prefix,omitempty"`
+	// SubscribeResources lists resource URIs to subscribe to after connecting.
+	// Updates are published as mcp.resource.updated runtime events.
+	SubscribeResources []string `json:"subscribe_resources,omitempty"`
+	// MaxInlineTextChars overrides tools.mcp.max_inline_text_chars for this
+	// server's tool results. Zero uses the global setting.
+	MaxInlineTextChars int `json:"max_inline_text_chars,omitempty"`
+	// Roots lists directories advertised to the server through the MCP roots
+	// capability. Relative paths resolve against the workspace; when empty the
+	// workspace itself is the only root.
+	Roots []string `json:"roots,omitempty"`
+	// StderrLogLevel sets the level at which a stdio server's stderr lines are
+	// logged: "debug" (default), "info", "warn", "error" or "off".
+	StderrLogLevel string `json:"stderr_log_level,omitempty"`
+	// DebugTraffic records recent JSON-RPC messages exchanged with this server,
+	// with secrets redacted, for the mcp_admin tool's traffic action.
+	DebugTraffic bool `json:"debug_traffic,omitempty"`
+	// MaxRetries is how many times idempotent requests (initialize, tools/list,
+	// ...) to an sse/http server are retried on 429/502/503/504 or connection
+	// resets. Zero uses the default; negative disables retries.
+	MaxRetries int `json:"max_retries,omitempty"`
+	// KeepAliveSeconds pings an sse/http server at this interval and treats a
+	// missed reply as a dead connection, so proxy or NAT idle timeouts are
+	// detected before the next tool call. Zero disables keep-alive pings.
+	KeepAliveSeconds int `json:"keep_alive_seconds,omitempty"`
+	// TimeoutSeconds bounds each tool call to this server and the connect
+	// handshake. Zero uses the default; negative disables the limit.
+	TimeoutSeconds int `json:"timeout_seconds,omitempty"`
+}
+
+const (
+	DefaultMCPServerTimeoutSeconds = 60
+	DefaultMCPServerMaxRetries     = 3
+)
+
+// GetMaxRetries returns how many times transient HTTP failures are retried,
+// or 0 when retries are disabled.
+func (c MCPServerConfig) GetMaxRetries() int {
+	if c.MaxRetries < 0 {
+		return 0
+	}
+	if c.MaxRetries > 0 {
+		return c.MaxRetries
+	}
+	return DefaultMCPServerMaxRetries
+}
+
+// GetTimeoutSeconds returns the per-call and handshake timeout in seconds, or
+// 0 when calls to this server are not bounded.
+func (c MCPServerConfig) GetTimeoutSeconds() int {
+	if c.TimeoutSeconds < 0 {
+		return 0
+	}
+	if c.TimeoutSeconds > 0 {
+		return c.TimeoutSeconds
+	}
+	return DefaultMCPServerTimeoutSeconds
 }
 
 // MCPConfig defines configuration for all MCP servers
@@ -1277,6 +1502,12 @@ type MCPConfig struct {
 	Discovery  ToolDiscoveryConfig `                                json:"discovery"`
 	// MaxInlineTextChars controls how much MCP text stays inline before it is saved as an artifact.
 	MaxInlineTextChars int `json:"max_inline_text_chars,omitempty" env:"PICOCLAW_TOOLS_MCP_MAX_INLINE_TEXT_CHARS"`
+	// HealthCheckInterval is how often, in seconds, stdio servers are pinged and
+	// restarted when they stop responding. Zero uses the default; negative disables.
+	HealthCheckInterval int `json:"health_check_interval_seconds,omitempty" env:"PICOCLAW_TOOLS_MCP_HEALTH_CHECK_INTERVAL_SECONDS"`
+	// AdminTool registers the mcp_admin tool, which lets agents start and stop
+	// configured servers at runtime.
+	AdminTool bool `json:"admin_tool,omitempty" env:"PICOCLAW_TOOLS_MCP_ADMIN_TOOL"`
 	// Servers is a map of server name to server configuration
 	Servers map[string]MCPServerConfig `json:"servers,omitempty"`
 }
@@ -1290,6 +1521,29 @@ func (c *MCPConfig) GetMaxInlineTextChars() int {
 	return DefaultMCPMaxInlineTextChars
 }
 
+// GetServerMaxInlineTextChars returns the inline text limit for a server,
+// preferring its own max_inline_text_chars over the global one.
+func (c *MCPConfig) GetServerMaxInlineTextChars(server MCPServerConfig) int {
+	if server.MaxInlineTextChars > 0 {
+		return server.MaxInlineTextChars
+	}
+	return c.GetMaxInlineTextChars()
+}
+
+const DefaultMCPHealthCheckInterval = 30
+
+// GetHealthCheckInterval returns the stdio health check interval in seconds,
+// or 0 when health checks are disabled.
+func (c *MCPConfig) GetHealthCheckInterval() int {
+	if c.HealthCheckInterval < 0 {
+		return 0
+	}
+	if c.HealthCheckInterval > 0 {
+		return c.HealthCheckInterval
+	}
+	return DefaultMCPHealthCheckInterval
+}
+
 func LoadConfig(path string) (*Config, error) {
 	updateResolver(filepath.Dir(path))
 
@@ -1873,8 +2127,28 @@ func (t *ToolsConfig) IsToolEnabled(name string) bool {
 		return t.MediaCleanup.Enabled
 	case "append_file":
 		return t.AppendFile.Enabled
+	case "write_session":
+		return t.WriteSession.Enabled
+	case "delete_file":
+		return t.DeleteFile.Enabled
+	case "copy_file":
+		return t.CopyFile.Enabled
+	case "archive":
+		return t.Archive.Enabled
+	case "set_permissions":
+		return t.SetPermissions.Enabled
+	case "undo_file":
+		return t.UndoFile.Enabled
+	case "scratch":
+		return t.Scratch.Enabled
+	case "snapshot":
+		return t.Snapshot.Enabled
 	case "edit_file":
 		return t.EditFile.Enabled
+	case "multi_edit":
+		return t.MultiEdit.Enabled
+	case "apply_patch":
+		return t.ApplyPatch.Enabled
 	case "find_skills":
 		return t.FindSkills.Enabled
 	case "i2c":
@@ -1883,6 +2157,16 @@ func (t *ToolsConfig) IsToolEnabled(name string) bool {
 		return t.InstallSkill.Enabled
 	case "list_dir":
 		return t.ListDir.Enabled
+	case "find_files":
+		return t.FindFiles.Enabled
+	case "grep":
+		return t.Grep.Enabled
+	case "file_stat":
+		return t.FileStat.Enabled
+	case "tail_file":
+		return t.TailFile.Enabled
+	case "query_data":
+		return t.QueryData.Enabled
 	case "load_image":
 		return t.LoadImage.Enabled
 	case "message":
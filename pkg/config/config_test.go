@@ -613,7 +613,11 @@ func TestLoadConfig_MCPMaxInlineTextChars(t *testing.T) {
 		"tools": {
 			"mcp": {
 				"enabled": true,
-				"max_inline_text_chars": 2048
+				"max_inline_text_chars": 2048,
+				"servers": {
+					"chatty": {"command": "chatty-mcp", "max_inline_text_chars": 512},
+					"quiet": {"command": "quiet-mcp"}
+				}
 			}
 		}
 	}`
@@ -628,6 +632,12 @@ func TestLoadConfig_MCPMaxInlineTextChars(t *testing.T) {
 	if got := cfg.Tools.MCP.GetMaxInlineTextChars(); got != 2048 {
 		t.Fatalf("cfg.Tools.MCP.GetMaxInlineTextChars() = %d, want 2048", got)
 	}
+	if got := cfg.Tools.MCP.GetServerMaxInlineTextChars(cfg.Tools.MCP.Servers["chatty"]); got != 512 {
+		t.Fatalf("GetServerMaxInlineTextChars(chatty) = %d, want 512", got)
+	}
+	if got := cfg.Tools.MCP.GetServerMaxInlineTextChars(cfg.Tools.MCP.Servers["quiet"]); got != 2048 {
+		t.Fatalf("GetServerMaxInlineTextChars(quiet) = %d, want 2048", got)
+	}
 }
 
 func TestConfig_BackwardCompat_NoAgentsList(t *testing.T) {
@@ -3270,3 +3280,27 @@ func testChannelsConfigWithTokens() ChannelsConfig {
 	}
 	return channels
 }
+
+func TestExecConfig_ActiveProfile(t *testing.T) {
+	profile, err := ExecConfig{}.ActiveProfile()
+	assert.NoError(t, err)
+	assert.Nil(t, profile)
+
+	profile, err = ExecConfig{Profile: ExecProfileReadOnly}.ActiveProfile()
+	assert.NoError(t, err)
+	if assert.NotNil(t, profile) {
+		assert.Contains(t, profile.AllowedCommands, "git status")
+		assert.False(t, profile.AllowRedirects)
+	}
+
+	custom := ExecProfile{AllowedCommands: []string{"ls"}, TimeoutSeconds: 5}
+	profile, err = ExecConfig{
+		Profile:  ExecProfileReadOnly,
+		Profiles: map[string]ExecProfile{ExecProfileReadOnly: custom},
+	}.ActiveProfile()
+	assert.NoError(t, err)
+	assert.Equal(t, &custom, profile)
+
+	_, err = ExecConfig{Profile: "root"}.ActiveProfile()
+	assert.ErrorContains(t, err, `unknown exec profile "root"`)
+}
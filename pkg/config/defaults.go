@@ -436,15 +436,56 @@ func DefaultConfig() *Config {
 					UseBM25:          true,
 					UseRegex:         false,
 				},
-				MaxInlineTextChars: DefaultMCPMaxInlineTextChars,
-				Servers:            map[string]MCPServerConfig{},
+				MaxInlineTextChars:  DefaultMCPMaxInlineTextChars,
+				HealthCheckInterval: DefaultMCPHealthCheckInterval,
+				Servers:             map[string]MCPServerConfig{},
 			},
 			AppendFile: ToolConfig{
 				Enabled: true,
 			},
+			WriteSession: ToolConfig{
+				Enabled: true,
+			},
+			DeleteFile: ToolConfig{
+				Enabled: true,
+			},
+			CopyFile: ToolConfig{
+				Enabled: true,
+			},
+			Archive: ToolConfig{
+				Enabled: true,
+			},
+			SetPermissions: ToolConfig{
+				Enabled: true,
+			},
+			UndoFile: UndoFileToolConfig{
+				ToolConfig: ToolConfig{
+					Enabled: false,
+				},
+				MaxBackups: 10,
+			},
+			Scratch: ScratchToolConfig{
+				ToolConfig: ToolConfig{
+					Enabled: true,
+				},
+				MaxAgeHours: 24,
+				MaxSizeMB:   512,
+			},
+			Snapshot: SnapshotToolConfig{
+				ToolConfig: ToolConfig{
+					Enabled: false,
+				},
+				MaxSnapshots: 5,
+			},
 			EditFile: ToolConfig{
 				Enabled: true,
 			},
+			MultiEdit: ToolConfig{
+				Enabled: true,
+			},
+			ApplyPatch: ToolConfig{
+				Enabled: true,
+			},
 			FindSkills: ToolConfig{
 				Enabled: true,
 			},
@@ -457,6 +498,21 @@ func DefaultConfig() *Config {
 			ListDir: ToolConfig{
 				Enabled: true,
 			},
+			FindFiles: ToolConfig{
+				Enabled: true,
+			},
+			Grep: ToolConfig{
+				Enabled: true,
+			},
+			FileStat: ToolConfig{
+				Enabled: true,
+			},
+			TailFile: ToolConfig{
+				Enabled: true,
+			},
+			QueryData: ToolConfig{
+				Enabled: true,
+			},
 			LoadImage: ToolConfig{
 				Enabled: true,
 			},
@@ -501,6 +557,11 @@ func DefaultConfig() *Config {
 			Enabled:    false,
 			MonitorUSB: true,
 		},
+		Watch: WatchConfig{
+			Enabled:  false,
+			Paths:    []string{},
+			Interval: 5,
+		},
 		Voice: VoiceConfig{
 			ModelName:         "",
 			TTSModelName:      "",
@@ -95,8 +95,14 @@ const (
 	KindMCPServerConnected Kind = "mcp.server.connected"
 	// KindMCPServerConnecting is emitted before connecting to an MCP server.
 	KindMCPServerConnecting Kind = "mcp.server.connecting"
+	// KindMCPServerDisconnected is emitted when an MCP server is shut down at runtime.
+	KindMCPServerDisconnected Kind = "mcp.server.disconnected"
 	// KindMCPServerFailed is emitted when an MCP server fails.
 	KindMCPServerFailed Kind = "mcp.server.failed"
+	// KindMCPServerRestarted is emitted when an unresponsive MCP server is restarted.
+	KindMCPServerRestarted Kind = "mcp.server.restarted"
+	// KindMCPResourceUpdated is emitted when a subscribed MCP resource changes.
+	KindMCPResourceUpdated Kind = "mcp.resource.updated"
 	// KindMCPToolDiscovered is emitted when an MCP tool is discovered.
 	KindMCPToolDiscovered Kind = "mcp.tool.discovered"
 	// KindMCPToolCallStart is emitted when an MCP tool call starts.
@@ -148,7 +154,10 @@ var knownKinds = []Kind{
 	KindGatewayReloadFailed,
 	KindMCPServerConnected,
 	KindMCPServerConnecting,
+	KindMCPServerDisconnected,
 	KindMCPServerFailed,
+	KindMCPServerRestarted,
+	KindMCPResourceUpdated,
 	KindMCPToolDiscovered,
 	KindMCPToolCallStart,
 	KindMCPToolCallEnd,
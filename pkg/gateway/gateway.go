@@ -52,6 +52,7 @@ import (
 	"github.com/sipeed/picoclaw/pkg/providers"
 	"github.com/sipeed/picoclaw/pkg/state"
 	"github.com/sipeed/picoclaw/pkg/tools"
+	"github.com/sipeed/picoclaw/pkg/watch"
 )
 
 const (
@@ -70,6 +71,7 @@ type services struct {
 	MediaStore       media.MediaStore
 	ChannelManager   *channels.Manager
 	DeviceService    *devices.Service
+	WatchService     *watch.Service
 	HealthServer     *health.Server
 	VoiceAgentCancel context.CancelFunc
 	manualReloadChan chan struct{}
@@ -529,9 +531,33 @@ func setupAndStartServices(
 		fmt.Println("✓ Device event service started")
 	}
 
+	runningServices.WatchService = newWatchService(cfg, stateManager, msgBus, agentLoop)
+	if err = runningServices.WatchService.Start(context.Background()); err != nil {
+		logger.ErrorCF("watch", "Error starting file watch service", map[string]any{"error": err.Error()})
+	} else if runningServices.WatchService.Running() {
+		fmt.Println("✓ File watch service started")
+	}
+
 	return runningServices, nil
 }
 
+func newWatchService(
+	cfg *config.Config,
+	stateManager *state.Manager,
+	msgBus *bus.MessageBus,
+	agentLoop *agent.AgentLoop,
+) *watch.Service {
+	service := watch.NewService(watch.Config{
+		Enabled:  cfg.Watch.Enabled,
+		Paths:    cfg.Watch.Paths,
+		Interval: time.Duration(cfg.Watch.Interval) * time.Second,
+	}, cfg.WorkspacePath(), stateManager)
+	service.SetBus(msgBus)
+	service.SetToolEvents(agentLoop.RuntimeEventBus())
+	agentLoop.SetFileWriteObserver(service.NoteWrite)
+	return service
+}
+
 func stopAndCleanupServices(runningServices *services, shutdownTimeout time.Duration, isReload bool) {
 	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), shutdownTimeout)
 	defer shutdownCancel()
@@ -546,6 +572,9 @@ func stopAndCleanupServices(runningServices *services, shutdownTimeout time.Dura
 	if runningServices.DeviceService != nil {
 		runningServices.DeviceService.Stop()
 	}
+	if runningServices.WatchService != nil {
+		runningServices.WatchService.Stop()
+	}
 	if runningServices.HeartbeatService != nil {
 		runningServices.HeartbeatService.Stop()
 	}
@@ -729,6 +758,13 @@ func restartServices(
 		fmt.Println("  ✓ Device event service restarted")
 	}
 
+	runningServices.WatchService = newWatchService(cfg, stateManager, msgBus, al)
+	if err := runningServices.WatchService.Start(context.Background()); err != nil {
+		logger.WarnCF("watch", "Failed to restart file watch service", map[string]any{"error": err.Error()})
+	} else if runningServices.WatchService.Running() {
+		fmt.Println("  ✓ File watch service restarted")
+	}
+
 	transcriber := asr.DetectTranscriber(cfg)
 	al.SetTranscriber(transcriber)
 	if transcriber != nil {
@@ -65,6 +65,7 @@ func mcpServerEventAttrs(payload ServerEventPayload) map[string]any {
 	setMCPAttrString(attrs, "server", payload.Server)
 	setMCPAttrString(attrs, "type", payload.Type)
 	setMCPAttrString(attrs, "tool", payload.Tool)
+	setMCPAttrString(attrs, "resource", payload.Resource)
 	if payload.ToolCount > 0 {
 		attrs["tool_count"] = payload.ToolCount
 	}
@@ -0,0 +1,67 @@
+package mcp
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/sipeed/picoclaw/pkg/config"
+)
+
+// envPlaceholderPattern matches ${VAR} placeholders. Bare $VAR is left alone
+// so arguments that legitimately contain a dollar sign are passed through.
+var envPlaceholderPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// expandServerEnvVars replaces ${VAR} placeholders in the URL, headers, args
+// and env values of a server config with values from the process
+// environment, so secrets can stay out of the config file. It returns an
+// error naming every referenced variable that is not set. The maps and slices
+// of the returned config are copies; cfg is not modified.
+func expandServerEnvVars(name string, cfg config.MCPServerConfig) (config.MCPServerConfig, error) {
+	missing := make(map[string]struct{})
+	expand := func(value string) string {
+		return envPlaceholderPattern.ReplaceAllStringFunc(value, func(placeholder string) string {
+			key := placeholder[2 : len(placeholder)-1]
+			if v, ok := os.LookupEnv(key); ok {
+				return v
+			}
+			missing[key] = struct{}{}
+			return placeholder
+		})
+	}
+
+	cfg.URL = expand(cfg.URL)
+	if cfg.Args != nil {
+		args := make([]string, len(cfg.Args))
+		for i, arg := range cfg.Args {
+			args[i] = expand(arg)
+		}
+		cfg.Args = args
+	}
+	if cfg.Headers != nil {
+		headers := make(map[string]string, len(cfg.Headers))
+		for k, v := range cfg.Headers {
+			headers[k] = expand(v)
+		}
+		cfg.Headers = headers
+	}
+	if cfg.Env != nil {
+		env := make(map[string]string, len(cfg.Env))
+		for k, v := range cfg.Env {
+			env[k] = expand(v)
+		}
+		cfg.Env = env
+	}
+
+	if len(missing) > 0 {
+		return cfg, fmt.Errorf(
+			"server %s references unset environment variable(s): %s",
+			name,
+			strings.Join(slices.Sorted(maps.Keys(missing)), ", "),
+		)
+	}
+	return cfg, nil
+}
@@ -0,0 +1,57 @@
+package mcp
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sipeed/picoclaw/pkg/config"
+)
+
+func TestExpandServerEnvVars(t *testing.T) {
+	t.Setenv("PICOCLAW_TEST_MCP_TOKEN", "secret-token")
+	t.Setenv("PICOCLAW_TEST_MCP_HOST", "mcp.example.com")
+
+	cfg := config.MCPServerConfig{
+		URL:     "https://${PICOCLAW_TEST_MCP_HOST}/mcp",
+		Args:    []string{"--token=${PICOCLAW_TEST_MCP_TOKEN}", "cost=$5"},
+		Headers: map[string]string{"Authorization": "Bearer ${PICOCLAW_TEST_MCP_TOKEN}"},
+		Env:     map[string]string{"API_KEY": "${PICOCLAW_TEST_MCP_TOKEN}"},
+	}
+
+	got, err := expandServerEnvVars("remote", cfg)
+	if err != nil {
+		t.Fatalf("expandServerEnvVars() error = %v", err)
+	}
+	if got.URL != "https://mcp.example.com/mcp" {
+		t.Errorf("URL = %q", got.URL)
+	}
+	if got.Args[0] != "--token=secret-token" || got.Args[1] != "cost=$5" {
+		t.Errorf("Args = %v", got.Args)
+	}
+	if got.Headers["Authorization"] != "Bearer secret-token" {
+		t.Errorf("Authorization header = %q", got.Headers["Authorization"])
+	}
+	if got.Env["API_KEY"] != "secret-token" {
+		t.Errorf("Env[API_KEY] = %q", got.Env["API_KEY"])
+	}
+	if cfg.Headers["Authorization"] != "Bearer ${PICOCLAW_TEST_MCP_TOKEN}" {
+		t.Errorf("input config was modified: %q", cfg.Headers["Authorization"])
+	}
+}
+
+func TestExpandServerEnvVars_ReportsUnsetVariables(t *testing.T) {
+	cfg := config.MCPServerConfig{
+		URL:     "https://${PICOCLAW_TEST_MCP_MISSING_HOST}/mcp",
+		Headers: map[string]string{"X-Api-Key": "${PICOCLAW_TEST_MCP_MISSING_KEY}"},
+	}
+
+	_, err := expandServerEnvVars("remote", cfg)
+	if err == nil {
+		t.Fatal("expected error for unset variables")
+	}
+	for _, name := range []string{"PICOCLAW_TEST_MCP_MISSING_HOST", "PICOCLAW_TEST_MCP_MISSING_KEY"} {
+		if !strings.Contains(err.Error(), name) {
+			t.Errorf("error %q does not mention %s", err, name)
+		}
+	}
+}
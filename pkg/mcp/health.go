@@ -0,0 +1,98 @@
+package mcp
+
+import (
+	"context"
+	"time"
+
+	"github.com/sipeed/picoclaw/pkg/config"
+	runtimeevents "github.com/sipeed/picoclaw/pkg/events"
+	"github.com/sipeed/picoclaw/pkg/logger"
+)
+
+// healthCheckTimeout bounds a single ping so a wedged server is detected
+// within one interval instead of stalling the health loop.
+var healthCheckTimeout = 10 * time.Second
+
+// StartHealthChecks pings connected stdio servers every interval and restarts
+// any server that stops responding. Registered MCP tools keep working across
+// a restart because they resolve the live session through the manager by
+// server name; if the restarted server exposes other tools, the tools changed
+// handler is called to re-register them. Only the first call starts the loop;
+// it stops on Close.
+func (m *Manager) StartHealthChecks(interval time.Duration) {
+	if interval <= 0 || m.lifetime == nil {
+		return
+	}
+	m.healthOnce.Do(func() {
+		logger.DebugCF("mcp", "Starting MCP health checks",
+			map[string]any{
+				"interval": interval.String(),
+			})
+		go m.runHealthChecks(interval)
+	})
+}
+
+func (m *Manager) runHealthChecks(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.lifetime.Done():
+			return
+		case <-ticker.C:
+			m.checkServersHealth()
+		}
+	}
+}
+
+func (m *Manager) checkServersHealth() {
+	for name, conn := range m.GetServers() {
+		if m.closed.Load() {
+			return
+		}
+		if conn == nil || conn.Session == nil {
+			continue
+		}
+		// Remote servers recover lazily on the next call when their session is
+		// lost; only child processes can die without anyone noticing.
+		if config.EffectiveMCPTransportType(conn.Config) != "stdio" {
+			continue
+		}
+		m.checkServerHealth(name, conn)
+	}
+}
+
+func (m *Manager) checkServerHealth(name string, conn *ServerConnection) {
+	pingCtx, cancel := context.WithTimeout(m.lifetime, healthCheckTimeout)
+	err := conn.Session.Ping(pingCtx, nil)
+	cancel()
+	if err == nil || m.closed.Load() {
+		return
+	}
+
+	logger.WarnCF("mcp", "MCP server failed health check, restarting",
+		map[string]any{
+			"server": name,
+			"error":  err.Error(),
+		})
+
+	// The restarted process is bound to the manager lifetime rather than a
+	// request context, so it keeps running after this check returns.
+	freshConn, err := m.reconnectServer(m.lifetime, name, conn)
+	if err != nil {
+		logger.ErrorCF("mcp", "Failed to restart MCP server; will retry on next health check",
+			map[string]any{
+				"server": name,
+				"error":  err.Error(),
+			})
+		m.publishServerEvent(runtimeevents.KindMCPServerFailed, name, conn.Config, 0, err)
+		return
+	}
+
+	logger.InfoCF("mcp", "Restarted MCP server",
+		map[string]any{
+			"server": name,
+		})
+	m.publishServerEvent(runtimeevents.KindMCPServerRestarted, name, freshConn.Config, len(freshConn.Tools), nil)
+}
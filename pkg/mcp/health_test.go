@@ -0,0 +1,147 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	sdkmcp "github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/sipeed/picoclaw/pkg/config"
+	runtimeevents "github.com/sipeed/picoclaw/pkg/events"
+)
+
+func TestCheckServersHealth_RestartsUnresponsiveStdioServer(t *testing.T) {
+	originalConnectServerFunc := connectServerFunc
+	t.Cleanup(func() {
+		connectServerFunc = originalConnectServerFunc
+	})
+
+	// The scripted transport rejects "ping", which models a wedged child process.
+	staleConn, _, err := newScriptedServerConnection("stale", nil, nil)
+	if err != nil {
+		t.Fatalf("newScriptedServerConnection(stale) error = %v", err)
+	}
+	staleConn.Config = config.MCPServerConfig{Enabled: true, Type: "stdio", Command: "server"}
+
+	freshConn, _, err := newScriptedServerConnection("fresh", nil, nil)
+	if err != nil {
+		t.Fatalf("newScriptedServerConnection(fresh) error = %v", err)
+	}
+	freshConn.Config = staleConn.Config
+
+	connectCalls := 0
+	connectServerFunc = func(ctx context.Context, name string, cfg config.MCPServerConfig) (*ServerConnection, error) {
+		connectCalls++
+		return freshConn, nil
+	}
+
+	eventBus := runtimeevents.NewBus()
+	defer func() {
+		if err := eventBus.Close(); err != nil {
+			t.Errorf("event bus close failed: %v", err)
+		}
+	}()
+	_, eventsCh, err := eventBus.Channel().OfKind(
+		runtimeevents.KindMCPServerRestarted,
+	).SubscribeChan(t.Context(), runtimeevents.SubscribeOptions{Name: "mcp-health", Buffer: 1})
+	if err != nil {
+		t.Fatalf("SubscribeChan failed: %v", err)
+	}
+
+	mgr := NewManager(WithRuntimeEvents(eventBus))
+	defer mgr.Close()
+	mgr.servers["flaky"] = staleConn
+
+	mgr.checkServersHealth()
+
+	if connectCalls != 1 {
+		t.Fatalf("connectCalls = %d, want 1", connectCalls)
+	}
+	conn, ok := mgr.GetServer("flaky")
+	if !ok || conn != freshConn {
+		t.Fatal("expected unresponsive server to be replaced by the restarted connection")
+	}
+	restarted := receiveMCPRuntimeEvent(t, eventsCh)
+	if restarted.Source.Name != "flaky" {
+		t.Fatalf("restarted event = %+v", restarted)
+	}
+}
+
+func TestCheckServersHealth_ReportsChangedToolSet(t *testing.T) {
+	originalConnectServerFunc := connectServerFunc
+	t.Cleanup(func() {
+		connectServerFunc = originalConnectServerFunc
+	})
+
+	staleConn, _, err := newScriptedServerConnection("stale", nil, nil)
+	if err != nil {
+		t.Fatalf("newScriptedServerConnection(stale) error = %v", err)
+	}
+	staleConn.Config = config.MCPServerConfig{Enabled: true, Type: "stdio", Command: "server"}
+	freshConn, _, err := newScriptedServerConnection("fresh", nil, nil)
+	if err != nil {
+		t.Fatalf("newScriptedServerConnection(fresh) error = %v", err)
+	}
+	freshConn.Config = staleConn.Config
+	freshConn.Tools = append(freshConn.Tools, &sdkmcp.Tool{
+		Name:        "reverse",
+		InputSchema: map[string]any{"type": "object"},
+	})
+	connectServerFunc = func(ctx context.Context, name string, cfg config.MCPServerConfig) (*ServerConnection, error) {
+		return freshConn, nil
+	}
+
+	var changed []string
+	mgr := NewManager(WithToolsChangedHandler(func(serverName string, stale, fresh *ServerConnection) {
+		if stale != staleConn || fresh != freshConn {
+			t.Errorf("handler got unexpected connections")
+		}
+		changed = append(changed, serverName)
+	}))
+	defer mgr.Close()
+	mgr.servers["flaky"] = staleConn
+
+	mgr.checkServersHealth()
+
+	if len(changed) != 1 || changed[0] != "flaky" {
+		t.Fatalf("tools changed handler calls = %v, want [flaky]", changed)
+	}
+}
+
+func TestSameTools(t *testing.T) {
+	a := []*sdkmcp.Tool{{Name: "echo", InputSchema: map[string]any{"type": "object"}}}
+	b := []*sdkmcp.Tool{{Name: "echo", InputSchema: map[string]any{"type": "object"}}}
+	if !sameTools(a, b) {
+		t.Fatal("expected identical tool lists to match")
+	}
+	b[0].Description = "now documented"
+	if sameTools(a, b) {
+		t.Fatal("expected a changed description to count as a different tool set")
+	}
+}
+
+func TestCheckServersHealth_SkipsRemoteServers(t *testing.T) {
+	originalConnectServerFunc := connectServerFunc
+	t.Cleanup(func() {
+		connectServerFunc = originalConnectServerFunc
+	})
+	connectServerFunc = func(ctx context.Context, name string, cfg config.MCPServerConfig) (*ServerConnection, error) {
+		return nil, fmt.Errorf("unexpected reconnect of %s", name)
+	}
+
+	remoteConn, _, err := newScriptedServerConnection("remote", nil, nil)
+	if err != nil {
+		t.Fatalf("newScriptedServerConnection(remote) error = %v", err)
+	}
+
+	mgr := NewManager()
+	defer mgr.Close()
+	mgr.servers["remote"] = remoteConn
+
+	mgr.checkServersHealth()
+
+	if conn, _ := mgr.GetServer("remote"); conn != remoteConn {
+		t.Fatal("expected remote server connection to be left alone")
+	}
+}
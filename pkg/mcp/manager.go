@@ -9,9 +9,12 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"reflect"
+	"slices"
 	"strings"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 
@@ -118,12 +121,18 @@ func loadEnvFile(path string) (map[string]string, error) {
 
 // ServerConnection represents a connection to an MCP server
 type ServerConnection struct {
-	Name        string
-	Config      config.MCPServerConfig
-	Client      *mcp.Client
-	Session     *mcp.ClientSession
-	Tools       []*mcp.Tool
-	reconnectMu sync.Mutex
+	Name    string
+	Config  config.MCPServerConfig
+	Client  *mcp.Client
+	Session *mcp.ClientSession
+	Tools   []*mcp.Tool
+	// ProtocolVersion is the MCP protocol version negotiated at initialize.
+	ProtocolVersion string
+	reconnectMu     sync.Mutex
+	traffic         *trafficLog
+	stderr          *stderrLog
+	// resourceUpdates is nil for connections not created by connectServer.
+	resourceUpdates *resourceUpdateHook
 }
 
 // Manager manages multiple MCP server connections
@@ -133,10 +142,28 @@ type Manager struct {
 	mu            sync.RWMutex
 	closed        atomic.Bool    // changed from bool to atomic.Bool to avoid TOCTOU race
 	wg            sync.WaitGroup // tracks in-flight CallTool calls
+
+	// lifetime outlives individual calls so background restarts are not tied
+	// to a request context; it is canceled by Close.
+	lifetime       context.Context
+	cancelLifetime context.CancelFunc
+	healthOnce     sync.Once
+
+	subsMu            sync.Mutex
+	subscriptions     map[string]map[string]struct{} // server -> resource URIs subscribed at runtime
+	onResourceUpdated func(serverName, uri string)
+	onToolsChanged    func(serverName string, stale, fresh *ServerConnection)
+
+	metrics metricsRecorder
 }
 
 var connectServerFunc = connectServer
 
+// maxConcurrentServerConnects bounds how many MCP servers are started at once
+// during LoadFromMCPConfig, so cold-starting many npx/uvx servers overlaps
+// without spawning every process simultaneously.
+var maxConcurrentServerConnects = 4
+
 // ManagerOption configures an MCP manager.
 type ManagerOption func(*Manager)
 
@@ -147,6 +174,24 @@ func WithRuntimeEvents(eventBus runtimeevents.Bus) ManagerOption {
 	}
 }
 
+// WithResourceUpdateHandler sets a function called for every update to a
+// subscribed resource, in addition to the runtime event. It runs on the
+// session's notification goroutine and must not block.
+func WithResourceUpdateHandler(onUpdate func(serverName, uri string)) ManagerOption {
+	return func(m *Manager) {
+		m.onResourceUpdated = onUpdate
+	}
+}
+
+// WithToolsChangedHandler sets a function called when a server reconnected
+// after a crash or a lost session reports different tools than before, so the
+// caller can replace the tools it registered for the stale connection.
+func WithToolsChangedHandler(onChange func(serverName string, stale, fresh *ServerConnection)) ManagerOption {
+	return func(m *Manager) {
+		m.onToolsChanged = onChange
+	}
+}
+
 // ServerEventPayload describes MCP server connection events.
 type ServerEventPayload struct {
 	Server    string `json:"server"`
@@ -154,14 +199,18 @@ type ServerEventPayload struct {
 	URL       string `json:"url,omitempty"`
 	Command   string `json:"command,omitempty"`
 	Tool      string `json:"tool,omitempty"`
+	Resource  string `json:"resource,omitempty"`
 	ToolCount int    `json:"tool_count,omitempty"`
 	Error     string `json:"error,omitempty"`
 }
 
 // NewManager creates a new MCP manager
 func NewManager(opts ...ManagerOption) *Manager {
+	lifetime, cancel := context.WithCancel(context.Background())
 	m := &Manager{
-		servers: make(map[string]*ServerConnection),
+		servers:        make(map[string]*ServerConnection),
+		lifetime:       lifetime,
+		cancelLifetime: cancel,
 	}
 	for _, opt := range opts {
 		if opt != nil {
@@ -200,6 +249,7 @@ func (m *Manager) LoadFromMCPConfig(
 
 	var wg sync.WaitGroup
 	errs := make(chan error, len(mcpCfg.Servers))
+	sem := make(chan struct{}, max(maxConcurrentServerConnects, 1))
 	enabledCount := 0
 
 	for name, serverCfg := range mcpCfg.Servers {
@@ -216,24 +266,18 @@ func (m *Manager) LoadFromMCPConfig(
 		go func(name string, serverCfg config.MCPServerConfig, workspace string) {
 			defer wg.Done()
 
-			// Resolve relative envFile paths relative to workspace
-			if serverCfg.EnvFile != "" && !filepath.IsAbs(serverCfg.EnvFile) {
-				if workspace == "" {
-					err := fmt.Errorf(
-						"workspace path is empty while resolving relative envFile %q for server %s",
-						serverCfg.EnvFile,
-						name,
-					)
-					logger.ErrorCF("mcp", "Invalid MCP server configuration",
-						map[string]any{
-							"server":   name,
-							"env_file": serverCfg.EnvFile,
-							"error":    err.Error(),
-						})
-					errs <- err
-					return
-				}
-				serverCfg.EnvFile = filepath.Join(workspace, serverCfg.EnvFile)
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				errs <- fmt.Errorf("failed to connect to server %s: %w", name, ctx.Err())
+				return
+			}
+
+			serverCfg, err := resolveServerConfig(name, serverCfg, workspace)
+			if err != nil {
+				errs <- err
+				return
 			}
 
 			if err := m.ConnectServer(ctx, name, serverCfg); err != nil {
@@ -287,23 +331,110 @@ func (m *Manager) LoadFromMCPConfig(
 	return nil
 }
 
-// ConnectServer connects to a single MCP server
+// resolveServerConfig expands ${VAR} placeholders and resolves
+// workspace-relative paths in a server config.
+func resolveServerConfig(
+	name string,
+	serverCfg config.MCPServerConfig,
+	workspace string,
+) (config.MCPServerConfig, error) {
+	// Resolve relative envFile paths relative to workspace
+	if serverCfg.EnvFile != "" && !filepath.IsAbs(serverCfg.EnvFile) {
+		if workspace == "" {
+			err := fmt.Errorf(
+				"workspace path is empty while resolving relative envFile %q for server %s",
+				serverCfg.EnvFile,
+				name,
+			)
+			logger.ErrorCF("mcp", "Invalid MCP server configuration",
+				map[string]any{
+					"server":   name,
+					"env_file": serverCfg.EnvFile,
+					"error":    err.Error(),
+				})
+			return serverCfg, err
+		}
+		serverCfg.EnvFile = filepath.Join(workspace, serverCfg.EnvFile)
+	}
+	serverCfg.Roots = resolveServerRoots(serverCfg.Roots, workspace)
+
+	serverCfg, err := expandServerEnvVars(name, serverCfg)
+	if err != nil {
+		logger.ErrorCF("mcp", "Invalid MCP server configuration",
+			map[string]any{
+				"server": name,
+				"error":  err.Error(),
+			})
+		return serverCfg, err
+	}
+	return serverCfg, nil
+}
+
+// StartServer connects a server at runtime, resolving workspace-relative
+// paths the same way LoadFromMCPConfig does. It fails if a server with the
+// same name is already connected.
+func (m *Manager) StartServer(
+	ctx context.Context,
+	name string,
+	cfg config.MCPServerConfig,
+	workspacePath string,
+) error {
+	if _, ok := m.GetServer(name); ok {
+		return fmt.Errorf("server %s is already connected", name)
+	}
+	cfg, err := resolveServerConfig(name, cfg, workspacePath)
+	if err != nil {
+		return err
+	}
+	return m.ConnectServer(ctx, name, cfg)
+}
+
+// DisconnectServer shuts down a single server at runtime and forgets it.
+// In-flight calls to the server fail once its session is closed.
+func (m *Manager) DisconnectServer(name string) error {
+	m.mu.Lock()
+	conn, ok := m.servers[name]
+	if ok {
+		delete(m.servers, name)
+	}
+	m.mu.Unlock()
+
+	if !ok {
+		return fmt.Errorf("server %s not found", name)
+	}
+
+	logger.InfoCF("mcp", "Disconnecting MCP server",
+		map[string]any{
+			"server": name,
+		})
+	m.forgetResourceSubscriptions(name)
+	err := conn.Session.Close()
+	m.publishServerEvent(runtimeevents.KindMCPServerDisconnected, name, conn.Config, 0, err)
+	if err != nil {
+		return fmt.Errorf("failed to close server %s: %w", name, err)
+	}
+	return nil
+}
+
+// ConnectServer connects to a single MCP server. The server process is bound
+// to the manager lifetime, not to ctx: ctx only bounds how long the caller
+// waits for the connection, so a server started from a tool call keeps
+// running after the turn that started it ends.
 func (m *Manager) ConnectServer(
 	ctx context.Context,
 	name string,
 	cfg config.MCPServerConfig,
 ) error {
 	m.publishServerEvent(runtimeevents.KindMCPServerConnecting, name, cfg, 0, nil)
-	conn, err := connectServerFunc(ctx, name, cfg)
+	conn, err := m.connectDetached(ctx, name, cfg)
 	if err != nil {
 		m.publishServerEvent(runtimeevents.KindMCPServerFailed, name, cfg, 0, err)
 		return err
 	}
 
 	m.mu.Lock()
-	defer m.mu.Unlock()
-
 	if m.closed.Load() {
+		m.mu.Unlock()
 		_ = conn.Session.Close()
 		m.publishServerEvent(runtimeevents.KindMCPServerFailed, name, cfg, 0, fmt.Errorf("manager is closed"))
 		return fmt.Errorf("manager is closed")
@@ -318,9 +449,44 @@ func (m *Manager) ConnectServer(
 		m.publishToolDiscovered(name, cfg, toolName)
 	}
 	m.publishServerEvent(runtimeevents.KindMCPServerConnected, name, cfg, len(conn.Tools), nil)
+	m.mu.Unlock()
+
+	m.subscribeResources(ctx, name, conn)
 	return nil
 }
 
+// connectDetached runs connectServerFunc under the manager lifetime and waits
+// for it until ctx is done. A connection that completes after the caller gave
+// up is closed rather than leaked.
+func (m *Manager) connectDetached(
+	ctx context.Context,
+	name string,
+	cfg config.MCPServerConfig,
+) (*ServerConnection, error) {
+	type connectResult struct {
+		conn *ServerConnection
+		err  error
+	}
+	done := make(chan connectResult, 1)
+	connect := connectServerFunc
+	go func() {
+		conn, err := connect(m.lifetime, name, cfg)
+		done <- connectResult{conn: conn, err: err}
+	}()
+
+	select {
+	case res := <-done:
+		return res.conn, res.err
+	case <-ctx.Done():
+		go func() {
+			if res := <-done; res.conn != nil {
+				_ = res.conn.Session.Close()
+			}
+		}()
+		return nil, fmt.Errorf("failed to connect: %w", ctx.Err())
+	}
+}
+
 func connectServer(
 	ctx context.Context,
 	name string,
@@ -333,20 +499,35 @@ func connectServer(
 			"args_count": len(cfg.Args),
 		})
 
-	// Create client
-	client := mcp.NewClient(&mcp.Implementation{
-		Name:    "picoclaw",
-		Version: "1.0.0",
-	}, nil)
+	if err := validateToolPatterns(cfg); err != nil {
+		return nil, err
+	}
 
 	// Create transport based on configuration
 	// Auto-detect transport type if not explicitly specified
 	var transport mcp.Transport
+	var stderr *stderrLog
 	transportType := config.EffectiveMCPTransportType(cfg)
 	if transportType == "" {
 		return nil, fmt.Errorf("either URL or command must be provided")
 	}
 
+	// Create client
+	resourceUpdates := &resourceUpdateHook{}
+	clientOpts := &mcp.ClientOptions{ResourceUpdatedHandler: resourceUpdates.handle}
+	if cfg.KeepAliveSeconds > 0 && transportType != "stdio" {
+		// The SDK closes the session when a keep-alive ping goes unanswered;
+		// the next tool call then reconnects through reconnectServer.
+		clientOpts.KeepAlive = time.Duration(cfg.KeepAliveSeconds) * time.Second
+	}
+	client := mcp.NewClient(&mcp.Implementation{
+		Name:    "picoclaw",
+		Version: "1.0.0",
+	}, clientOpts)
+	if roots := rootsFromPaths(cfg.Roots); len(roots) > 0 {
+		client.AddRoots(roots...)
+	}
+
 	switch transportType {
 	case "sse", "http":
 		if cfg.URL == "" {
@@ -374,14 +555,13 @@ func connectServer(
 			DisableStandaloneSSE: disableStandaloneSSE,
 		}
 
+		var roundTripper http.RoundTripper = http.DefaultTransport
+
 		// Add custom headers if provided
 		if len(cfg.Headers) > 0 {
-			// Create a custom HTTP client with header-injecting transport
-			sseTransport.HTTPClient = &http.Client{
-				Transport: &headerTransport{
-					base:    http.DefaultTransport,
-					headers: cfg.Headers,
-				},
+			roundTripper = &headerTransport{
+				base:    roundTripper,
+				headers: cfg.Headers,
 			}
 			logger.DebugCF("mcp", "Added custom HTTP headers",
 				map[string]any{
@@ -389,6 +569,16 @@ func connectServer(
 					"header_count": len(cfg.Headers),
 				})
 		}
+		if maxRetries := cfg.GetMaxRetries(); maxRetries > 0 {
+			roundTripper = &retryTransport{
+				base:       roundTripper,
+				server:     name,
+				maxRetries: maxRetries,
+			}
+		}
+		if roundTripper != http.DefaultTransport {
+			sseTransport.HTTPClient = &http.Client{Transport: roundTripper}
+		}
 
 		transport = sseTransport
 	case "stdio":
@@ -442,6 +632,10 @@ func connectServer(
 			env = append(env, fmt.Sprintf("%s=%s", k, v))
 		}
 		cmd.Env = env
+		stderr = newStderrLog(name, cfg.StderrLogLevel)
+		cmd.Stderr = stderr
+		// Don't let a grandchild holding stderr open block process shutdown.
+		cmd.WaitDelay = isolatedCommandTerminateDuration
 		transport = &isolatedCommandTransport{Command: cmd}
 	default:
 		return nil, fmt.Errorf(
@@ -450,13 +644,47 @@ func connectServer(
 		)
 	}
 
+	var traffic *trafficLog
+	if cfg.DebugTraffic {
+		traffic = newTrafficLog(trafficLogSize)
+		transport = &trafficTransport{transport: transport, log: traffic}
+	}
+
+	// Bound the handshake so a server that never answers initialize or
+	// tools/list cannot block the caller. The session itself is not tied to
+	// this deadline.
+	handshakeCtx := ctx
+	if timeout := cfg.GetTimeoutSeconds(); timeout > 0 {
+		var cancel context.CancelFunc
+		handshakeCtx, cancel = context.WithTimeout(ctx, time.Duration(timeout)*time.Second)
+		defer cancel()
+	}
+
 	// Connect to server
-	session, err := client.Connect(ctx, transport, nil)
+	session, err := client.Connect(handshakeCtx, transport, nil)
 	if err != nil {
+		if stderr != nil {
+			if tail := stderr.Tail(); len(tail) > 0 {
+				logger.WarnCF("mcp", "MCP server stderr before connection failure",
+					map[string]any{
+						"server": name,
+						"stderr": strings.Join(tail, "\n"),
+					})
+			}
+		}
+		if isUnsupportedProtocolVersionError(err) {
+			return nil, fmt.Errorf(
+				"failed to connect: server answered initialize with an MCP protocol version picoclaw "+
+					"does not support; update picoclaw or configure the server for an older protocol: %w",
+				err,
+			)
+		}
 		return nil, fmt.Errorf("failed to connect: %w", err)
 	}
 
-	// Get server info
+	// Get server info. The SDK negotiates the protocol version during
+	// initialize and adapts its transport (e.g. the MCP-Protocol-Version
+	// header) to it; the result is recorded for status output.
 	initResult := session.InitializeResult()
 	logger.InfoCF("mcp", "Connected to MCP server",
 		map[string]any{
@@ -467,21 +695,33 @@ func connectServer(
 		})
 
 	// List available tools if supported
-	tools, err := listServerTools(ctx, name, session, initResult)
+	tools, err := listServerTools(handshakeCtx, name, session, initResult)
 	if err != nil {
 		_ = session.Close()
 		return nil, err
 	}
+	tools = filterServerTools(name, cfg, tools)
 
 	return &ServerConnection{
-		Name:    name,
-		Config:  cfg,
-		Client:  client,
-		Session: session,
-		Tools:   tools,
+		Name:            name,
+		Config:          cfg,
+		Client:          client,
+		Session:         session,
+		Tools:           tools,
+		ProtocolVersion: initResult.ProtocolVersion,
+		traffic:         traffic,
+		stderr:          stderr,
+		resourceUpdates: resourceUpdates,
 	}, nil
 }
 
+// isUnsupportedProtocolVersionError reports whether connecting failed because
+// the server negotiated a protocol version the SDK does not implement. The
+// SDK does not export its error type, so the message is matched instead.
+func isUnsupportedProtocolVersionError(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "unsupported protocol version")
+}
+
 // GetServers returns all connected servers
 func (m *Manager) GetServers() map[string]*ServerConnection {
 	m.mu.RLock()
@@ -503,11 +743,48 @@ func (m *Manager) GetServer(name string) (*ServerConnection, bool) {
 	return conn, ok
 }
 
-// CallTool calls a tool on a specific server
+// ServerTraffic returns the recent JSON-RPC messages recorded for a server,
+// oldest first. Recording must be enabled with debug_traffic.
+func (m *Manager) ServerTraffic(name string) ([]TrafficEntry, error) {
+	conn, ok := m.GetServer(name)
+	if !ok {
+		return nil, fmt.Errorf("server %s not found", name)
+	}
+	if conn.traffic == nil {
+		return nil, fmt.Errorf("traffic recording is disabled for server %s (set debug_traffic)", name)
+	}
+	return conn.traffic.snapshot(), nil
+}
+
+// ServerStderr returns the most recent stderr lines of a stdio server,
+// oldest first.
+func (m *Manager) ServerStderr(name string) ([]string, error) {
+	conn, ok := m.GetServer(name)
+	if !ok {
+		return nil, fmt.Errorf("server %s not found", name)
+	}
+	if conn.stderr == nil {
+		return nil, fmt.Errorf("server %s is not a stdio server", name)
+	}
+	return conn.stderr.Tail(), nil
+}
+
+// CallTool calls a tool on a specific server and records its metrics.
 func (m *Manager) CallTool(
 	ctx context.Context,
 	serverName, toolName string,
 	arguments map[string]any,
+) (*mcp.CallToolResult, error) {
+	start := time.Now()
+	result, err := m.callTool(ctx, serverName, toolName, arguments)
+	m.metrics.record(serverName, toolName, time.Since(start), arguments, result, err)
+	return result, err
+}
+
+func (m *Manager) callTool(
+	ctx context.Context,
+	serverName, toolName string,
+	arguments map[string]any,
 ) (*mcp.CallToolResult, error) {
 	// Check if closed before acquiring lock (fast path)
 	if m.closed.Load() {
@@ -536,33 +813,57 @@ func (m *Manager) CallTool(
 		Arguments: arguments,
 	}
 
-	result, err := conn.Session.CallTool(ctx, params)
-	if err != nil {
-		if shouldReconnectCallError(err) {
-			logger.WarnCF("mcp", "MCP server session was lost during tool call, reconnecting",
-				map[string]any{
-					"server": serverName,
-					"tool":   toolName,
-					"error":  err.Error(),
-				})
-
-			reconnectedConn, reconnectErr := m.reconnectServer(ctx, serverName, conn)
-			if reconnectErr != nil {
-				return nil, fmt.Errorf("failed to recover lost MCP session: %w", reconnectErr)
-			}
+	result, err := callSessionTool(ctx, conn, params)
+	if err != nil && shouldReconnectCallError(err) {
+		logger.WarnCF("mcp", "MCP server session was lost during tool call, reconnecting",
+			map[string]any{
+				"server": serverName,
+				"tool":   toolName,
+				"error":  err.Error(),
+			})
 
-			result, err = reconnectedConn.Session.CallTool(ctx, params)
-			if err == nil {
-				return result, nil
-			}
+		// The restarted process is bound to the manager lifetime, like the
+		// health check's restarts, so it survives the end of this call. The
+		// handshake has its own timeout.
+		reconnectedConn, reconnectErr := m.reconnectServer(m.lifetime, serverName, conn)
+		if reconnectErr != nil {
+			return nil, fmt.Errorf("failed to recover lost MCP session: %w", reconnectErr)
 		}
 
-		return nil, fmt.Errorf("failed to call tool: %w", err)
+		result, err = callSessionTool(ctx, reconnectedConn, params)
+	}
+	if err != nil {
+		return nil, err
 	}
-
 	return result, nil
 }
 
+// callSessionTool calls a tool on conn's session. The call is bounded so a
+// hung server cannot stall the agent turn when the caller's context has no
+// deadline of its own.
+func callSessionTool(
+	ctx context.Context,
+	conn *ServerConnection,
+	params *mcp.CallToolParams,
+) (*mcp.CallToolResult, error) {
+	callCtx := ctx
+	timeout := conn.Config.GetTimeoutSeconds()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		callCtx, cancel = context.WithTimeout(ctx, time.Duration(timeout)*time.Second)
+		defer cancel()
+	}
+
+	result, err := conn.Session.CallTool(callCtx, params)
+	if err == nil {
+		return result, nil
+	}
+	if errors.Is(callCtx.Err(), context.DeadlineExceeded) && ctx.Err() == nil {
+		return nil, fmt.Errorf("tool call timed out after %ds: %w", timeout, callCtx.Err())
+	}
+	return nil, fmt.Errorf("failed to call tool: %w", err)
+}
+
 func listServerTools(
 	ctx context.Context,
 	name string,
@@ -599,7 +900,7 @@ func shouldReconnectCallError(err error) bool {
 	if err == nil {
 		return false
 	}
-	if errors.Is(err, mcp.ErrSessionMissing) {
+	if errors.Is(err, mcp.ErrSessionMissing) || errors.Is(err, mcp.ErrConnectionClosed) {
 		return true
 	}
 	return strings.Contains(strings.ToLower(err.Error()), mcp.ErrSessionMissing.Error())
@@ -655,6 +956,16 @@ func (m *Manager) reconnectServer(
 		staleToClose := staleConn
 		m.mu.Unlock()
 		_ = staleToClose.Session.Close()
+		m.subscribeResources(ctx, serverName, freshConn)
+		if m.onToolsChanged != nil && !sameTools(staleConn.Tools, freshConn.Tools) {
+			logger.InfoCF("mcp", "Reconnected MCP server reports a different tool set",
+				map[string]any{
+					"server":       serverName,
+					"tools_before": len(staleConn.Tools),
+					"tools_after":  len(freshConn.Tools),
+				})
+			m.onToolsChanged(serverName, staleConn, freshConn)
+		}
 		return freshConn, nil
 	}
 
@@ -663,6 +974,14 @@ func (m *Manager) reconnectServer(
 	return currentConn, nil
 }
 
+// sameTools reports whether two tool lists declare the same tools with the
+// same descriptions and schemas, in the same order.
+func sameTools(a, b []*mcp.Tool) bool {
+	return slices.EqualFunc(a, b, func(x, y *mcp.Tool) bool {
+		return reflect.DeepEqual(x, y)
+	})
+}
+
 // Close closes all server connections
 func (m *Manager) Close() error {
 	// Use Swap to atomically set closed=true and get the previous value
@@ -670,6 +989,9 @@ func (m *Manager) Close() error {
 	if m.closed.Swap(true) {
 		return nil // already closed
 	}
+	if m.cancelLifetime != nil {
+		m.cancelLifetime()
+	}
 
 	// Wait for all in-flight CallTool calls to finish before closing sessions
 	// After closed=true is set, no new CallTool can start (they check closed first)
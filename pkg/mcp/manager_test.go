@@ -3,12 +3,14 @@ package mcp
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"sync"
 	"testing"
@@ -242,6 +244,60 @@ func TestLoadFromMCPConfig_EmptyWorkspaceWithRelativeEnvFile(t *testing.T) {
 	}
 }
 
+func TestLoadFromMCPConfig_BoundsConcurrentConnects(t *testing.T) {
+	originalConnectServerFunc := connectServerFunc
+	originalMaxConnects := maxConcurrentServerConnects
+	t.Cleanup(func() {
+		connectServerFunc = originalConnectServerFunc
+		maxConcurrentServerConnects = originalMaxConnects
+	})
+	maxConcurrentServerConnects = 2
+
+	var mu sync.Mutex
+	inFlight, peak := 0, 0
+	connectServerFunc = func(ctx context.Context, name string, cfg config.MCPServerConfig) (*ServerConnection, error) {
+		mu.Lock()
+		inFlight++
+		peak = max(peak, inFlight)
+		mu.Unlock()
+		defer func() {
+			mu.Lock()
+			inFlight--
+			mu.Unlock()
+		}()
+
+		time.Sleep(20 * time.Millisecond)
+		if name == "broken" {
+			return nil, fmt.Errorf("boom")
+		}
+		conn, _, err := newScriptedServerConnection(name, nil, nil)
+		return conn, err
+	}
+
+	servers := map[string]config.MCPServerConfig{
+		"broken": {Enabled: true, Command: "server"},
+	}
+	for i := range 5 {
+		servers[fmt.Sprintf("server-%d", i)] = config.MCPServerConfig{Enabled: true, Command: "server"}
+	}
+
+	mgr := NewManager()
+	defer mgr.Close()
+	err := mgr.LoadFromMCPConfig(context.Background(), config.MCPConfig{
+		ToolConfig: config.ToolConfig{Enabled: true},
+		Servers:    servers,
+	}, t.TempDir())
+	if err != nil {
+		t.Fatalf("LoadFromMCPConfig() error = %v, want partial success", err)
+	}
+	if got := len(mgr.GetServers()); got != 5 {
+		t.Fatalf("connected servers = %d, want 5", got)
+	}
+	if peak > 2 {
+		t.Fatalf("peak concurrent connects = %d, want <= 2", peak)
+	}
+}
+
 func TestNewManager_InitialState(t *testing.T) {
 	mgr := NewManager()
 	if mgr == nil {
@@ -475,6 +531,9 @@ func TestConnectServer_StreamableHTTPRequestResponseMode(t *testing.T) {
 			if got := conn.Session.ID(); got == "" {
 				t.Fatal("expected non-empty streamable session ID")
 			}
+			if conn.ProtocolVersion == "" {
+				t.Fatal("expected negotiated protocol version to be recorded")
+			}
 			if err := conn.Session.Close(); err != nil {
 				t.Fatalf("Session.Close() error = %v", err)
 			}
@@ -565,8 +624,10 @@ func TestCallTool_ReconnectsWhenHTTPServerLosesSession(t *testing.T) {
 	}
 
 	connectCalls := 0
+	var reconnectCtx context.Context
 	connectServerFunc = func(ctx context.Context, name string, cfg config.MCPServerConfig) (*ServerConnection, error) {
 		connectCalls++
+		reconnectCtx = ctx
 		if connectCalls == 1 {
 			return freshConn, nil
 		}
@@ -574,6 +635,7 @@ func TestCallTool_ReconnectsWhenHTTPServerLosesSession(t *testing.T) {
 	}
 
 	mgr := NewManager()
+	defer mgr.Close()
 	mgr.servers["flaky"] = staleConn
 
 	result, err := mgr.CallTool(context.Background(), "flaky", "echo", map[string]any{
@@ -582,6 +644,9 @@ func TestCallTool_ReconnectsWhenHTTPServerLosesSession(t *testing.T) {
 	if err != nil {
 		t.Fatalf("CallTool() error = %v", err)
 	}
+	if reconnectCtx == nil || reconnectCtx.Err() != nil {
+		t.Fatal("expected the reconnected server to outlive the tool call's timeout context")
+	}
 	if result == nil || len(result.Content) != 1 {
 		t.Fatalf("CallTool() returned unexpected content: %#v", result)
 	}
@@ -612,6 +677,252 @@ func TestCallTool_ReconnectsWhenHTTPServerLosesSession(t *testing.T) {
 	}
 }
 
+func TestCallTool_ReconnectsWhenKeepAliveClosedSession(t *testing.T) {
+	originalConnectServerFunc := connectServerFunc
+	t.Cleanup(func() {
+		connectServerFunc = originalConnectServerFunc
+	})
+
+	// A failed keep-alive ping makes the SDK close the session, so the next
+	// call fails with ErrConnectionClosed.
+	staleConn, _, err := newScriptedServerConnection(
+		"session-1",
+		nil,
+		fmt.Errorf(`%w: calling "tools/call": client is closing`, sdkmcp.ErrConnectionClosed),
+	)
+	if err != nil {
+		t.Fatalf("newScriptedServerConnection(stale) error = %v", err)
+	}
+	freshConn, freshTransport, err := newScriptedServerConnection(
+		"session-2",
+		&sdkmcp.CallToolResult{
+			Content: []sdkmcp.Content{
+				&sdkmcp.TextContent{Text: "reconnected"},
+			},
+		},
+		nil,
+	)
+	if err != nil {
+		t.Fatalf("newScriptedServerConnection(fresh) error = %v", err)
+	}
+
+	connectCalls := 0
+	connectServerFunc = func(ctx context.Context, name string, cfg config.MCPServerConfig) (*ServerConnection, error) {
+		connectCalls++
+		return freshConn, nil
+	}
+
+	mgr := NewManager()
+	mgr.servers["remote"] = staleConn
+
+	if _, err := mgr.CallTool(context.Background(), "remote", "echo", nil); err != nil {
+		t.Fatalf("CallTool() error = %v", err)
+	}
+	if connectCalls != 1 {
+		t.Fatalf("connectCalls = %d, want 1", connectCalls)
+	}
+	if freshTransport.toolCallCalls != 1 {
+		t.Fatalf("fresh toolCallCalls = %d, want 1", freshTransport.toolCallCalls)
+	}
+}
+
+func TestIsUnsupportedProtocolVersionError(t *testing.T) {
+	client := sdkmcp.NewClient(&sdkmcp.Implementation{Name: "picoclaw-test", Version: "1.0.0"}, nil)
+	_, err := client.Connect(context.Background(), &scriptedTransport{
+		sessionID:       "session-1",
+		protocolVersion: "1999-01-01",
+	}, nil)
+	if err == nil {
+		t.Fatal("expected Connect to reject an unsupported protocol version")
+	}
+	if !isUnsupportedProtocolVersionError(err) {
+		t.Fatalf("isUnsupportedProtocolVersionError(%v) = false, want true", err)
+	}
+	if isUnsupportedProtocolVersionError(errors.New("connection refused")) {
+		t.Fatal("unrelated errors must not be reported as protocol version errors")
+	}
+}
+
+func TestCallTool_TimesOutHungServer(t *testing.T) {
+	conn, transport, err := newScriptedServerConnection("session-1", nil, nil)
+	if err != nil {
+		t.Fatalf("newScriptedServerConnection() error = %v", err)
+	}
+	transport.toolCallHang = true
+	conn.Config.TimeoutSeconds = 1
+
+	mgr := NewManager()
+	mgr.servers["flaky"] = conn
+
+	start := time.Now()
+	_, err = mgr.CallTool(context.Background(), "flaky", "echo", nil)
+	if err == nil {
+		t.Fatal("expected CallTool() to fail for a hung server")
+	}
+	if !strings.Contains(err.Error(), "timed out after 1s") {
+		t.Fatalf("CallTool() error = %v, want timeout error", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("CallTool() took %s, want it bounded by the server timeout", elapsed)
+	}
+}
+
+func TestConnectServer_BoundsHandshake(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses the sleep command")
+	}
+	originalTerminate := isolatedCommandTerminateDuration
+	isolatedCommandTerminateDuration = 100 * time.Millisecond
+	t.Cleanup(func() { isolatedCommandTerminateDuration = originalTerminate })
+	cfg := config.MCPServerConfig{
+		Enabled:        true,
+		Command:        "sleep",
+		Args:           []string{"30"},
+		TimeoutSeconds: 1,
+	}
+
+	start := time.Now()
+	_, err := connectServer(context.Background(), "silent", cfg)
+	if err == nil {
+		t.Fatal("expected connecting to a server that never answers initialize to fail")
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Fatalf("connectServer() took %s, want it bounded by the server timeout", elapsed)
+	}
+}
+
+func TestDisconnectServer_RemovesServerAndPublishesEvent(t *testing.T) {
+	conn, transport, err := newScriptedServerConnection("session-1", nil, nil)
+	if err != nil {
+		t.Fatalf("newScriptedServerConnection() error = %v", err)
+	}
+
+	eventBus := runtimeevents.NewBus()
+	defer func() {
+		if err := eventBus.Close(); err != nil {
+			t.Errorf("event bus close failed: %v", err)
+		}
+	}()
+	_, eventsCh, err := eventBus.Channel().OfKind(
+		runtimeevents.KindMCPServerDisconnected,
+	).SubscribeChan(t.Context(), runtimeevents.SubscribeOptions{Name: "mcp-disconnect", Buffer: 1})
+	if err != nil {
+		t.Fatalf("SubscribeChan failed: %v", err)
+	}
+
+	mgr := NewManager(WithRuntimeEvents(eventBus))
+	defer mgr.Close()
+	mgr.servers["flaky"] = conn
+
+	if err := mgr.DisconnectServer("flaky"); err != nil {
+		t.Fatalf("DisconnectServer() error = %v", err)
+	}
+	if _, ok := mgr.GetServer("flaky"); ok {
+		t.Fatal("expected server to be removed after DisconnectServer")
+	}
+	transport.mu.Lock()
+	closed := transport.closed
+	transport.mu.Unlock()
+	if !closed {
+		t.Fatal("expected server session to be closed")
+	}
+	if evt := receiveMCPRuntimeEvent(t, eventsCh); evt.Source.Name != "flaky" {
+		t.Fatalf("disconnected event = %+v", evt)
+	}
+
+	if err := mgr.DisconnectServer("flaky"); err == nil {
+		t.Fatal("expected error when disconnecting an unknown server")
+	}
+}
+
+func TestStartServer_RejectsAlreadyConnectedServer(t *testing.T) {
+	originalConnectServerFunc := connectServerFunc
+	t.Cleanup(func() {
+		connectServerFunc = originalConnectServerFunc
+	})
+	connectServerFunc = func(ctx context.Context, name string, cfg config.MCPServerConfig) (*ServerConnection, error) {
+		conn, _, err := newScriptedServerConnection(name, nil, nil)
+		return conn, err
+	}
+
+	mgr := NewManager()
+	defer mgr.Close()
+	cfg := config.MCPServerConfig{Enabled: true, Command: "server"}
+
+	if err := mgr.StartServer(context.Background(), "files", cfg, t.TempDir()); err != nil {
+		t.Fatalf("StartServer() error = %v", err)
+	}
+	if _, ok := mgr.GetServer("files"); !ok {
+		t.Fatal("expected started server to be registered")
+	}
+	err := mgr.StartServer(context.Background(), "files", cfg, t.TempDir())
+	if err == nil || !strings.Contains(err.Error(), "already connected") {
+		t.Fatalf("StartServer() second call error = %v, want already connected", err)
+	}
+}
+
+func TestStartServer_OutlivesStartContext(t *testing.T) {
+	originalConnectServerFunc := connectServerFunc
+	t.Cleanup(func() {
+		connectServerFunc = originalConnectServerFunc
+	})
+	var processCtx context.Context
+	connectServerFunc = func(ctx context.Context, name string, cfg config.MCPServerConfig) (*ServerConnection, error) {
+		processCtx = ctx
+		conn, _, err := newScriptedServerConnection(name, nil, nil)
+		return conn, err
+	}
+
+	mgr := NewManager()
+	cfg := config.MCPServerConfig{Enabled: true, Command: "server"}
+
+	// A tool call starts the server and its turn ends right after.
+	startCtx, cancel := context.WithCancel(context.Background())
+	if err := mgr.StartServer(startCtx, "files", cfg, t.TempDir()); err != nil {
+		t.Fatalf("StartServer() error = %v", err)
+	}
+	cancel()
+
+	if err := processCtx.Err(); err != nil {
+		t.Fatalf("server process context canceled with the start context: %v", err)
+	}
+	if _, ok := mgr.GetServer("files"); !ok {
+		t.Fatal("expected server to stay connected after the start context is canceled")
+	}
+
+	mgr.Close()
+	if processCtx.Err() == nil {
+		t.Fatal("expected server process context to end when the manager closes")
+	}
+}
+
+func TestStartServer_CanceledStartContextStopsWaiting(t *testing.T) {
+	originalConnectServerFunc := connectServerFunc
+	t.Cleanup(func() {
+		connectServerFunc = originalConnectServerFunc
+	})
+	release := make(chan struct{})
+	connectServerFunc = func(ctx context.Context, name string, cfg config.MCPServerConfig) (*ServerConnection, error) {
+		<-release
+		conn, _, err := newScriptedServerConnection(name, nil, nil)
+		return conn, err
+	}
+
+	mgr := NewManager()
+	defer mgr.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := mgr.StartServer(ctx, "files", config.MCPServerConfig{Enabled: true, Command: "server"}, t.TempDir())
+	close(release)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("StartServer() error = %v, want context.Canceled", err)
+	}
+	if _, ok := mgr.GetServer("files"); ok {
+		t.Fatal("expected abandoned connection not to be registered")
+	}
+}
+
 func TestClose_IdempotentOnEmptyManager(t *testing.T) {
 	mgr := NewManager()
 
@@ -659,9 +970,12 @@ func newScriptedServerConnection(
 }
 
 type scriptedTransport struct {
-	sessionID      string
-	toolCallResult *sdkmcp.CallToolResult
-	toolCallErr    error
+	sessionID       string
+	protocolVersion string
+	toolCallResult  *sdkmcp.CallToolResult
+	toolCallErr     error
+	// toolCallHang leaves tools/call unanswered to model a hung server.
+	toolCallHang bool
 
 	mu            sync.Mutex
 	toolCallCalls int
@@ -696,8 +1010,12 @@ func (t *scriptedTransport) Write(ctx context.Context, msg jsonrpc.Message) erro
 
 	switch req.Method {
 	case "initialize":
+		protocolVersion := t.protocolVersion
+		if protocolVersion == "" {
+			protocolVersion = "2025-11-25"
+		}
 		payload, err := json.Marshal(&sdkmcp.InitializeResult{
-			ProtocolVersion: "2025-11-25",
+			ProtocolVersion: protocolVersion,
 			ServerInfo: &sdkmcp.Implementation{
 				Name:    "scripted-test-server",
 				Version: "1.0.0",
@@ -727,6 +1045,9 @@ func (t *scriptedTransport) Write(ctx context.Context, msg jsonrpc.Message) erro
 		if t.toolCallErr != nil {
 			return t.toolCallErr
 		}
+		if t.toolCallHang {
+			return nil
+		}
 
 		payload, err := json.Marshal(t.toolCallResult)
 		if err != nil {
@@ -0,0 +1,108 @@
+package mcp
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	sdkmcp "github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ToolMetrics summarizes the calls made to one MCP tool since the manager
+// was created. Byte counts are the JSON-encoded sizes of the call arguments
+// and results, which approximate the traffic on the wire.
+type ToolMetrics struct {
+	Server        string
+	Tool          string
+	Calls         int64
+	Errors        int64
+	TotalLatency  time.Duration
+	MaxLatency    time.Duration
+	BytesSent     int64
+	BytesReceived int64
+}
+
+// AvgLatency returns the mean call latency, or 0 before the first call.
+func (t ToolMetrics) AvgLatency() time.Duration {
+	if t.Calls == 0 {
+		return 0
+	}
+	return t.TotalLatency / time.Duration(t.Calls)
+}
+
+type toolMetricsKey struct {
+	server string
+	tool   string
+}
+
+type metricsRecorder struct {
+	mu    sync.Mutex
+	tools map[toolMetricsKey]*ToolMetrics
+}
+
+func (r *metricsRecorder) record(
+	serverName, toolName string,
+	latency time.Duration,
+	arguments map[string]any,
+	result *sdkmcp.CallToolResult,
+	err error,
+) {
+	sent := jsonSize(arguments)
+	var received int64
+	if result != nil {
+		received = jsonSize(result)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.tools == nil {
+		r.tools = make(map[toolMetricsKey]*ToolMetrics)
+	}
+	key := toolMetricsKey{server: serverName, tool: toolName}
+	metrics, ok := r.tools[key]
+	if !ok {
+		metrics = &ToolMetrics{Server: serverName, Tool: toolName}
+		r.tools[key] = metrics
+	}
+	metrics.Calls++
+	if err != nil || (result != nil && result.IsError) {
+		metrics.Errors++
+	}
+	metrics.TotalLatency += latency
+	metrics.MaxLatency = max(metrics.MaxLatency, latency)
+	metrics.BytesSent += sent
+	metrics.BytesReceived += received
+}
+
+func (r *metricsRecorder) snapshot() []ToolMetrics {
+	r.mu.Lock()
+	out := make([]ToolMetrics, 0, len(r.tools))
+	for _, metrics := range r.tools {
+		out = append(out, *metrics)
+	}
+	r.mu.Unlock()
+
+	slices.SortFunc(out, func(a, b ToolMetrics) int {
+		if c := strings.Compare(a.Server, b.Server); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Tool, b.Tool)
+	})
+	return out
+}
+
+func jsonSize(v any) int64 {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return 0
+	}
+	return int64(len(data))
+}
+
+// ToolMetrics returns per-tool call statistics for every MCP tool called
+// through the manager, sorted by server and tool name.
+func (m *Manager) ToolMetrics() []ToolMetrics {
+	return m.metrics.snapshot()
+}
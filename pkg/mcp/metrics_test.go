@@ -0,0 +1,51 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	sdkmcp "github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestCallTool_RecordsToolMetrics(t *testing.T) {
+	conn, _, err := newScriptedServerConnection("session-1", &sdkmcp.CallToolResult{
+		Content: []sdkmcp.Content{&sdkmcp.TextContent{Text: "ok"}},
+	}, nil)
+	if err != nil {
+		t.Fatalf("newScriptedServerConnection() error = %v", err)
+	}
+
+	mgr := NewManager()
+	defer mgr.Close()
+	mgr.servers["flaky"] = conn
+
+	for range 2 {
+		if _, err := mgr.CallTool(context.Background(), "flaky", "echo", map[string]any{"q": "hi"}); err != nil {
+			t.Fatalf("CallTool() error = %v", err)
+		}
+	}
+	if _, err := mgr.CallTool(context.Background(), "missing", "echo", nil); err == nil {
+		t.Fatal("expected error for unknown server")
+	}
+
+	metrics := mgr.ToolMetrics()
+	if len(metrics) != 2 {
+		t.Fatalf("len(ToolMetrics()) = %d, want 2: %+v", len(metrics), metrics)
+	}
+	echo := metrics[0]
+	if echo.Server != "flaky" || echo.Tool != "echo" {
+		t.Fatalf("metrics[0] = %+v, want flaky/echo", echo)
+	}
+	if echo.Calls != 2 || echo.Errors != 0 {
+		t.Fatalf("calls/errors = %d/%d, want 2/0", echo.Calls, echo.Errors)
+	}
+	if echo.BytesSent == 0 || echo.BytesReceived == 0 {
+		t.Fatalf("expected byte counts to be recorded, got %+v", echo)
+	}
+	if echo.AvgLatency() > echo.MaxLatency {
+		t.Fatalf("avg latency %s exceeds max %s", echo.AvgLatency(), echo.MaxLatency)
+	}
+	if missing := metrics[1]; missing.Server != "missing" || missing.Errors != 1 {
+		t.Fatalf("metrics[1] = %+v, want one error for missing/echo", missing)
+	}
+}
@@ -0,0 +1,182 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+
+	sdkmcp "github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/sipeed/picoclaw/pkg/config"
+	runtimeevents "github.com/sipeed/picoclaw/pkg/events"
+	"github.com/sipeed/picoclaw/pkg/logger"
+)
+
+// resourceUpdateHook routes notifications/resources/updated from a session to
+// the manager that owns it. The client is created before the manager knows
+// about the connection, so the target is attached afterwards.
+type resourceUpdateHook struct {
+	mu       sync.Mutex
+	onUpdate func(uri string)
+}
+
+func (h *resourceUpdateHook) set(onUpdate func(uri string)) {
+	h.mu.Lock()
+	h.onUpdate = onUpdate
+	h.mu.Unlock()
+}
+
+func (h *resourceUpdateHook) handle(_ context.Context, req *sdkmcp.ResourceUpdatedNotificationRequest) {
+	if req == nil || req.Params == nil {
+		return
+	}
+	h.mu.Lock()
+	onUpdate := h.onUpdate
+	h.mu.Unlock()
+	if onUpdate != nil {
+		onUpdate(req.Params.URI)
+	}
+}
+
+// SubscribeResource asks a server to send updates for a resource. Each update
+// is published as a KindMCPResourceUpdated runtime event and passed to the
+// resource update handler. The subscription is renewed automatically when the
+// server reconnects.
+func (m *Manager) SubscribeResource(ctx context.Context, serverName, uri string) error {
+	conn, ok := m.GetServer(serverName)
+	if !ok {
+		return fmt.Errorf("server %s not found", serverName)
+	}
+	if !supportsResourceSubscriptions(conn) {
+		return fmt.Errorf("server %s does not support resource subscriptions", serverName)
+	}
+	if err := conn.Session.Subscribe(ctx, &sdkmcp.SubscribeParams{URI: uri}); err != nil {
+		return fmt.Errorf("failed to subscribe to %s on server %s: %w", uri, serverName, err)
+	}
+
+	m.subsMu.Lock()
+	defer m.subsMu.Unlock()
+	if m.subscriptions == nil {
+		m.subscriptions = make(map[string]map[string]struct{})
+	}
+	if m.subscriptions[serverName] == nil {
+		m.subscriptions[serverName] = make(map[string]struct{})
+	}
+	m.subscriptions[serverName][uri] = struct{}{}
+	return nil
+}
+
+// UnsubscribeResource stops updates for a resource subscribed with
+// SubscribeResource. Resources listed in a server's subscribe_resources config
+// are subscribed again the next time the server reconnects.
+func (m *Manager) UnsubscribeResource(ctx context.Context, serverName, uri string) error {
+	m.subsMu.Lock()
+	delete(m.subscriptions[serverName], uri)
+	m.subsMu.Unlock()
+
+	conn, ok := m.GetServer(serverName)
+	if !ok {
+		return fmt.Errorf("server %s not found", serverName)
+	}
+	if err := conn.Session.Unsubscribe(ctx, &sdkmcp.UnsubscribeParams{URI: uri}); err != nil {
+		return fmt.Errorf("failed to unsubscribe from %s on server %s: %w", uri, serverName, err)
+	}
+	return nil
+}
+
+// subscribeResources routes a new connection's resource updates to the
+// runtime event bus and the update handler, and subscribes to the configured and previously requested
+// resources. Failures are logged; the connection stays usable for tool calls.
+func (m *Manager) subscribeResources(ctx context.Context, serverName string, conn *ServerConnection) {
+	if conn.resourceUpdates != nil {
+		cfg := conn.Config
+		conn.resourceUpdates.set(func(uri string) {
+			m.publishResourceUpdated(serverName, cfg, uri)
+		})
+	}
+
+	uris := m.resourceSubscriptions(serverName, conn.Config)
+	if len(uris) == 0 {
+		return
+	}
+	if !supportsResourceSubscriptions(conn) {
+		logger.WarnCF("mcp", "MCP server does not support resource subscriptions",
+			map[string]any{
+				"server":    serverName,
+				"resources": len(uris),
+			})
+		return
+	}
+	for _, uri := range uris {
+		if err := conn.Session.Subscribe(ctx, &sdkmcp.SubscribeParams{URI: uri}); err != nil {
+			logger.WarnCF("mcp", "Failed to subscribe to MCP resource",
+				map[string]any{
+					"server":   serverName,
+					"resource": uri,
+					"error":    err.Error(),
+				})
+		}
+	}
+}
+
+// resourceSubscriptions returns the configured and runtime subscriptions for
+// a server, deduplicated and sorted.
+func (m *Manager) resourceSubscriptions(serverName string, cfg config.MCPServerConfig) []string {
+	uris := slices.Clone(cfg.SubscribeResources)
+	m.subsMu.Lock()
+	for uri := range m.subscriptions[serverName] {
+		uris = append(uris, uri)
+	}
+	m.subsMu.Unlock()
+	slices.Sort(uris)
+	return slices.Compact(uris)
+}
+
+func (m *Manager) forgetResourceSubscriptions(serverName string) {
+	m.subsMu.Lock()
+	delete(m.subscriptions, serverName)
+	m.subsMu.Unlock()
+}
+
+func supportsResourceSubscriptions(conn *ServerConnection) bool {
+	if conn == nil || conn.Session == nil {
+		return false
+	}
+	initResult := conn.Session.InitializeResult()
+	return initResult != nil &&
+		initResult.Capabilities != nil &&
+		initResult.Capabilities.Resources != nil &&
+		initResult.Capabilities.Resources.Subscribe
+}
+
+func (m *Manager) publishResourceUpdated(serverName string, cfg config.MCPServerConfig, uri string) {
+	logger.DebugCF("mcp", "MCP resource updated",
+		map[string]any{
+			"server":   serverName,
+			"resource": uri,
+		})
+	if m == nil {
+		return
+	}
+	if m.onResourceUpdated != nil {
+		m.onResourceUpdated(serverName, uri)
+	}
+	if m.runtimeEvents == nil {
+		return
+	}
+	payload := ServerEventPayload{
+		Server:   serverName,
+		Type:     mcpTransportType(cfg),
+		URL:      cfg.URL,
+		Command:  cfg.Command,
+		Resource: uri,
+	}
+	m.runtimeEvents.PublishNonBlocking(runtimeevents.Event{
+		Kind:     runtimeevents.KindMCPResourceUpdated,
+		Source:   runtimeevents.Source{Component: "mcp", Name: serverName},
+		Severity: runtimeevents.SeverityInfo,
+		Payload:  payload,
+		Attrs:    mcpServerEventAttrs(payload),
+	})
+}
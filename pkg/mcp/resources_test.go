@@ -0,0 +1,114 @@
+package mcp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	sdkmcp "github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/sipeed/picoclaw/pkg/config"
+	runtimeevents "github.com/sipeed/picoclaw/pkg/events"
+)
+
+func TestResourceSubscriptionPublishesUpdates(t *testing.T) {
+	subscribed := make(chan string, 1)
+	server := sdkmcp.NewServer(&sdkmcp.Implementation{Name: "resource-server", Version: "1.0.0"}, &sdkmcp.ServerOptions{
+		SubscribeHandler: func(_ context.Context, req *sdkmcp.SubscribeRequest) error {
+			subscribed <- req.Params.URI
+			return nil
+		},
+		UnsubscribeHandler: func(context.Context, *sdkmcp.UnsubscribeRequest) error {
+			return nil
+		},
+	})
+	server.AddResource(&sdkmcp.Resource{URI: "file:///notes.txt", Name: "notes"},
+		func(context.Context, *sdkmcp.ReadResourceRequest) (*sdkmcp.ReadResourceResult, error) {
+			return &sdkmcp.ReadResourceResult{}, nil
+		})
+
+	handler := sdkmcp.NewStreamableHTTPHandler(func(*http.Request) *sdkmcp.Server { return server }, nil)
+	httpServer := httptest.NewServer(handler)
+	defer httpServer.Close()
+
+	eventBus := runtimeevents.NewBus()
+	defer func() {
+		if err := eventBus.Close(); err != nil {
+			t.Errorf("event bus close failed: %v", err)
+		}
+	}()
+	_, eventsCh, err := eventBus.Channel().OfKind(
+		runtimeevents.KindMCPResourceUpdated,
+	).SubscribeChan(t.Context(), runtimeevents.SubscribeOptions{Name: "mcp-resources", Buffer: 1})
+	if err != nil {
+		t.Fatalf("SubscribeChan failed: %v", err)
+	}
+
+	handled := make(chan string, 1)
+	mgr := NewManager(WithRuntimeEvents(eventBus), WithResourceUpdateHandler(func(serverName, uri string) {
+		handled <- serverName + " " + uri
+	}))
+	defer mgr.Close()
+
+	err = mgr.ConnectServer(context.Background(), "notes", config.MCPServerConfig{
+		Enabled:            true,
+		Type:               "sse",
+		URL:                httpServer.URL,
+		SubscribeResources: []string{"file:///notes.txt"},
+	})
+	if err != nil {
+		t.Fatalf("ConnectServer() error = %v", err)
+	}
+
+	select {
+	case uri := <-subscribed:
+		if uri != "file:///notes.txt" {
+			t.Fatalf("subscribed URI = %q, want file:///notes.txt", uri)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for resources/subscribe")
+	}
+
+	if err := server.ResourceUpdated(context.Background(), &sdkmcp.ResourceUpdatedNotificationParams{
+		URI: "file:///notes.txt",
+	}); err != nil {
+		t.Fatalf("ResourceUpdated() error = %v", err)
+	}
+
+	evt := receiveMCPRuntimeEvent(t, eventsCh)
+	payload, ok := evt.Payload.(ServerEventPayload)
+	if !ok {
+		t.Fatalf("payload type = %T, want ServerEventPayload", evt.Payload)
+	}
+	if payload.Server != "notes" || payload.Resource != "file:///notes.txt" {
+		t.Fatalf("payload = %+v", payload)
+	}
+	select {
+	case got := <-handled:
+		if got != "notes file:///notes.txt" {
+			t.Fatalf("handler got %q, want notes file:///notes.txt", got)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for the resource update handler")
+	}
+}
+
+func TestSubscribeResource_RejectsServerWithoutSubscribeCapability(t *testing.T) {
+	conn, _, err := newScriptedServerConnection("session-1", nil, nil)
+	if err != nil {
+		t.Fatalf("newScriptedServerConnection() error = %v", err)
+	}
+
+	mgr := NewManager()
+	defer mgr.Close()
+	mgr.servers["flaky"] = conn
+
+	if err := mgr.SubscribeResource(context.Background(), "flaky", "file:///notes.txt"); err == nil {
+		t.Fatal("expected error for a server without resource subscription support")
+	}
+	if err := mgr.SubscribeResource(context.Background(), "missing", "file:///notes.txt"); err == nil {
+		t.Fatal("expected error for an unknown server")
+	}
+}
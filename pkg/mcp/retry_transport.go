@@ -0,0 +1,167 @@
+package mcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/sipeed/picoclaw/pkg/logger"
+)
+
+var (
+	retryBaseDelay = 500 * time.Millisecond
+	retryMaxDelay  = 10 * time.Second
+)
+
+// retryTransport retries transient HTTP failures (429/502/503/504 and
+// connection resets) for requests that are safe to repeat: GETs and the
+// JSON-RPC methods accepted by isRetryableMCPMethod. Tool calls are never
+// retried because they may have side effects on the server.
+type retryTransport struct {
+	base       http.RoundTripper
+	server     string
+	maxRetries int
+}
+
+func isRetryableMCPMethod(method string) bool {
+	switch method {
+	case "initialize", "ping", "tools/list", "prompts/list", "resources/list", "resources/templates/list":
+		return true
+	}
+	return false
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	var body []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	if !t.retryable(req.Method, body) {
+		req = withReplayableBody(req, body)
+		return base.RoundTrip(req)
+	}
+
+	for attempt := 0; ; attempt++ {
+		resp, err := base.RoundTrip(withReplayableBody(req, body))
+		if attempt >= t.maxRetries || !isTransientHTTPFailure(resp, err) {
+			return resp, err
+		}
+
+		delay := retryDelay(attempt, resp)
+		fields := map[string]any{
+			"server":  t.server,
+			"attempt": attempt + 1,
+			"delay":   delay.String(),
+		}
+		if err != nil {
+			fields["error"] = err.Error()
+		} else {
+			fields["status"] = resp.StatusCode
+			_, _ = io.Copy(io.Discard, resp.Body)
+			_ = resp.Body.Close()
+		}
+		logger.WarnCF("mcp", "Retrying transient MCP HTTP failure", fields)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// retryable reports whether a request may be sent again without side effects.
+func (t *retryTransport) retryable(method string, body []byte) bool {
+	if t.maxRetries <= 0 {
+		return false
+	}
+	if method == http.MethodGet {
+		return true
+	}
+	if method != http.MethodPost || len(body) == 0 {
+		return false
+	}
+	var msg struct {
+		Method string `json:"method"`
+	}
+	if err := json.Unmarshal(body, &msg); err != nil {
+		return false
+	}
+	return isRetryableMCPMethod(msg.Method)
+}
+
+func withReplayableBody(req *http.Request, body []byte) *http.Request {
+	clone := req.Clone(req.Context())
+	if body == nil {
+		return clone
+	}
+	clone.Body = io.NopCloser(bytes.NewReader(body))
+	clone.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(body)), nil
+	}
+	clone.ContentLength = int64(len(body))
+	return clone
+}
+
+func isTransientHTTPFailure(resp *http.Response, err error) bool {
+	if err != nil {
+		return errors.Is(err, syscall.ECONNRESET) ||
+			errors.Is(err, syscall.ECONNREFUSED) ||
+			errors.Is(err, io.ErrUnexpectedEOF) ||
+			errors.Is(err, io.EOF)
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// retryDelay honors Retry-After when present and otherwise uses exponential
+// backoff with full jitter, capped at retryMaxDelay.
+func retryDelay(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if delay, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+			return min(delay, retryMaxDelay)
+		}
+	}
+	backoff := min(retryBaseDelay<<min(attempt, 16), retryMaxDelay)
+	return time.Duration(rand.Int64N(int64(backoff))) + 1
+}
+
+func parseRetryAfter(value string) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return max(time.Until(at), 0), true
+	}
+	return 0, false
+}
@@ -0,0 +1,86 @@
+package mcp
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRetryTransport_RetriesIdempotentRequests(t *testing.T) {
+	originalBase, originalMax := retryBaseDelay, retryMaxDelay
+	t.Cleanup(func() {
+		retryBaseDelay, retryMaxDelay = originalBase, originalMax
+	})
+	retryBaseDelay, retryMaxDelay = time.Millisecond, 5*time.Millisecond
+
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if !strings.Contains(string(body), `"tools/list"`) {
+			t.Errorf("retried request lost its body: %q", body)
+		}
+		if calls.Add(1) < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: &retryTransport{server: "remote", maxRetries: 3}}
+	resp, err := client.Post(srv.URL, "application/json",
+		strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"tools/list"}`))
+	if err != nil {
+		t.Fatalf("Post() error = %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+	if got := calls.Load(); got != 3 {
+		t.Fatalf("server calls = %d, want 3", got)
+	}
+}
+
+func TestRetryTransport_DoesNotRetryToolCalls(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: &retryTransport{server: "remote", maxRetries: 3}}
+	resp, err := client.Post(srv.URL, "application/json",
+		strings.NewReader(`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"delete"}}`))
+	if err != nil {
+		t.Fatalf("Post() error = %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusBadGateway {
+		t.Fatalf("status = %d, want 502", resp.StatusCode)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Fatalf("server calls = %d, want 1 (tools/call must not be retried)", got)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	if d, ok := parseRetryAfter("7"); !ok || d != 7*time.Second {
+		t.Fatalf("parseRetryAfter(7) = %v, %v", d, ok)
+	}
+	future := time.Now().Add(time.Minute).UTC().Format(http.TimeFormat)
+	if d, ok := parseRetryAfter(future); !ok || d <= 0 || d > time.Minute {
+		t.Fatalf("parseRetryAfter(date) = %v, %v", d, ok)
+	}
+	if _, ok := parseRetryAfter("soon"); ok {
+		t.Fatal("expected invalid Retry-After to be rejected")
+	}
+}
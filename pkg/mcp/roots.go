@@ -0,0 +1,56 @@
+package mcp
+
+import (
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// resolveServerRoots returns the absolute directories advertised to a server.
+// Relative entries resolve against workspace, and an empty list falls back to
+// the workspace so servers stay scoped to the same sandbox as picoclaw's own
+// filesystem tools.
+func resolveServerRoots(roots []string, workspace string) []string {
+	if len(roots) == 0 {
+		if workspace == "" {
+			return nil
+		}
+		return []string{filepath.Clean(workspace)}
+	}
+
+	resolved := make([]string, 0, len(roots))
+	for _, root := range roots {
+		root = strings.TrimSpace(root)
+		if root == "" {
+			continue
+		}
+		root = expandHomeCommandPath(root)
+		if !filepath.IsAbs(root) {
+			if workspace == "" {
+				continue
+			}
+			root = filepath.Join(workspace, root)
+		}
+		resolved = append(resolved, filepath.Clean(root))
+	}
+	return resolved
+}
+
+// rootsFromPaths converts directories into file:// roots for roots/list.
+func rootsFromPaths(paths []string) []*mcp.Root {
+	roots := make([]*mcp.Root, 0, len(paths))
+	for _, path := range paths {
+		slashed := filepath.ToSlash(path)
+		if !strings.HasPrefix(slashed, "/") {
+			// Windows drive paths need a leading slash in file URIs.
+			slashed = "/" + slashed
+		}
+		roots = append(roots, &mcp.Root{
+			URI:  (&url.URL{Scheme: "file", Path: slashed}).String(),
+			Name: filepath.Base(path),
+		})
+	}
+	return roots
+}
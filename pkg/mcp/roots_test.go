@@ -0,0 +1,73 @@
+package mcp
+
+import (
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestResolveServerRoots(t *testing.T) {
+	workspace := filepath.Join(t.TempDir(), "workspace")
+
+	tests := []struct {
+		name      string
+		roots     []string
+		workspace string
+		want      []string
+	}{
+		{
+			name:      "defaults to workspace",
+			workspace: workspace,
+			want:      []string{workspace},
+		},
+		{
+			name: "no workspace and no roots",
+			want: nil,
+		},
+		{
+			name:      "relative roots resolve against workspace",
+			roots:     []string{"projects", " ", filepath.Join(workspace, "..", "shared")},
+			workspace: workspace,
+			want: []string{
+				filepath.Join(workspace, "projects"),
+				filepath.Join(filepath.Dir(workspace), "shared"),
+			},
+		},
+		{
+			name:  "relative roots are dropped without workspace",
+			roots: []string{"projects"},
+			want:  []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := resolveServerRoots(tt.roots, tt.workspace)
+			if len(got) != len(tt.want) {
+				t.Fatalf("resolveServerRoots() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("resolveServerRoots()[%d] = %q, want %q", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRootsFromPaths(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("unix paths")
+	}
+
+	roots := rootsFromPaths([]string{"/home/user/my workspace"})
+	if len(roots) != 1 {
+		t.Fatalf("rootsFromPaths() returned %d roots, want 1", len(roots))
+	}
+	if roots[0].URI != "file:///home/user/my%20workspace" {
+		t.Fatalf("root URI = %q", roots[0].URI)
+	}
+	if roots[0].Name != "my workspace" {
+		t.Fatalf("root name = %q", roots[0].Name)
+	}
+}
@@ -0,0 +1,97 @@
+package mcp
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+
+	"github.com/sipeed/picoclaw/pkg/logger"
+)
+
+const (
+	// stderrTailLines is how many recent stderr lines are kept per server.
+	stderrTailLines = 50
+	// maxStderrLineBytes flushes a partial line once it grows this large, so a
+	// server that never writes a newline cannot grow the buffer unbounded.
+	maxStderrLineBytes = 8 * 1024
+)
+
+// stderrLog forwards a stdio server's stderr to the logger line by line,
+// using "mcp:<server>" as the component, and keeps the most recent lines for
+// diagnostics.
+type stderrLog struct {
+	component string
+	level     logger.LogLevel
+	silent    bool
+
+	mu      sync.Mutex
+	partial []byte
+	tail    []string
+}
+
+// newStderrLog creates a stderr sink for a server. level is one of "debug"
+// (default), "info", "warn", "error" or "off"; "off" keeps the tail buffer but
+// does not log.
+func newStderrLog(server, level string) *stderrLog {
+	l := &stderrLog{component: "mcp:" + server, level: logger.DEBUG}
+	if strings.EqualFold(strings.TrimSpace(level), "off") {
+		l.silent = true
+	} else if parsed, ok := logger.ParseLevel(level); ok {
+		l.level = parsed
+	}
+	return l
+}
+
+func (l *stderrLog) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.partial = append(l.partial, p...)
+	for {
+		idx := bytes.IndexByte(l.partial, '\n')
+		if idx < 0 {
+			break
+		}
+		l.emit(string(l.partial[:idx]))
+		l.partial = l.partial[idx+1:]
+	}
+	if len(l.partial) >= maxStderrLineBytes {
+		l.emit(string(l.partial))
+		l.partial = nil
+	}
+	return len(p), nil
+}
+
+// emit must be called with l.mu held.
+func (l *stderrLog) emit(line string) {
+	line = strings.TrimRight(line, "\r")
+	if strings.TrimSpace(line) == "" {
+		return
+	}
+
+	l.tail = append(l.tail, line)
+	if len(l.tail) > stderrTailLines {
+		l.tail = l.tail[len(l.tail)-stderrTailLines:]
+	}
+
+	if l.silent {
+		return
+	}
+	switch l.level {
+	case logger.ERROR, logger.FATAL:
+		logger.ErrorCF(l.component, line, nil)
+	case logger.WARN:
+		logger.WarnCF(l.component, line, nil)
+	case logger.INFO:
+		logger.InfoCF(l.component, line, nil)
+	default:
+		logger.DebugCF(l.component, line, nil)
+	}
+}
+
+// Tail returns the most recent stderr lines, oldest first.
+func (l *stderrLog) Tail() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]string(nil), l.tail...)
+}
@@ -0,0 +1,58 @@
+package mcp
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/sipeed/picoclaw/pkg/logger"
+)
+
+func TestStderrLog_SplitsLinesAndKeepsTail(t *testing.T) {
+	l := newStderrLog("files", "off")
+
+	_, _ = l.Write([]byte("starting server\r\npartial "))
+	_, _ = l.Write([]byte("line\n\n"))
+	if got := l.Tail(); len(got) != 2 || got[0] != "starting server" || got[1] != "partial line" {
+		t.Fatalf("Tail() = %q", got)
+	}
+
+	for i := range stderrTailLines + 5 {
+		_, _ = fmt.Fprintf(l, "line %d\n", i)
+	}
+	tail := l.Tail()
+	if len(tail) != stderrTailLines {
+		t.Fatalf("Tail() kept %d lines, want %d", len(tail), stderrTailLines)
+	}
+	if tail[len(tail)-1] != fmt.Sprintf("line %d", stderrTailLines+4) {
+		t.Fatalf("last tail line = %q", tail[len(tail)-1])
+	}
+
+	_, _ = l.Write([]byte(strings.Repeat("x", maxStderrLineBytes)))
+	if last := l.Tail()[stderrTailLines-1]; len(last) != maxStderrLineBytes {
+		t.Fatalf("expected oversized partial line to be flushed, got %d bytes", len(last))
+	}
+}
+
+func TestNewStderrLog_Levels(t *testing.T) {
+	tests := []struct {
+		level      string
+		wantLevel  logger.LogLevel
+		wantSilent bool
+	}{
+		{level: "", wantLevel: logger.DEBUG},
+		{level: "warn", wantLevel: logger.WARN},
+		{level: "bogus", wantLevel: logger.DEBUG},
+		{level: "OFF", wantLevel: logger.DEBUG, wantSilent: true},
+	}
+
+	for _, tt := range tests {
+		l := newStderrLog("files", tt.level)
+		if l.level != tt.wantLevel || l.silent != tt.wantSilent {
+			t.Fatalf("newStderrLog(%q) = level %v silent %v", tt.level, l.level, l.silent)
+		}
+		if l.component != "mcp:files" {
+			t.Fatalf("component = %q, want mcp:files", l.component)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package mcp
+
+import (
+	"fmt"
+	"path"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/sipeed/picoclaw/pkg/config"
+	"github.com/sipeed/picoclaw/pkg/logger"
+)
+
+// validateToolPatterns reports the first malformed include/exclude pattern so
+// a typo in config fails the connection instead of silently exposing tools.
+func validateToolPatterns(cfg config.MCPServerConfig) error {
+	for _, patterns := range [][]string{cfg.IncludeTools, cfg.ExcludeTools} {
+		for _, pattern := range patterns {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("invalid tool pattern %q: %w", pattern, err)
+			}
+		}
+	}
+	return nil
+}
+
+// toolAllowed reports whether a tool passes the server's include/exclude
+// patterns. Patterns must have been checked by validateToolPatterns.
+func toolAllowed(cfg config.MCPServerConfig, toolName string) bool {
+	if len(cfg.IncludeTools) > 0 && !matchesAnyToolPattern(cfg.IncludeTools, toolName) {
+		return false
+	}
+	return !matchesAnyToolPattern(cfg.ExcludeTools, toolName)
+}
+
+func matchesAnyToolPattern(patterns []string, toolName string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, toolName); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// filterServerTools drops the tools hidden by the server's include/exclude
+// patterns so they are never registered with agents.
+func filterServerTools(name string, cfg config.MCPServerConfig, tools []*mcp.Tool) []*mcp.Tool {
+	if len(cfg.IncludeTools) == 0 && len(cfg.ExcludeTools) == 0 {
+		return tools
+	}
+
+	filtered := make([]*mcp.Tool, 0, len(tools))
+	for _, tool := range tools {
+		if tool == nil {
+			continue
+		}
+		if !toolAllowed(cfg, tool.Name) {
+			logger.DebugCF("mcp", "Skipping MCP tool excluded by server config",
+				map[string]any{
+					"server": name,
+					"tool":   tool.Name,
+				})
+			continue
+		}
+		filtered = append(filtered, tool)
+	}
+
+	if len(filtered) != len(tools) {
+		logger.InfoCF("mcp", "Filtered MCP server tools",
+			map[string]any{
+				"server":   name,
+				"listed":   len(tools),
+				"exposed":  len(filtered),
+				"excluded": len(tools) - len(filtered),
+			})
+	}
+
+	return filtered
+}
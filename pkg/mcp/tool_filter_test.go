@@ -0,0 +1,71 @@
+package mcp
+
+import (
+	"testing"
+
+	sdkmcp "github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/sipeed/picoclaw/pkg/config"
+)
+
+func TestFilterServerTools(t *testing.T) {
+	tools := []*sdkmcp.Tool{
+		{Name: "get_issue"},
+		{Name: "list_issues"},
+		{Name: "create_issue"},
+		{Name: "delete_repo"},
+	}
+
+	tests := []struct {
+		name string
+		cfg  config.MCPServerConfig
+		want []string
+	}{
+		{
+			name: "no patterns keeps everything",
+			cfg:  config.MCPServerConfig{},
+			want: []string{"get_issue", "list_issues", "create_issue", "delete_repo"},
+		},
+		{
+			name: "include only",
+			cfg:  config.MCPServerConfig{IncludeTools: []string{"get_*", "list_*"}},
+			want: []string{"get_issue", "list_issues"},
+		},
+		{
+			name: "exclude only",
+			cfg:  config.MCPServerConfig{ExcludeTools: []string{"delete_*"}},
+			want: []string{"get_issue", "list_issues", "create_issue"},
+		},
+		{
+			name: "exclude wins over include",
+			cfg: config.MCPServerConfig{
+				IncludeTools: []string{"*_issue*"},
+				ExcludeTools: []string{"create_issue"},
+			},
+			want: []string{"get_issue", "list_issues"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := filterServerTools("github", tt.cfg, tools)
+			if len(got) != len(tt.want) {
+				t.Fatalf("filterServerTools() returned %d tools, want %d", len(got), len(tt.want))
+			}
+			for i, tool := range got {
+				if tool.Name != tt.want[i] {
+					t.Fatalf("tool[%d] = %q, want %q", i, tool.Name, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestValidateToolPatterns(t *testing.T) {
+	if err := validateToolPatterns(config.MCPServerConfig{IncludeTools: []string{"get_*"}}); err != nil {
+		t.Fatalf("validateToolPatterns(valid) error = %v", err)
+	}
+	if err := validateToolPatterns(config.MCPServerConfig{ExcludeTools: []string{"[bad"}}); err == nil {
+		t.Fatal("expected error for malformed pattern")
+	}
+}
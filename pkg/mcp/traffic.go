@@ -0,0 +1,157 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	sdkmcp "github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	// trafficLogSize is how many JSON-RPC messages are kept per server.
+	trafficLogSize = 200
+	// maxTrafficMessageBytes caps a single recorded message so large tool
+	// results do not pin megabytes of memory in the debug buffer.
+	maxTrafficMessageBytes = 4096
+	redactedTrafficValue   = "[REDACTED]"
+)
+
+// Traffic directions recorded by the debug log.
+const (
+	TrafficSent     = "send"
+	TrafficReceived = "recv"
+)
+
+// TrafficEntry is one JSON-RPC message exchanged with an MCP server.
+type TrafficEntry struct {
+	Time      time.Time `json:"time"`
+	Direction string    `json:"direction"`
+	Message   string    `json:"message"`
+}
+
+// trafficLog is a fixed-size ring buffer of recent JSON-RPC messages.
+type trafficLog struct {
+	mu      sync.Mutex
+	entries []TrafficEntry
+	next    int
+	full    bool
+}
+
+func newTrafficLog(size int) *trafficLog {
+	return &trafficLog{entries: make([]TrafficEntry, size)}
+}
+
+func (l *trafficLog) record(direction string, msg jsonrpc.Message) {
+	data, err := jsonrpc.EncodeMessage(msg)
+	if err != nil {
+		return
+	}
+	entry := TrafficEntry{
+		Time:      time.Now(),
+		Direction: direction,
+		Message:   redactTrafficMessage(data),
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries[l.next] = entry
+	l.next = (l.next + 1) % len(l.entries)
+	if l.next == 0 {
+		l.full = true
+	}
+}
+
+// snapshot returns the recorded entries, oldest first.
+func (l *trafficLog) snapshot() []TrafficEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !l.full {
+		return append([]TrafficEntry(nil), l.entries[:l.next]...)
+	}
+	out := make([]TrafficEntry, 0, len(l.entries))
+	out = append(out, l.entries[l.next:]...)
+	return append(out, l.entries[:l.next]...)
+}
+
+// trafficTransport records every message passing through the wrapped
+// transport into a trafficLog.
+type trafficTransport struct {
+	transport sdkmcp.Transport
+	log       *trafficLog
+}
+
+func (t *trafficTransport) Connect(ctx context.Context) (sdkmcp.Connection, error) {
+	conn, err := t.transport.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &trafficConnection{Connection: conn, log: t.log}, nil
+}
+
+type trafficConnection struct {
+	sdkmcp.Connection
+	log *trafficLog
+}
+
+func (c *trafficConnection) Read(ctx context.Context) (jsonrpc.Message, error) {
+	msg, err := c.Connection.Read(ctx)
+	if err == nil {
+		c.log.record(TrafficReceived, msg)
+	}
+	return msg, err
+}
+
+func (c *trafficConnection) Write(ctx context.Context, msg jsonrpc.Message) error {
+	c.log.record(TrafficSent, msg)
+	return c.Connection.Write(ctx, msg)
+}
+
+// redactTrafficMessage masks values stored under secret-looking keys and
+// truncates oversized messages.
+func redactTrafficMessage(data []byte) string {
+	var decoded any
+	if err := json.Unmarshal(data, &decoded); err == nil {
+		if redacted, err := json.Marshal(redactTrafficValue(decoded)); err == nil {
+			data = redacted
+		}
+	}
+	if len(data) > maxTrafficMessageBytes {
+		return string(data[:maxTrafficMessageBytes]) + "…(truncated)"
+	}
+	return string(data)
+}
+
+func redactTrafficValue(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, item := range v {
+			if isSecretTrafficKey(key) {
+				v[key] = redactedTrafficValue
+				continue
+			}
+			v[key] = redactTrafficValue(item)
+		}
+		return v
+	case []any:
+		for i, item := range v {
+			v[i] = redactTrafficValue(item)
+		}
+		return v
+	default:
+		return value
+	}
+}
+
+func isSecretTrafficKey(key string) bool {
+	normalized := strings.NewReplacer("-", "", "_", "").Replace(strings.ToLower(key))
+	for _, marker := range []string{"authorization", "token", "secret", "password", "apikey", "cookie", "credential"} {
+		if strings.Contains(normalized, marker) {
+			return true
+		}
+	}
+	return false
+}
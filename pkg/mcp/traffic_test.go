@@ -0,0 +1,43 @@
+package mcp
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+)
+
+func TestTrafficLog_KeepsMostRecentEntries(t *testing.T) {
+	log := newTrafficLog(2)
+	for _, method := range []string{"initialize", "tools/list", "tools/call"} {
+		log.record(TrafficSent, &jsonrpc.Request{Method: method})
+	}
+
+	entries := log.snapshot()
+	if len(entries) != 2 {
+		t.Fatalf("snapshot() returned %d entries, want 2", len(entries))
+	}
+	if !strings.Contains(entries[0].Message, "tools/list") || !strings.Contains(entries[1].Message, "tools/call") {
+		t.Fatalf("snapshot() = %+v, want the two most recent requests oldest first", entries)
+	}
+}
+
+func TestRedactTrafficMessage(t *testing.T) {
+	got := redactTrafficMessage([]byte(
+		`{"params":{"arguments":{"query":"weather","api_key":"sk-123","nested":[{"Authorization":"Bearer x"}]}}}`,
+	))
+
+	for _, secret := range []string{"sk-123", "Bearer x"} {
+		if strings.Contains(got, secret) {
+			t.Fatalf("redactTrafficMessage() leaked %q: %s", secret, got)
+		}
+	}
+	if !strings.Contains(got, `"query":"weather"`) {
+		t.Fatalf("redactTrafficMessage() dropped non-secret fields: %s", got)
+	}
+
+	long := redactTrafficMessage([]byte(`"` + strings.Repeat("a", maxTrafficMessageBytes*2) + `"`))
+	if !strings.HasSuffix(long, "(truncated)") {
+		t.Fatalf("expected oversized message to be truncated, got %d bytes", len(long))
+	}
+}
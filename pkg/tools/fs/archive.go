@@ -0,0 +1,540 @@
+package fstools
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	// MaxArchiveTotalSize caps both the size of an archive read or written by
+	// the archive tool and the total uncompressed size of its contents.
+	MaxArchiveTotalSize = 100 * 1024 * 1024
+	// MaxArchiveEntries caps the number of files in an archive.
+	MaxArchiveEntries = 5000
+
+	maxArchiveListEntries = 500
+
+	archiveFormatZip   = "zip"
+	archiveFormatTarGz = "tar.gz"
+)
+
+// ArchiveTool creates, extracts and lists zip and tar.gz archives inside the
+// workspace. Entries whose names would escape the extraction directory are
+// rejected before anything is written, and symlinks and other special
+// entries are skipped in both directions.
+type ArchiveTool struct {
+	fs         fileSystem
+	maxBytes   int64
+	maxEntries int
+}
+
+// NewArchiveTool creates a new ArchiveTool with optional directory restriction.
+func NewArchiveTool(workspace string, restrict bool, allowPaths ...[]*regexp.Regexp) *ArchiveTool {
+	var patterns []*regexp.Regexp
+	if len(allowPaths) > 0 {
+		patterns = allowPaths[0]
+	}
+	return &ArchiveTool{
+		fs:         buildFs(workspace, restrict, patterns),
+		maxBytes:   MaxArchiveTotalSize,
+		maxEntries: MaxArchiveEntries,
+	}
+}
+
+func (t *ArchiveTool) Name() string {
+	return "archive"
+}
+
+func (t *ArchiveTool) Description() string {
+	return fmt.Sprintf(
+		"Create, extract or list zip and tar.gz archives. action=create packs the given sources (files or directories) into path; action=extract unpacks path into destination; action=list shows the entries of path without extracting. Archives are limited to %d files and %d bytes uncompressed; entries with unsafe paths abort extraction, and symlinks are skipped.",
+		t.maxEntries, t.maxBytes,
+	)
+}
+
+func (t *ArchiveTool) Parameters() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"action": map[string]any{
+				"type":        "string",
+				"enum":        []string{"create", "extract", "list"},
+				"description": "What to do with the archive",
+			},
+			"path": map[string]any{
+				"type":        "string",
+				"description": "Path of the archive file",
+			},
+			"sources": map[string]any{
+				"type":        "array",
+				"items":       map[string]any{"type": "string"},
+				"description": "For create: files or directories to add. Each is stored under its base name.",
+			},
+			"destination": map[string]any{
+				"type":        "string",
+				"description": "For extract: directory to extract into. Defaults to the archive's directory.",
+			},
+			"format": map[string]any{
+				"type":        "string",
+				"enum":        []string{archiveFormatZip, archiveFormatTarGz},
+				"description": "Archive format. Inferred from the extension (.zip, .tar.gz, .tgz) when omitted.",
+			},
+			"overwrite": map[string]any{
+				"type":        "boolean",
+				"description": "Set to true to replace an existing archive (create) or existing files (extract).",
+				"default":     false,
+			},
+		},
+		"required": []string{"action", "path"},
+	}
+}
+
+func (t *ArchiveTool) Execute(ctx context.Context, args map[string]any) *ToolResult {
+	action, _ := args["action"].(string)
+	archivePath, ok := args["path"].(string)
+	if !ok || archivePath == "" {
+		return ErrorResult("path is required")
+	}
+	formatArg, _ := args["format"].(string)
+	format, err := archiveFormat(archivePath, formatArg)
+	if err != nil {
+		return ErrorResult(err.Error())
+	}
+	overwrite, _ := args["overwrite"].(bool)
+
+	switch action {
+	case "create":
+		rawSources, _ := args["sources"].([]any)
+		sources := make([]string, 0, len(rawSources))
+		for _, raw := range rawSources {
+			if s, ok := raw.(string); ok && s != "" {
+				sources = append(sources, s)
+			}
+		}
+		if len(sources) == 0 {
+			return ErrorResult("sources is required for action=create")
+		}
+		return t.create(ctx, archivePath, format, sources, overwrite)
+	case "extract":
+		dest, _ := args["destination"].(string)
+		if dest == "" {
+			dest = filepath.Dir(archivePath)
+		}
+		return t.extract(ctx, archivePath, format, dest, overwrite)
+	case "list":
+		return t.list(archivePath, format)
+	default:
+		return ErrorResult("action must be one of create, extract or list")
+	}
+}
+
+// archiveFormat returns the explicit format, or the one implied by the
+// archive's extension.
+func archiveFormat(archivePath, format string) (string, error) {
+	switch strings.ToLower(format) {
+	case archiveFormatZip:
+		return archiveFormatZip, nil
+	case archiveFormatTarGz, "tgz":
+		return archiveFormatTarGz, nil
+	case "":
+	default:
+		return "", fmt.Errorf("unsupported archive format %q; use zip or tar.gz", format)
+	}
+
+	lower := strings.ToLower(archivePath)
+	switch {
+	case strings.HasSuffix(lower, ".zip"):
+		return archiveFormatZip, nil
+	case strings.HasSuffix(lower, ".tar.gz"), strings.HasSuffix(lower, ".tgz"):
+		return archiveFormatTarGz, nil
+	}
+	return "", fmt.Errorf("cannot infer the archive format of %s; set format to zip or tar.gz", archivePath)
+}
+
+type archiveSource struct {
+	name string
+	path string
+	info os.FileInfo
+}
+
+func (t *ArchiveTool) create(
+	ctx context.Context,
+	archivePath, format string,
+	sources []string,
+	overwrite bool,
+) *ToolResult {
+	if _, err := statPath(t.fs, archivePath); err == nil && !overwrite {
+		return ErrorResult(fmt.Sprintf("archive %s already exists; set overwrite=true to replace it", archivePath))
+	}
+
+	var files []archiveSource
+	var skipped []string
+	var total int64
+	seen := make(map[string]bool)
+	add := func(name, filePath string, info os.FileInfo) error {
+		if filepath.Clean(filePath) == filepath.Clean(archivePath) {
+			return nil
+		}
+		if !info.Mode().IsRegular() {
+			skipped = append(skipped, filePath)
+			return nil
+		}
+		if seen[name] {
+			return fmt.Errorf("two sources would both be stored as %s", name)
+		}
+		if len(files) >= t.maxEntries {
+			return fmt.Errorf("archive exceeds the limit of %d files", t.maxEntries)
+		}
+		if total += info.Size(); total > t.maxBytes {
+			return fmt.Errorf("archive exceeds the limit of %d bytes", t.maxBytes)
+		}
+		seen[name] = true
+		files = append(files, archiveSource{name: name, path: filePath, info: info})
+		return nil
+	}
+
+	for _, src := range sources {
+		info, err := statPath(t.fs, src)
+		if err != nil {
+			return ErrorResult(err.Error())
+		}
+		base := path.Base(filepath.ToSlash(filepath.Clean(src)))
+		if !info.IsDir() {
+			if err := add(base, src, info); err != nil {
+				return ErrorResult(err.Error())
+			}
+			continue
+		}
+		if base == "." || base == "/" {
+			base = ""
+		}
+
+		var walkErr error
+		_, err = walkFiles(ctx, t.fs, src, nil, func(rel string, entry os.DirEntry) bool {
+			filePath := filepath.Join(src, filepath.FromSlash(rel))
+			info, err := entry.Info()
+			if err != nil {
+				skipped = append(skipped, filePath)
+				return true
+			}
+			walkErr = add(path.Join(base, rel), filePath, info)
+			return walkErr == nil
+		})
+		if err == nil {
+			err = walkErr
+		}
+		if err != nil {
+			return ErrorResult(err.Error())
+		}
+	}
+	if len(files) == 0 {
+		return ErrorResult("no regular files found in sources")
+	}
+
+	var buf bytes.Buffer
+	if err := t.writeArchive(ctx, &buf, format, files); err != nil {
+		return ErrorResult(fmt.Sprintf("failed to create archive: %v", err))
+	}
+	if err := t.fs.WriteFile(archivePath, buf.Bytes()); err != nil {
+		return ErrorResult(err.Error())
+	}
+
+	msg := fmt.Sprintf("Created %s: %d file(s), %d bytes uncompressed, %d bytes archived",
+		archivePath, len(files), total, buf.Len())
+	if len(skipped) > 0 {
+		msg += fmt.Sprintf("\nSkipped non-regular files: %s", strings.Join(skipped, ", "))
+	}
+	return SilentResult(msg)
+}
+
+func (t *ArchiveTool) writeArchive(ctx context.Context, w io.Writer, format string, files []archiveSource) error {
+	var zw *zip.Writer
+	var gw *gzip.Writer
+	var tw *tar.Writer
+	if format == archiveFormatZip {
+		zw = zip.NewWriter(w)
+	} else {
+		gw = gzip.NewWriter(w)
+		tw = tar.NewWriter(gw)
+	}
+
+	for _, file := range files {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		data, err := t.fs.ReadFile(file.path)
+		if err != nil {
+			return err
+		}
+		if zw != nil {
+			header := &zip.FileHeader{Name: file.name, Method: zip.Deflate, Modified: file.info.ModTime()}
+			header.SetMode(file.info.Mode().Perm())
+			fw, err := zw.CreateHeader(header)
+			if err != nil {
+				return err
+			}
+			if _, err := fw.Write(data); err != nil {
+				return err
+			}
+			continue
+		}
+		header := &tar.Header{
+			Typeflag: tar.TypeReg,
+			Name:     file.name,
+			Mode:     int64(file.info.Mode().Perm()),
+			Size:     int64(len(data)),
+			ModTime:  file.info.ModTime().Truncate(time.Second),
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if _, err := tw.Write(data); err != nil {
+			return err
+		}
+	}
+
+	if zw != nil {
+		return zw.Close()
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gw.Close()
+}
+
+type archiveFile struct {
+	name string
+	data []byte
+}
+
+func (t *ArchiveTool) extract(ctx context.Context, archivePath, format, dest string, overwrite bool) *ToolResult {
+	data, err := t.readArchive(archivePath)
+	if err != nil {
+		return ErrorResult(err.Error())
+	}
+
+	var files []archiveFile
+	var skipped []string
+	var total int64
+	err = walkArchive(data, format, func(entry archiveEntry, r io.Reader) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		name, err := safeArchivePath(entry.name)
+		if err != nil {
+			return err
+		}
+		switch {
+		case entry.dir || name == "":
+			return nil
+		case r == nil:
+			skipped = append(skipped, entry.name)
+			return nil
+		}
+		if len(files) >= t.maxEntries {
+			return fmt.Errorf("archive has more than %d files", t.maxEntries)
+		}
+		// Header sizes can lie, so the limit is enforced on the bytes read.
+		content, err := io.ReadAll(io.LimitReader(r, t.maxBytes-total+1))
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", entry.name, err)
+		}
+		if total += int64(len(content)); total > t.maxBytes {
+			return fmt.Errorf("archive expands to more than %d bytes", t.maxBytes)
+		}
+		files = append(files, archiveFile{name: name, data: content})
+		return nil
+	})
+	if err != nil {
+		return ErrorResult(fmt.Sprintf("failed to extract %s: %v; nothing was written", archivePath, err))
+	}
+
+	// Hold every target from the existence check until it is written.
+	targets := make([]string, 0, len(files))
+	for _, file := range files {
+		targets = append(targets, filepath.Join(dest, filepath.FromSlash(file.name)))
+	}
+	unlock, err := lockPaths(t.fs, targets...)
+	if err != nil {
+		return ErrorResult(err.Error())
+	}
+	defer unlock()
+
+	if !overwrite {
+		for _, file := range files {
+			target := filepath.Join(dest, filepath.FromSlash(file.name))
+			if _, err := statPath(t.fs, target); err == nil {
+				return ErrorResult(fmt.Sprintf(
+					"%s already exists; set overwrite=true to replace existing files",
+					target,
+				))
+			}
+		}
+	}
+
+	for _, file := range files {
+		target := filepath.Join(dest, filepath.FromSlash(file.name))
+		if err := t.fs.WriteFile(target, file.data); err != nil {
+			return ErrorResult(err.Error())
+		}
+	}
+
+	msg := fmt.Sprintf("Extracted %d file(s), %d bytes from %s into %s", len(files), total, archivePath, dest)
+	if len(skipped) > 0 {
+		msg += fmt.Sprintf("\nSkipped symlinks and special entries: %s", strings.Join(skipped, ", "))
+	}
+	return SilentResult(msg)
+}
+
+func (t *ArchiveTool) list(archivePath, format string) *ToolResult {
+	data, err := t.readArchive(archivePath)
+	if err != nil {
+		return ErrorResult(err.Error())
+	}
+
+	var lines []string
+	var count int
+	var total int64
+	err = walkArchive(data, format, func(entry archiveEntry, _ io.Reader) error {
+		count++
+		if count > maxArchiveListEntries {
+			return nil
+		}
+		switch _, unsafe := safeArchivePath(entry.name); {
+		case unsafe != nil:
+			lines = append(lines, entry.name+" [unsafe path]")
+		case entry.dir:
+			lines = append(lines, strings.TrimSuffix(entry.name, "/")+"/")
+		case entry.regular:
+			total += entry.size
+			lines = append(lines, fmt.Sprintf("%s (%s)", entry.name, formatFileSize(entry.size)))
+		default:
+			lines = append(lines, entry.name+" [skipped on extract]")
+		}
+		return nil
+	})
+	if err != nil {
+		return ErrorResult(fmt.Sprintf("failed to read %s: %v", archivePath, err))
+	}
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "%s (%s, %d entries)\n", archivePath, format, count)
+	out.WriteString(strings.Join(lines, "\n"))
+	if count > maxArchiveListEntries {
+		fmt.Fprintf(&out, "\n[... %d more entries not shown]", count-maxArchiveListEntries)
+	}
+	return NewToolResult(out.String())
+}
+
+func (t *ArchiveTool) readArchive(archivePath string) ([]byte, error) {
+	info, err := statPath(t.fs, archivePath)
+	if err != nil {
+		return nil, err
+	}
+	if info.Size() > t.maxBytes {
+		return nil, fmt.Errorf("archive %s is larger than the limit of %d bytes", archivePath, t.maxBytes)
+	}
+	return t.fs.ReadFile(archivePath)
+}
+
+type archiveEntry struct {
+	name    string
+	size    int64
+	dir     bool
+	regular bool
+}
+
+// walkArchive calls visit for every entry of a zip or tar.gz archive. r holds
+// the contents of regular files and is nil for everything else.
+func walkArchive(data []byte, format string, visit func(entry archiveEntry, r io.Reader) error) error {
+	if format == archiveFormatZip {
+		zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			return err
+		}
+		for _, f := range zr.File {
+			mode := f.Mode()
+			entry := archiveEntry{
+				name:    f.Name,
+				size:    int64(f.UncompressedSize64),
+				dir:     mode.IsDir(),
+				regular: mode.IsRegular(),
+			}
+			if !entry.regular {
+				if err := visit(entry, nil); err != nil {
+					return err
+				}
+				continue
+			}
+			rc, err := f.Open()
+			if err != nil {
+				return err
+			}
+			err = visit(entry, rc)
+			_ = rc.Close()
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	gr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	defer gr.Close()
+	tr := tar.NewReader(gr)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if header.Typeflag == tar.TypeXGlobalHeader {
+			continue
+		}
+		mode := header.FileInfo().Mode()
+		entry := archiveEntry{
+			name:    header.Name,
+			size:    header.Size,
+			dir:     mode.IsDir(),
+			regular: mode.IsRegular(),
+		}
+		var r io.Reader
+		if entry.regular {
+			r = tr
+		}
+		if err := visit(entry, r); err != nil {
+			return err
+		}
+	}
+}
+
+// safeArchivePath cleans an entry name and rejects names that are absolute
+// or climb out of the extraction directory (zip-slip). The archive root
+// itself is returned as "".
+func safeArchivePath(name string) (string, error) {
+	clean := path.Clean(strings.ReplaceAll(name, `\`, "/"))
+	if clean == "." {
+		return "", nil
+	}
+	if !filepath.IsLocal(filepath.FromSlash(clean)) || strings.Contains(clean, ":") {
+		return "", fmt.Errorf("entry %q has an unsafe path", name)
+	}
+	return clean, nil
+}
@@ -0,0 +1,140 @@
+package fstools
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArchiveTool_CreateListExtract(t *testing.T) {
+	for _, name := range []string{"bundle.zip", "bundle.tar.gz"} {
+		t.Run(name, func(t *testing.T) {
+			workspace := t.TempDir()
+			writeFindFixture(t, workspace, map[string]string{
+				"src/main.go":     "package main\n",
+				"src/lib/util.go": "package lib\n",
+				"README.md":       "# readme\n",
+			})
+			tool := NewArchiveTool(workspace, true)
+			ctx := context.Background()
+
+			result := tool.Execute(ctx, map[string]any{
+				"action":  "create",
+				"path":    "out/" + name,
+				"sources": []any{"src", "README.md"},
+			})
+			require.False(t, result.IsError, result.ForLLM)
+			assert.Contains(t, result.ForLLM, "3 file(s)")
+
+			result = tool.Execute(ctx, map[string]any{"action": "list", "path": "out/" + name})
+			require.False(t, result.IsError, result.ForLLM)
+			assert.Contains(t, result.ForLLM, "3 entries")
+			assert.Contains(t, result.ForLLM, "src/lib/util.go")
+			assert.Contains(t, result.ForLLM, "README.md")
+
+			result = tool.Execute(ctx, map[string]any{
+				"action":      "extract",
+				"path":        "out/" + name,
+				"destination": "unpacked",
+			})
+			require.False(t, result.IsError, result.ForLLM)
+			assertFileContent(t, filepath.Join(workspace, "unpacked", "src", "lib", "util.go"), "package lib\n")
+			assertFileContent(t, filepath.Join(workspace, "unpacked", "README.md"), "# readme\n")
+
+			result = tool.Execute(ctx, map[string]any{
+				"action":      "extract",
+				"path":        "out/" + name,
+				"destination": "unpacked",
+			})
+			require.True(t, result.IsError)
+			assert.Contains(t, result.ForLLM, "overwrite=true")
+		})
+	}
+}
+
+func TestArchiveTool_ExtractRejectsZipSlip(t *testing.T) {
+	workspace := t.TempDir()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, name := range []string{"ok.txt", "../evil.txt"} {
+		w, err := zw.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write([]byte("x"))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+	require.NoError(t, os.WriteFile(filepath.Join(workspace, "bad.zip"), buf.Bytes(), 0o644))
+
+	tool := NewArchiveTool(workspace, true)
+	result := tool.Execute(context.Background(), map[string]any{
+		"action":      "extract",
+		"path":        "bad.zip",
+		"destination": "out",
+	})
+	require.True(t, result.IsError)
+	assert.Contains(t, result.ForLLM, "unsafe path")
+	assert.Contains(t, result.ForLLM, "nothing was written")
+	_, err := os.Stat(filepath.Join(workspace, "out", "ok.txt"))
+	assert.True(t, os.IsNotExist(err))
+
+	result = tool.Execute(context.Background(), map[string]any{"action": "list", "path": "bad.zip"})
+	require.False(t, result.IsError, result.ForLLM)
+	assert.Contains(t, result.ForLLM, "../evil.txt [unsafe path]")
+}
+
+func TestArchiveTool_ExtractSkipsSymlinksAndEnforcesSize(t *testing.T) {
+	workspace := t.TempDir()
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "link", Typeflag: tar.TypeSymlink, Linkname: "/etc/passwd"}))
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "data.bin", Typeflag: tar.TypeReg, Mode: 0o644, Size: 4096}))
+	_, err := tw.Write(bytes.Repeat([]byte("a"), 4096))
+	require.NoError(t, err)
+	require.NoError(t, tw.Close())
+	require.NoError(t, gw.Close())
+	require.NoError(t, os.WriteFile(filepath.Join(workspace, "in.tgz"), buf.Bytes(), 0o644))
+
+	tool := NewArchiveTool(workspace, true)
+	result := tool.Execute(context.Background(), map[string]any{"action": "extract", "path": "in.tgz"})
+	require.False(t, result.IsError, result.ForLLM)
+	assert.Contains(t, result.ForLLM, "Skipped symlinks and special entries: link")
+	_, err = os.Lstat(filepath.Join(workspace, "link"))
+	assert.True(t, os.IsNotExist(err))
+
+	tool.maxBytes = 1024
+	result = tool.Execute(context.Background(), map[string]any{
+		"action":      "extract",
+		"path":        "in.tgz",
+		"destination": "small",
+	})
+	require.True(t, result.IsError)
+	assert.Contains(t, result.ForLLM, "more than 1024 bytes")
+}
+
+func TestArchiveTool_InvalidArguments(t *testing.T) {
+	workspace := t.TempDir()
+	writeFindFixture(t, workspace, map[string]string{"a.txt": "a"})
+	tool := NewArchiveTool(workspace, true)
+
+	tests := map[string]map[string]any{
+		"unknown action":  {"action": "compress", "path": "a.zip"},
+		"unknown format":  {"action": "list", "path": "a.rar"},
+		"missing sources": {"action": "create", "path": "a.zip"},
+		"missing archive": {"action": "list", "path": "missing.zip"},
+		"not an archive":  {"action": "list", "path": "a.txt", "format": "zip"},
+	}
+	for name, args := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.True(t, tool.Execute(context.Background(), args).IsError)
+		})
+	}
+}
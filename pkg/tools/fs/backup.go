@@ -0,0 +1,239 @@
+package fstools
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sipeed/picoclaw/pkg/fileutil"
+	"github.com/sipeed/picoclaw/pkg/logger"
+)
+
+const (
+	// DefaultMaxFileBackups is the number of versions kept per file when the
+	// configured limit is not positive.
+	DefaultMaxFileBackups = 10
+	// maxBackupFileSize skips backups of files too large to copy cheaply on
+	// every write.
+	maxBackupFileSize = 10 * 1024 * 1024
+
+	backupSuffix = ".bak"
+	// absentSuffix marks a version where the file did not exist, so undoing
+	// the change that created it removes the file again.
+	absentSuffix   = ".absent"
+	backupPathFile = "path"
+)
+
+// FileBackups keeps the previous versions of files changed by the filesystem
+// tools under <workspace>/state/file_backups, one directory per file, so
+// undo_file can roll back destructive edits.
+type FileBackups struct {
+	workspace string
+	dir       string
+	keep      int
+	mu        sync.Mutex
+}
+
+// FileBackup describes one saved version of a file.
+type FileBackup struct {
+	Time time.Time
+	Size int64
+	// Absent is set when the file did not exist before the change.
+	Absent bool
+
+	name string
+}
+
+// NewFileBackups creates a backup store for workspace that keeps up to keep
+// versions of each file.
+func NewFileBackups(workspace string, keep int) *FileBackups {
+	if keep <= 0 {
+		keep = DefaultMaxFileBackups
+	}
+	return &FileBackups{
+		workspace: workspace,
+		dir:       filepath.Join(workspace, "state", "file_backups"),
+		keep:      keep,
+	}
+}
+
+func (b *FileBackups) fileDir(abs string) string {
+	sum := sha256.Sum256([]byte(abs))
+	return filepath.Join(b.dir, hex.EncodeToString(sum[:8]))
+}
+
+// save records data, or the file's absence when absent is set, as the newest
+// version of abs and prunes versions beyond the limit.
+func (b *FileBackups) save(abs string, data []byte, absent bool) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	dir := b.fileDir(abs)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+	if err := fileutil.WriteFileAtomic(filepath.Join(dir, backupPathFile), []byte(abs), 0o600); err != nil {
+		return err
+	}
+	suffix := backupSuffix
+	if absent {
+		suffix, data = absentSuffix, nil
+	}
+	stamp := time.Now().UnixNano()
+	name := strconv.FormatInt(stamp, 10) + suffix
+	for {
+		if _, err := os.Lstat(filepath.Join(dir, name)); errors.Is(err, fs.ErrNotExist) {
+			break
+		}
+		stamp++
+		name = strconv.FormatInt(stamp, 10) + suffix
+	}
+	if err := fileutil.WriteFileAtomic(filepath.Join(dir, name), data, 0o600); err != nil {
+		return err
+	}
+
+	versions, err := b.listLocked(abs)
+	if err != nil {
+		return err
+	}
+	for _, old := range versions[min(b.keep, len(versions)):] {
+		_ = os.Remove(filepath.Join(dir, old.name))
+	}
+	return nil
+}
+
+// List returns the saved versions of the file at abs, newest first.
+func (b *FileBackups) List(abs string) ([]FileBackup, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.listLocked(abs)
+}
+
+func (b *FileBackups) listLocked(abs string) ([]FileBackup, error) {
+	entries, err := os.ReadDir(b.fileDir(abs))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var versions []FileBackup
+	for _, entry := range entries {
+		name := entry.Name()
+		stem, absent := strings.CutSuffix(name, absentSuffix)
+		if !absent {
+			var ok bool
+			if stem, ok = strings.CutSuffix(name, backupSuffix); !ok {
+				continue
+			}
+		}
+		stamp, err := strconv.ParseInt(stem, 10, 64)
+		if err != nil {
+			continue
+		}
+		version := FileBackup{Time: time.Unix(0, stamp), Absent: absent, name: name}
+		if info, err := entry.Info(); err == nil {
+			version.Size = info.Size()
+		}
+		versions = append(versions, version)
+	}
+	slices.SortFunc(versions, func(a, b FileBackup) int { return b.Time.Compare(a.Time) })
+	return versions, nil
+}
+
+// read returns the content saved for version of abs.
+func (b *FileBackups) read(abs string, version FileBackup) ([]byte, error) {
+	return os.ReadFile(filepath.Join(b.fileDir(abs), version.name))
+}
+
+// drop deletes version of abs once it has been restored.
+func (b *FileBackups) drop(abs string, version FileBackup) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return os.Remove(filepath.Join(b.fileDir(abs), version.name))
+}
+
+// backupFs records the current content of a file in FileBackups before it is
+// overwritten or deleted. Backup failures are logged and never block the
+// write itself.
+type backupFs struct {
+	fileSystem
+	backups      *FileBackups
+	hostRelative bool
+}
+
+func newBackupFs(inner fileSystem, backups *FileBackups) *backupFs {
+	return &backupFs{fileSystem: inner, backups: backups, hostRelative: isHostRelative(inner)}
+}
+
+func (b *backupFs) WriteFile(path string, data []byte) error {
+	b.backup(path, data)
+	return b.fileSystem.WriteFile(path, data)
+}
+
+func (b *backupFs) Remove(path string) error {
+	b.backup(path, nil)
+	return b.fileSystem.Remove(path)
+}
+
+// backup saves the file at path unless it already holds next. Directories,
+// special files and files over maxBackupFileSize are not backed up.
+func (b *backupFs) backup(path string, next []byte) {
+	abs := absoluteToolPath(b.backups.workspace, path, b.hostRelative)
+	info, err := b.fileSystem.Lstat(path)
+	var saveErr error
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		if next == nil {
+			return
+		}
+		saveErr = b.backups.save(abs, nil, true)
+	case err != nil, !info.Mode().IsRegular(), info.Size() > maxBackupFileSize:
+		return
+	default:
+		current, err := b.fileSystem.ReadFile(path)
+		if err != nil || (next != nil && string(current) == string(next)) {
+			return
+		}
+		saveErr = b.backups.save(abs, current, false)
+	}
+	if saveErr != nil {
+		logger.WarnCF("tool", "Failed to back up file before change",
+			map[string]any{"path": path, "error": saveErr.Error()})
+	}
+}
+
+// backupTarget is implemented by the tools that change existing files.
+type backupTarget interface {
+	applyFileBackups(backups *FileBackups)
+}
+
+// ApplyFileBackups makes a filesystem tool record previous file versions in
+// backups before changing them. It reports false for tools that never modify
+// files, and is a no-op when backups is nil. Apply it before ApplyFilePolicy
+// so that rejected writes are not backed up.
+func ApplyFileBackups(tool any, backups *FileBackups) bool {
+	target, ok := tool.(backupTarget)
+	if ok && backups != nil {
+		target.applyFileBackups(backups)
+	}
+	return ok
+}
+
+func (t *WriteFileTool) applyFileBackups(b *FileBackups)    { t.fs = newBackupFs(t.fs, b) }
+func (t *EditFileTool) applyFileBackups(b *FileBackups)     { t.fs = newBackupFs(t.fs, b) }
+func (t *AppendFileTool) applyFileBackups(b *FileBackups)   { t.fs = newBackupFs(t.fs, b) }
+func (t *MultiEditTool) applyFileBackups(b *FileBackups)    { t.fs = newBackupFs(t.fs, b) }
+func (t *ApplyPatchTool) applyFileBackups(b *FileBackups)   { t.fs = newBackupFs(t.fs, b) }
+func (t *DeleteFileTool) applyFileBackups(b *FileBackups)   { t.fs = newBackupFs(t.fs, b) }
+func (t *CopyFileTool) applyFileBackups(b *FileBackups)     { t.fs = newBackupFs(t.fs, b) }
+func (t *ArchiveTool) applyFileBackups(b *FileBackups)      { t.fs = newBackupFs(t.fs, b) }
+func (t *WriteSessionTool) applyFileBackups(b *FileBackups) { t.fs = newBackupFs(t.fs, b) }
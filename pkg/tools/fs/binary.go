@@ -0,0 +1,72 @@
+package fstools
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	binaryPreviewHexBytes   = 256
+	binaryPreviewMinString  = 4
+	binaryPreviewMaxStrings = 32
+	binaryPreviewMaxString  = 120
+)
+
+// formatBinaryPreview describes binary data without dumping it into the
+// model's context as text: a hex dump of the first bytes, labelled with their
+// file offsets, followed by the printable ASCII strings found in data.
+func formatBinaryPreview(data []byte, offset int64) string {
+	var out strings.Builder
+	out.WriteString("Binary content is not shown as text. Call read_file with base64=true to get the raw bytes.\n")
+
+	hexLen := min(len(data), binaryPreviewHexBytes)
+	fmt.Fprintf(&out, "\nhex (first %d bytes):\n", hexLen)
+	for i := 0; i < hexLen; i += 16 {
+		row := data[i:min(i+16, hexLen)]
+		ascii := make([]byte, len(row))
+		for j, b := range row {
+			ascii[j] = '.'
+			if b >= 0x20 && b < 0x7f {
+				ascii[j] = b
+			}
+		}
+		fmt.Fprintf(&out, "%08x  %-47s  |%s|\n", offset+int64(i), fmt.Sprintf("% x", row), ascii)
+	}
+
+	found := printableStrings(data, binaryPreviewMinString, binaryPreviewMaxStrings+1)
+	if len(found) == 0 {
+		return out.String()
+	}
+	out.WriteString("\nstrings:\n")
+	for i, s := range found {
+		if i == binaryPreviewMaxStrings {
+			out.WriteString("...\n")
+			break
+		}
+		if len(s) > binaryPreviewMaxString {
+			s = s[:binaryPreviewMaxString] + "..."
+		}
+		out.WriteString(s + "\n")
+	}
+	return out.String()
+}
+
+// printableStrings returns up to limit runs of at least minLen printable
+// ASCII characters, like strings(1).
+func printableStrings(data []byte, minLen, limit int) []string {
+	var found []string
+	start := -1
+	for i := 0; i <= len(data) && len(found) < limit; i++ {
+		if i < len(data) && data[i] >= 0x20 && data[i] < 0x7f {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 && i-start >= minLen {
+			found = append(found, string(data[start:i]))
+		}
+		start = -1
+	}
+	return found
+}
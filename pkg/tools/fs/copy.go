@@ -0,0 +1,235 @@
+package fstools
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+const (
+	// MaxCopyTotalSize caps the bytes a single copy_file call may duplicate.
+	MaxCopyTotalSize = 50 * 1024 * 1024
+	// MaxCopyEntries caps the number of files and directories a recursive
+	// copy may create.
+	MaxCopyEntries = 1000
+)
+
+// CopyFileTool copies a file, or a directory tree when recursive is set.
+// The whole copy is planned before anything is written, so limit and
+// overwrite violations are reported without leaving a partial copy behind.
+type CopyFileTool struct {
+	fs        fileSystem
+	workspace string
+	maxBytes  int64
+	maxFiles  int
+}
+
+// NewCopyFileTool creates a new CopyFileTool with optional directory restriction.
+func NewCopyFileTool(workspace string, restrict bool, allowPaths ...[]*regexp.Regexp) *CopyFileTool {
+	var patterns []*regexp.Regexp
+	if len(allowPaths) > 0 {
+		patterns = allowPaths[0]
+	}
+	return &CopyFileTool{
+		fs:        buildFs(workspace, restrict, patterns),
+		workspace: workspace,
+		maxBytes:  MaxCopyTotalSize,
+		maxFiles:  MaxCopyEntries,
+	}
+}
+
+func (t *CopyFileTool) Name() string {
+	return "copy_file"
+}
+
+func (t *CopyFileTool) Description() string {
+	return fmt.Sprintf(
+		"Copy a file to a new path, or a directory tree when recursive=true. Existing destination files are only replaced when overwrite=true. A single call copies at most %d files and %d bytes; symlinks are skipped.",
+		t.maxFiles, t.maxBytes,
+	)
+}
+
+func (t *CopyFileTool) Parameters() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"source": map[string]any{
+				"type":        "string",
+				"description": "Path of the file or directory to copy",
+			},
+			"destination": map[string]any{
+				"type":        "string",
+				"description": "Path to copy to. For directories this is the new directory's path.",
+			},
+			"recursive": map[string]any{
+				"type":        "boolean",
+				"description": "Set to true to copy a directory and everything in it.",
+				"default":     false,
+			},
+			"overwrite": map[string]any{
+				"type":        "boolean",
+				"description": "Set to true to replace destination files that already exist.",
+				"default":     false,
+			},
+		},
+		"required": []string{"source", "destination"},
+	}
+}
+
+type copyEntry struct {
+	src  string
+	dst  string
+	perm fs.FileMode
+}
+
+type copyPlan struct {
+	entries []copyEntry
+	dirs    []string
+	skipped []string
+	bytes   int64
+}
+
+func (t *CopyFileTool) Execute(ctx context.Context, args map[string]any) *ToolResult {
+	src, ok := args["source"].(string)
+	if !ok || src == "" {
+		return ErrorResult("source is required")
+	}
+	dst, ok := args["destination"].(string)
+	if !ok || dst == "" {
+		return ErrorResult("destination is required")
+	}
+	recursive, _ := args["recursive"].(bool)
+	overwrite, _ := args["overwrite"].(bool)
+
+	srcAbs, dstAbs := t.absPath(src), t.absPath(dst)
+	if srcAbs == dstAbs {
+		return ErrorResult("source and destination are the same path")
+	}
+	if isWithinWorkspace(dstAbs, srcAbs) {
+		return ErrorResult("destination cannot be inside the source directory")
+	}
+
+	plan := &copyPlan{}
+	if err := t.collect(src, dst, recursive, true, plan); err != nil {
+		return ErrorResult(err.Error())
+	}
+
+	// Hold every destination from the existence check until it is written.
+	targets := make([]string, 0, len(plan.entries))
+	for _, entry := range plan.entries {
+		targets = append(targets, entry.dst)
+	}
+	unlock, err := lockPaths(t.fs, targets...)
+	if err != nil {
+		return ErrorResult(err.Error())
+	}
+	defer unlock()
+
+	if !overwrite {
+		for _, entry := range plan.entries {
+			if t.exists(entry.dst) {
+				return ErrorResult(fmt.Sprintf(
+					"destination file %s already exists; set overwrite=true to replace it",
+					entry.dst,
+				))
+			}
+		}
+	}
+
+	// Directories are created first so empty ones are copied too.
+	for _, dir := range plan.dirs {
+		if err := t.fs.MkdirAll(dir); err != nil {
+			return ErrorResult(err.Error())
+		}
+	}
+	for _, entry := range plan.entries {
+		if err := ctx.Err(); err != nil {
+			return ErrorResult(fmt.Sprintf("copy cancelled: %v", err))
+		}
+		data, err := t.fs.ReadFile(entry.src)
+		if err != nil {
+			return ErrorResult(err.Error())
+		}
+		if err := t.fs.WriteFile(entry.dst, data); err != nil {
+			return ErrorResult(err.Error())
+		}
+		if err := t.fs.Chmod(entry.dst, entry.perm); err != nil {
+			return ErrorResult(err.Error())
+		}
+	}
+
+	msg := fmt.Sprintf("Copied %d file(s), %d bytes: %s -> %s", len(plan.entries), plan.bytes, src, dst)
+	if len(plan.skipped) > 0 {
+		msg += fmt.Sprintf("\nSkipped symlinks: %s", strings.Join(plan.skipped, ", "))
+	}
+	return SilentResult(msg)
+}
+
+// collect walks src and records every regular file and directory to copy,
+// enforcing the size and entry limits as it goes.
+func (t *CopyFileTool) collect(src, dst string, recursive, top bool, plan *copyPlan) error {
+	info, err := statPath(t.fs, src)
+	if err != nil {
+		return err
+	}
+
+	if !info.IsDir() {
+		if !info.Mode().IsRegular() {
+			if top {
+				return fmt.Errorf("source %s is not a regular file", src)
+			}
+			plan.skipped = append(plan.skipped, src)
+			return nil
+		}
+		if len(plan.entries)+len(plan.dirs) >= t.maxFiles {
+			return fmt.Errorf("copy exceeds the limit of %d files", t.maxFiles)
+		}
+		if plan.bytes+info.Size() > t.maxBytes {
+			return fmt.Errorf("copy exceeds the limit of %d bytes", t.maxBytes)
+		}
+		plan.entries = append(plan.entries, copyEntry{src: src, dst: dst, perm: info.Mode().Perm()})
+		plan.bytes += info.Size()
+		return nil
+	}
+
+	if !recursive {
+		return fmt.Errorf("source %s is a directory; set recursive=true to copy it", src)
+	}
+
+	if len(plan.entries)+len(plan.dirs) >= t.maxFiles {
+		return fmt.Errorf("copy exceeds the limit of %d files", t.maxFiles)
+	}
+	plan.dirs = append(plan.dirs, dst)
+
+	entries, err := t.fs.ReadDir(src)
+	if err != nil {
+		return fmt.Errorf("failed to read directory: %w", err)
+	}
+	for _, entry := range entries {
+		childSrc := filepath.Join(src, entry.Name())
+		if entry.Type()&fs.ModeSymlink != 0 {
+			plan.skipped = append(plan.skipped, childSrc)
+			continue
+		}
+		if err := t.collect(childSrc, filepath.Join(dst, entry.Name()), recursive, false, plan); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *CopyFileTool) exists(path string) bool {
+	f, err := t.fs.Open(path)
+	if err != nil {
+		return false
+	}
+	_ = f.Close()
+	return true
+}
+
+func (t *CopyFileTool) absPath(path string) string {
+	return absoluteToolPath(t.workspace, path, isHostRelative(t.fs))
+}
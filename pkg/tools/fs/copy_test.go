@@ -0,0 +1,169 @@
+package fstools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCopyFileTool_Restricted_CopiesFile(t *testing.T) {
+	workspace := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(workspace, "config.yaml"), []byte("a: 1\n"), 0o644))
+
+	tool := NewCopyFileTool(workspace, true)
+	result := tool.Execute(context.Background(), map[string]any{
+		"source":      "config.yaml",
+		"destination": "backup/config.yaml.bak",
+	})
+
+	assert.False(t, result.IsError, "Expected success, got: %s", result.ForLLM)
+	assert.Contains(t, result.ForLLM, "Copied 1 file(s), 5 bytes")
+	data, err := os.ReadFile(filepath.Join(workspace, "backup", "config.yaml.bak"))
+	require.NoError(t, err)
+	assert.Equal(t, "a: 1\n", string(data))
+}
+
+func TestCopyFileTool_Restricted_CopiesDirectoryRecursively(t *testing.T) {
+	workspace := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(workspace, "tmpl", "nested"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(workspace, "tmpl", "a.txt"), []byte("A"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(workspace, "tmpl", "nested", "b.txt"), []byte("BB"), 0o644))
+
+	tool := NewCopyFileTool(workspace, true)
+	result := tool.Execute(context.Background(), map[string]any{
+		"source":      "tmpl",
+		"destination": "project",
+		"recursive":   true,
+	})
+
+	assert.False(t, result.IsError, "Expected success, got: %s", result.ForLLM)
+	assert.Contains(t, result.ForLLM, "Copied 2 file(s), 3 bytes")
+	data, err := os.ReadFile(filepath.Join(workspace, "project", "nested", "b.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "BB", string(data))
+}
+
+func TestCopyFileTool_DirectoryRequiresRecursive(t *testing.T) {
+	workspace := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(workspace, "tmpl"), 0o755))
+
+	tool := NewCopyFileTool(workspace, true)
+	result := tool.Execute(context.Background(), map[string]any{
+		"source":      "tmpl",
+		"destination": "project",
+	})
+
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.ForLLM, "recursive=true")
+}
+
+func TestCopyFileTool_RefusesOverwriteByDefault(t *testing.T) {
+	workspace := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(workspace, "src.txt"), []byte("new"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(workspace, "dst.txt"), []byte("old"), 0o644))
+
+	tool := NewCopyFileTool(workspace, true)
+	args := map[string]any{"source": "src.txt", "destination": "dst.txt"}
+
+	result := tool.Execute(context.Background(), args)
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.ForLLM, "overwrite=true")
+	data, _ := os.ReadFile(filepath.Join(workspace, "dst.txt"))
+	assert.Equal(t, "old", string(data))
+
+	args["overwrite"] = true
+	result = tool.Execute(context.Background(), args)
+	assert.False(t, result.IsError, "Expected success, got: %s", result.ForLLM)
+	data, _ = os.ReadFile(filepath.Join(workspace, "dst.txt"))
+	assert.Equal(t, "new", string(data))
+}
+
+func TestCopyFileTool_EnforcesLimitsBeforeWriting(t *testing.T) {
+	workspace := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(workspace, "src"), 0o755))
+	for _, name := range []string{"a", "b", "c"} {
+		require.NoError(t, os.WriteFile(filepath.Join(workspace, "src", name), []byte("1234"), 0o644))
+	}
+
+	tool := NewCopyFileTool(workspace, true)
+	tool.maxFiles = 2
+	result := tool.Execute(context.Background(), map[string]any{
+		"source": "src", "destination": "dst", "recursive": true,
+	})
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.ForLLM, "limit of 2 files")
+
+	tool = NewCopyFileTool(workspace, true)
+	tool.maxBytes = 10
+	result = tool.Execute(context.Background(), map[string]any{
+		"source": "src", "destination": "dst", "recursive": true,
+	})
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.ForLLM, "limit of 10 bytes")
+
+	_, err := os.Stat(filepath.Join(workspace, "dst"))
+	assert.True(t, os.IsNotExist(err), "nothing should be written when a limit is exceeded")
+}
+
+func TestCopyFileTool_RejectsCopyIntoItself(t *testing.T) {
+	workspace := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(workspace, "src"), 0o755))
+
+	tool := NewCopyFileTool(workspace, true)
+	result := tool.Execute(context.Background(), map[string]any{
+		"source": "src", "destination": "src/inner", "recursive": true,
+	})
+
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.ForLLM, "inside the source")
+}
+
+func TestCopyFileTool_Restricted_OutsideWorkspace(t *testing.T) {
+	workspace := t.TempDir()
+	outside := filepath.Join(t.TempDir(), "secret.txt")
+	require.NoError(t, os.WriteFile(outside, []byte("secret"), 0o644))
+
+	tool := NewCopyFileTool(workspace, true)
+	result := tool.Execute(context.Background(), map[string]any{
+		"source": outside, "destination": "copy.txt",
+	})
+
+	assert.True(t, result.IsError)
+	_, err := os.Stat(filepath.Join(workspace, "copy.txt"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestCopyFileTool_KeepsModeAndEmptyDirectories(t *testing.T) {
+	workspace := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(workspace, "tool", "cache"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(workspace, "tool", "run.sh"), []byte("#!/bin/sh\n"), 0o755))
+
+	tool := NewCopyFileTool(workspace, true)
+	result := tool.Execute(context.Background(), map[string]any{
+		"source": "tool", "destination": "copy", "recursive": true,
+	})
+	require.False(t, result.IsError, result.ForLLM)
+
+	info, err := os.Stat(filepath.Join(workspace, "copy", "run.sh"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o755), info.Mode().Perm())
+	assert.DirExists(t, filepath.Join(workspace, "copy", "cache"))
+}
+
+func TestCopyFileTool_Unrestricted_RelativePathsUseWorkingDirectory(t *testing.T) {
+	cwd := t.TempDir()
+	t.Chdir(cwd)
+	require.NoError(t, os.Mkdir(filepath.Join(cwd, "src"), 0o755))
+
+	tool := NewCopyFileTool(t.TempDir(), false)
+	result := tool.Execute(context.Background(), map[string]any{
+		"source": "src", "destination": filepath.Join(cwd, "src", "inner"), "recursive": true,
+	})
+
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.ForLLM, "inside the source")
+}
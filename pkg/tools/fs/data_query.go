@@ -0,0 +1,742 @@
+package fstools
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/rand/v2"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// MaxDataQueryFileSize caps the files query_data loads into memory. The
+	// output is capped separately, so files far larger than read_file accepts
+	// can still be summarized.
+	MaxDataQueryFileSize = 50 * 1024 * 1024
+
+	defaultDataQueryLimit = 20
+	maxDataQueryLimit     = 500
+	maxDataCellWidth      = 60
+)
+
+// DataQueryTool loads a CSV, TSV, JSON or JSON Lines file as a table and
+// returns a filtered, projected, sorted or aggregated slice of it as a compact
+// text table, so the model can inspect data without reading the whole file.
+type DataQueryTool struct {
+	fs        fileSystem
+	maxOutput int64
+}
+
+// NewDataQueryTool creates a new DataQueryTool with optional directory
+// restriction. maxReadFileSize caps the size of the returned table.
+func NewDataQueryTool(
+	workspace string,
+	restrict bool,
+	maxReadFileSize int,
+	allowPaths ...[]*regexp.Regexp,
+) *DataQueryTool {
+	var patterns []*regexp.Regexp
+	if len(allowPaths) > 0 {
+		patterns = allowPaths[0]
+	}
+	maxOutput := int64(maxReadFileSize)
+	if maxOutput <= 0 {
+		maxOutput = MaxReadFileSize
+	}
+	return &DataQueryTool{fs: buildFs(workspace, restrict, patterns), maxOutput: maxOutput}
+}
+
+func (t *DataQueryTool) Name() string {
+	return "query_data"
+}
+
+func (t *DataQueryTool) Description() string {
+	return "Query a CSV, TSV, JSON (array of objects) or JSON Lines file as a table: select columns, filter rows, sort, take the first rows or a random sample, and aggregate with count/sum/avg/min/max/distinct, optionally grouped by columns. Returns a compact table plus the row counts. Much cheaper than reading a whole data file; call it with only a path to see the columns and first rows."
+}
+
+func (t *DataQueryTool) Parameters() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"path": map[string]any{
+				"type":        "string",
+				"description": "Path to the data file",
+			},
+			"format": map[string]any{
+				"type":        "string",
+				"enum":        []string{"csv", "tsv", "json", "jsonl"},
+				"description": "File format. Inferred from the extension when omitted",
+			},
+			"columns": map[string]any{
+				"type":        "array",
+				"items":       map[string]any{"type": "string"},
+				"description": "Columns to return, in order. Defaults to all columns",
+			},
+			"where": map[string]any{
+				"type": "array",
+				"items": map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"column": map[string]any{"type": "string"},
+						"op": map[string]any{
+							"type": "string",
+							"enum": []string{"=", "!=", ">", ">=", "<", "<=", "contains", "empty", "not_empty"},
+						},
+						"value": map[string]any{
+							"description": "Value to compare with; numbers are compared numerically",
+						},
+					},
+					"required": []string{"column", "op"},
+				},
+				"description": "Row filters; a row must match all of them",
+			},
+			"group_by": map[string]any{
+				"type":        "array",
+				"items":       map[string]any{"type": "string"},
+				"description": "Columns to group by when aggregating",
+			},
+			"aggregate": map[string]any{
+				"type":  "array",
+				"items": map[string]any{"type": "string"},
+				"description": "Aggregations such as \"count\", \"sum:amount\", \"avg:amount\", \"min:date\", " +
+					"\"max:date\" or \"distinct:user\". Defaults to count when group_by is set",
+			},
+			"sort_by": map[string]any{
+				"type":        "string",
+				"description": "Column to sort the result by; prefix with - for descending order",
+			},
+			"limit": map[string]any{
+				"type":        "integer",
+				"description": fmt.Sprintf("Maximum rows to return (max %d)", maxDataQueryLimit),
+				"default":     defaultDataQueryLimit,
+			},
+			"sample": map[string]any{
+				"type":        "boolean",
+				"description": "Return a random sample of limit rows instead of the first ones",
+			},
+		},
+		"required": []string{"path"},
+	}
+}
+
+// dataTable holds a loaded data file with every cell as a string.
+type dataTable struct {
+	columns []string
+	rows    [][]string
+}
+
+func (d *dataTable) columnIndex(name string) (int, error) {
+	if i := slices.Index(d.columns, name); i >= 0 {
+		return i, nil
+	}
+	return -1, fmt.Errorf("unknown column %q; available columns: %s", name, strings.Join(d.columns, ", "))
+}
+
+type dataFilter struct {
+	column int
+	op     string
+	value  string
+}
+
+type dataAggregate struct {
+	fn     string
+	column int
+	label  string
+}
+
+func (t *DataQueryTool) Execute(ctx context.Context, args map[string]any) *ToolResult {
+	path, ok := args["path"].(string)
+	if !ok || path == "" {
+		return ErrorResult("path is required")
+	}
+	format, _ := args["format"].(string)
+	if format == "" {
+		format = dataFormatFromPath(path)
+		if format == "" {
+			return ErrorResult("cannot infer the format from the file extension; set format to csv, tsv, json or jsonl")
+		}
+	}
+	limit, err := getInt64Arg(args, "limit", defaultDataQueryLimit)
+	if err != nil {
+		return ErrorResult(err.Error())
+	}
+	if limit <= 0 || limit > maxDataQueryLimit {
+		return ErrorResult(fmt.Sprintf("limit must be between 1 and %d", maxDataQueryLimit))
+	}
+	sample, _ := args["sample"].(bool)
+
+	info, err := statPath(t.fs, path)
+	if err != nil {
+		return ErrorResult(err.Error())
+	}
+	if info.IsDir() {
+		return ErrorResult(fmt.Sprintf("%s is a directory", path))
+	}
+	if info.Size() > MaxDataQueryFileSize {
+		return ErrorResult(fmt.Sprintf(
+			"file is too large to query: %d bytes (max %d bytes)", info.Size(), MaxDataQueryFileSize,
+		))
+	}
+	data, err := t.fs.ReadFile(path)
+	if err != nil {
+		return ErrorResult(err.Error())
+	}
+	table, err := parseDataTable(data, format)
+	if err != nil {
+		return ErrorResult(fmt.Sprintf("failed to parse %s as %s: %v", path, format, err))
+	}
+	if err := ctx.Err(); err != nil {
+		return ErrorResult(err.Error())
+	}
+
+	filters, err := parseDataFilters(table, args["where"])
+	if err != nil {
+		return ErrorResult(err.Error())
+	}
+	groupBy, err := dataColumnList(table, args["group_by"], "group_by")
+	if err != nil {
+		return ErrorResult(err.Error())
+	}
+	aggregates, err := parseDataAggregates(table, args["aggregate"])
+	if err != nil {
+		return ErrorResult(err.Error())
+	}
+	if len(groupBy) > 0 && len(aggregates) == 0 {
+		aggregates = []dataAggregate{{fn: "count", column: -1, label: "count"}}
+	}
+
+	matched := table.rows[:0:0]
+	for _, row := range table.rows {
+		if matchesDataFilters(row, filters) {
+			matched = append(matched, row)
+		}
+	}
+
+	var result *dataTable
+	if len(aggregates) > 0 {
+		if _, ok := args["columns"]; ok {
+			return ErrorResult("columns cannot be combined with aggregate; use group_by to choose the key columns")
+		}
+		result = aggregateDataRows(table, matched, groupBy, aggregates)
+	} else {
+		selected, err := dataColumnList(table, args["columns"], "columns")
+		if err != nil {
+			return ErrorResult(err.Error())
+		}
+		result = projectDataRows(table, matched, selected)
+	}
+
+	if sortBy, _ := args["sort_by"].(string); sortBy != "" {
+		desc := strings.HasPrefix(sortBy, "-")
+		col, err := result.columnIndex(strings.TrimPrefix(sortBy, "-"))
+		if err != nil {
+			return ErrorResult(err.Error())
+		}
+		slices.SortStableFunc(result.rows, func(a, b []string) int {
+			if desc {
+				return compareDataValues(b[col], a[col])
+			}
+			return compareDataValues(a[col], b[col])
+		})
+	}
+
+	total := len(result.rows)
+	if total > int(limit) {
+		if sample {
+			picked := rand.Perm(total)[:limit]
+			slices.Sort(picked)
+			rows := make([][]string, len(picked))
+			for i, idx := range picked {
+				rows[i] = result.rows[idx]
+			}
+			result.rows = rows
+		} else {
+			result.rows = result.rows[:limit]
+		}
+	}
+
+	header := fmt.Sprintf("[%s: %d rows, %d columns", path, len(table.rows), len(table.columns))
+	if len(filters) > 0 {
+		header += fmt.Sprintf(", %d matched", len(matched))
+	}
+	if len(aggregates) > 0 {
+		header += fmt.Sprintf(", %d groups", total)
+	}
+	header += fmt.Sprintf(" | showing %d", len(result.rows))
+	if sample && total > int(limit) {
+		header += " sampled"
+	}
+	header += "]"
+	return NewToolResult(header + "\n" + renderDataTable(result, t.maxOutput))
+}
+
+func dataFormatFromPath(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return "csv"
+	case ".tsv", ".tab":
+		return "tsv"
+	case ".json":
+		return "json"
+	case ".jsonl", ".ndjson":
+		return "jsonl"
+	}
+	return ""
+}
+
+func parseDataTable(data []byte, format string) (*dataTable, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	switch format {
+	case "csv", "tsv":
+		return parseDelimitedTable(data, format == "tsv")
+	case "json", "jsonl":
+		return parseJSONTable(data, format == "jsonl")
+	}
+	return nil, fmt.Errorf("unsupported format %q", format)
+}
+
+func parseDelimitedTable(data []byte, tabs bool) (*dataTable, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	if tabs {
+		reader.Comma = '\t'
+	}
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return &dataTable{}, nil
+	}
+	table := &dataTable{columns: uniqueColumnNames(records[0])}
+	for _, record := range records[1:] {
+		row := make([]string, len(table.columns))
+		copy(row, record)
+		table.rows = append(table.rows, row)
+	}
+	return table, nil
+}
+
+// uniqueColumnNames fills in blank header cells and disambiguates duplicates
+// so every column can be addressed by name.
+func uniqueColumnNames(header []string) []string {
+	columns := make([]string, len(header))
+	seen := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			name = fmt.Sprintf("column_%d", i+1)
+		}
+		if n := seen[name]; n > 0 {
+			seen[name] = n + 1
+			name = fmt.Sprintf("%s_%d", name, n+1)
+		}
+		seen[name]++
+		columns[i] = name
+	}
+	return columns
+}
+
+func parseJSONTable(data []byte, lines bool) (*dataTable, error) {
+	var records []any
+	if lines {
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.UseNumber()
+		for {
+			var record any
+			if err := dec.Decode(&record); errors.Is(err, io.EOF) {
+				break
+			} else if err != nil {
+				return nil, fmt.Errorf("record %d: %w", len(records)+1, err)
+			}
+			records = append(records, record)
+		}
+	} else {
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.UseNumber()
+		var doc any
+		if err := dec.Decode(&doc); err != nil {
+			return nil, err
+		}
+		switch v := doc.(type) {
+		case []any:
+			records = v
+		case map[string]any:
+			// Accept a wrapper object such as {"data": [...]} when it has
+			// exactly one array field.
+			var arrays [][]any
+			for _, field := range v {
+				if arr, ok := field.([]any); ok {
+					arrays = append(arrays, arr)
+				}
+			}
+			if len(arrays) != 1 {
+				return nil, fmt.Errorf("expected an array of objects or an object with a single array field")
+			}
+			records = arrays[0]
+		default:
+			return nil, fmt.Errorf("expected an array of objects")
+		}
+	}
+
+	table := &dataTable{}
+	index := make(map[string]int)
+	var objects []map[string]any
+	for _, record := range records {
+		obj, ok := record.(map[string]any)
+		if !ok {
+			obj = map[string]any{"value": record}
+		}
+		// Go maps are unordered; sort new keys so the column order is stable.
+		keys := make([]string, 0, len(obj))
+		for key := range obj {
+			if _, seen := index[key]; !seen {
+				keys = append(keys, key)
+			}
+		}
+		slices.Sort(keys)
+		for _, key := range keys {
+			index[key] = len(table.columns)
+			table.columns = append(table.columns, key)
+		}
+		objects = append(objects, obj)
+	}
+	for _, obj := range objects {
+		row := make([]string, len(table.columns))
+		for key, value := range obj {
+			row[index[key]] = jsonCellString(value)
+		}
+		table.rows = append(table.rows, row)
+	}
+	return table, nil
+}
+
+func jsonCellString(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case bool:
+		return strconv.FormatBool(v)
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(encoded)
+}
+
+func dataColumnList(table *dataTable, raw any, param string) ([]int, error) {
+	if raw == nil {
+		return nil, nil
+	}
+	items, ok := raw.([]any)
+	if !ok {
+		return nil, fmt.Errorf("%s must be an array of column names", param)
+	}
+	indexes := make([]int, 0, len(items))
+	for _, item := range items {
+		name, ok := item.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s must be an array of column names", param)
+		}
+		i, err := table.columnIndex(name)
+		if err != nil {
+			return nil, err
+		}
+		indexes = append(indexes, i)
+	}
+	return indexes, nil
+}
+
+func parseDataFilters(table *dataTable, raw any) ([]dataFilter, error) {
+	if raw == nil {
+		return nil, nil
+	}
+	items, ok := raw.([]any)
+	if !ok {
+		return nil, fmt.Errorf("where must be an array of {column, op, value} objects")
+	}
+	filters := make([]dataFilter, 0, len(items))
+	for i, item := range items {
+		obj, ok := item.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("where[%d] must be an object", i)
+		}
+		name, _ := obj["column"].(string)
+		col, err := table.columnIndex(name)
+		if err != nil {
+			return nil, fmt.Errorf("where[%d]: %w", i, err)
+		}
+		op, _ := obj["op"].(string)
+		switch op {
+		case "=", "!=", ">", ">=", "<", "<=", "contains", "empty", "not_empty":
+		default:
+			return nil, fmt.Errorf("where[%d]: unsupported op %q", i, op)
+		}
+		value := ""
+		if v, ok := obj["value"]; ok {
+			if f, isFloat := v.(float64); isFloat {
+				value = strconv.FormatFloat(f, 'f', -1, 64)
+			} else {
+				value = jsonCellString(v)
+			}
+		}
+		filters = append(filters, dataFilter{column: col, op: op, value: value})
+	}
+	return filters, nil
+}
+
+func matchesDataFilters(row []string, filters []dataFilter) bool {
+	for _, f := range filters {
+		cell := row[f.column]
+		var ok bool
+		switch f.op {
+		case "=":
+			ok = compareDataValues(cell, f.value) == 0
+		case "!=":
+			ok = compareDataValues(cell, f.value) != 0
+		case ">":
+			ok = compareDataValues(cell, f.value) > 0
+		case ">=":
+			ok = compareDataValues(cell, f.value) >= 0
+		case "<":
+			ok = compareDataValues(cell, f.value) < 0
+		case "<=":
+			ok = compareDataValues(cell, f.value) <= 0
+		case "contains":
+			ok = strings.Contains(strings.ToLower(cell), strings.ToLower(f.value))
+		case "empty":
+			ok = strings.TrimSpace(cell) == ""
+		case "not_empty":
+			ok = strings.TrimSpace(cell) != ""
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// compareDataValues compares numerically when both values are numbers and
+// lexically otherwise.
+func compareDataValues(a, b string) int {
+	fa, errA := strconv.ParseFloat(strings.TrimSpace(a), 64)
+	fb, errB := strconv.ParseFloat(strings.TrimSpace(b), 64)
+	if errA == nil && errB == nil {
+		switch {
+		case fa < fb:
+			return -1
+		case fa > fb:
+			return 1
+		}
+		return 0
+	}
+	return strings.Compare(a, b)
+}
+
+func parseDataAggregates(table *dataTable, raw any) ([]dataAggregate, error) {
+	if raw == nil {
+		return nil, nil
+	}
+	items, ok := raw.([]any)
+	if !ok {
+		return nil, fmt.Errorf("aggregate must be an array of strings such as \"count\" or \"sum:amount\"")
+	}
+	aggregates := make([]dataAggregate, 0, len(items))
+	for _, item := range items {
+		spec, _ := item.(string)
+		fn, column, hasColumn := strings.Cut(strings.TrimSpace(spec), ":")
+		fn = strings.ToLower(fn)
+		switch fn {
+		case "count":
+		case "sum", "avg", "min", "max", "distinct":
+			if !hasColumn {
+				return nil, fmt.Errorf("aggregate %q needs a column, e.g. \"%s:amount\"", spec, fn)
+			}
+		default:
+			return nil, fmt.Errorf("unsupported aggregate %q; use count, sum, avg, min, max or distinct", spec)
+		}
+		agg := dataAggregate{fn: fn, column: -1, label: fn}
+		if hasColumn {
+			col, err := table.columnIndex(column)
+			if err != nil {
+				return nil, err
+			}
+			agg.column = col
+			agg.label = fmt.Sprintf("%s(%s)", fn, column)
+		}
+		aggregates = append(aggregates, agg)
+	}
+	return aggregates, nil
+}
+
+// aggregateDataRows groups rows by the groupBy columns, in order of first
+// appearance, and computes one output row per group.
+func aggregateDataRows(table *dataTable, rows [][]string, groupBy []int, aggregates []dataAggregate) *dataTable {
+	result := &dataTable{}
+	for _, col := range groupBy {
+		result.columns = append(result.columns, table.columns[col])
+	}
+	for _, agg := range aggregates {
+		result.columns = append(result.columns, agg.label)
+	}
+
+	groups := make(map[string][][]string)
+	var order []string
+	for _, row := range rows {
+		keyParts := make([]string, len(groupBy))
+		for i, col := range groupBy {
+			keyParts[i] = row[col]
+		}
+		key := strings.Join(keyParts, "\x00")
+		if _, ok := groups[key]; !ok {
+			order = append(order, key)
+		}
+		groups[key] = append(groups[key], row)
+	}
+	if len(groupBy) == 0 && len(order) == 0 {
+		order = []string{""}
+	}
+
+	for _, key := range order {
+		members := groups[key]
+		out := make([]string, 0, len(result.columns))
+		if len(members) > 0 {
+			for _, col := range groupBy {
+				out = append(out, members[0][col])
+			}
+		}
+		for _, agg := range aggregates {
+			out = append(out, computeDataAggregate(agg, members))
+		}
+		result.rows = append(result.rows, out)
+	}
+	return result
+}
+
+func computeDataAggregate(agg dataAggregate, rows [][]string) string {
+	if agg.fn == "count" {
+		if agg.column < 0 {
+			return strconv.Itoa(len(rows))
+		}
+		n := 0
+		for _, row := range rows {
+			if strings.TrimSpace(row[agg.column]) != "" {
+				n++
+			}
+		}
+		return strconv.Itoa(n)
+	}
+
+	if agg.fn == "distinct" {
+		seen := make(map[string]struct{})
+		for _, row := range rows {
+			seen[row[agg.column]] = struct{}{}
+		}
+		return strconv.Itoa(len(seen))
+	}
+
+	var values []string
+	for _, row := range rows {
+		if v := strings.TrimSpace(row[agg.column]); v != "" {
+			values = append(values, v)
+		}
+	}
+	if len(values) == 0 {
+		return ""
+	}
+	switch agg.fn {
+	case "min":
+		return slices.MinFunc(values, compareDataValues)
+	case "max":
+		return slices.MaxFunc(values, compareDataValues)
+	}
+
+	var sum float64
+	numeric := 0
+	for _, v := range values {
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			sum += f
+			numeric++
+		}
+	}
+	if numeric == 0 {
+		return ""
+	}
+	if agg.fn == "avg" {
+		return formatDataNumber(sum / float64(numeric))
+	}
+	return formatDataNumber(sum)
+}
+
+func formatDataNumber(f float64) string {
+	return strconv.FormatFloat(math.Round(f*1e6)/1e6, 'f', -1, 64)
+}
+
+func projectDataRows(table *dataTable, rows [][]string, columns []int) *dataTable {
+	if len(columns) == 0 {
+		return &dataTable{columns: table.columns, rows: rows}
+	}
+	result := &dataTable{}
+	for _, col := range columns {
+		result.columns = append(result.columns, table.columns[col])
+	}
+	for _, row := range rows {
+		out := make([]string, len(columns))
+		for i, col := range columns {
+			out[i] = row[col]
+		}
+		result.rows = append(result.rows, out)
+	}
+	return result
+}
+
+// renderDataTable formats the table with " | " separators, truncating long
+// cells and stopping before the output exceeds maxBytes.
+func renderDataTable(table *dataTable, maxBytes int64) string {
+	if len(table.columns) == 0 {
+		return "(no columns)"
+	}
+	var sb strings.Builder
+	sb.WriteString(strings.Join(table.columns, " | "))
+	for i, row := range table.rows {
+		cells := make([]string, len(row))
+		for j, cell := range row {
+			cells[j] = formatDataCell(cell)
+		}
+		line := "\n" + strings.Join(cells, " | ")
+		if int64(sb.Len()+len(line)) > maxBytes {
+			fmt.Fprintf(&sb, "\n[TRUNCATED - %d more rows not shown; narrow the query or lower limit]", len(table.rows)-i)
+			break
+		}
+		sb.WriteString(line)
+	}
+	if len(table.rows) == 0 {
+		sb.WriteString("\n(no rows)")
+	}
+	return sb.String()
+}
+
+func formatDataCell(cell string) string {
+	cell = strings.NewReplacer("\r\n", `\n`, "\n", `\n`, "\t", " ", "|", `\|`).Replace(cell)
+	if utf8.RuneCountInString(cell) > maxDataCellWidth {
+		runes := []rune(cell)
+		cell = string(runes[:maxDataCellWidth-1]) + "…"
+	}
+	return cell
+}
@@ -0,0 +1,158 @@
+package fstools
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const salesCSV = `region,product,amount,note
+north,apple,10,
+south,apple,5,late
+north,pear,7.5,
+east,plum,20,"has, comma"
+south,pear,3,
+`
+
+func queryData(t *testing.T, files map[string]string, args map[string]any) *ToolResult {
+	t.Helper()
+	workspace := t.TempDir()
+	writeFindFixture(t, workspace, files)
+	tool := NewDataQueryTool(workspace, true, 0)
+	return tool.Execute(context.Background(), args)
+}
+
+func dataResultLines(t *testing.T, result *ToolResult) []string {
+	t.Helper()
+	require.False(t, result.IsError, result.ForLLM)
+	return strings.Split(result.ForLLM, "\n")
+}
+
+func TestDataQueryTool_DefaultShowsColumnsAndHead(t *testing.T) {
+	result := queryData(t, map[string]string{"sales.csv": salesCSV}, map[string]any{
+		"path":  "sales.csv",
+		"limit": 2,
+	})
+	lines := dataResultLines(t, result)
+	assert.Equal(t, "[sales.csv: 5 rows, 4 columns | showing 2]", lines[0])
+	assert.Equal(t, []string{
+		"region | product | amount | note",
+		"north | apple | 10 | ",
+		"south | apple | 5 | late",
+	}, lines[1:])
+}
+
+func TestDataQueryTool_FilterSelectSort(t *testing.T) {
+	result := queryData(t, map[string]string{"sales.csv": salesCSV}, map[string]any{
+		"path":    "sales.csv",
+		"columns": []any{"product", "amount"},
+		"where": []any{
+			map[string]any{"column": "amount", "op": ">=", "value": float64(5)},
+			map[string]any{"column": "note", "op": "empty"},
+		},
+		"sort_by": "-amount",
+	})
+	lines := dataResultLines(t, result)
+	assert.Equal(t, "[sales.csv: 5 rows, 4 columns, 2 matched | showing 2]", lines[0])
+	assert.Equal(t, []string{"product | amount", "apple | 10", "pear | 7.5"}, lines[1:])
+}
+
+func TestDataQueryTool_GroupByAggregates(t *testing.T) {
+	result := queryData(t, map[string]string{"sales.csv": salesCSV}, map[string]any{
+		"path":      "sales.csv",
+		"group_by":  []any{"product"},
+		"aggregate": []any{"count", "sum:amount", "avg:amount", "max:region"},
+		"sort_by":   "product",
+	})
+	lines := dataResultLines(t, result)
+	assert.Equal(t, []string{
+		"product | count | sum(amount) | avg(amount) | max(region)",
+		"apple | 2 | 15 | 7.5 | south",
+		"pear | 2 | 10.5 | 5.25 | south",
+		"plum | 1 | 20 | 20 | east",
+	}, lines[1:])
+}
+
+func TestDataQueryTool_AggregateWithoutGroups(t *testing.T) {
+	result := queryData(t, map[string]string{"sales.csv": salesCSV}, map[string]any{
+		"path":      "sales.csv",
+		"aggregate": []any{"count", "distinct:region"},
+		"where":     []any{map[string]any{"column": "product", "op": "=", "value": "none"}},
+	})
+	lines := dataResultLines(t, result)
+	assert.Equal(t, []string{"count | distinct(region)", "0 | 0"}, lines[1:])
+}
+
+func TestDataQueryTool_JSONAndJSONLines(t *testing.T) {
+	files := map[string]string{
+		"users.json":   `{"data": [{"name": "ada", "age": 36}, {"name": "bob", "tags": ["x"]}]}`,
+		"events.jsonl": "{\"kind\":\"open\"}\n{\"kind\":\"close\",\"ok\":true}\n",
+	}
+	result := queryData(t, files, map[string]any{"path": "users.json"})
+	lines := dataResultLines(t, result)
+	assert.Equal(t, []string{"age | name | tags", "36 | ada | ", ` | bob | ["x"]`}, lines[1:])
+
+	result = queryData(t, files, map[string]any{"path": "events.jsonl", "sort_by": "kind"})
+	lines = dataResultLines(t, result)
+	assert.Equal(t, []string{"kind | ok", "close | true", "open | "}, lines[1:])
+}
+
+func TestDataQueryTool_SampleReturnsLimitRows(t *testing.T) {
+	var sb strings.Builder
+	sb.WriteString("n\n")
+	for i := range 100 {
+		sb.WriteString(strings.Repeat("x", i%5+1) + "\n")
+	}
+	result := queryData(t, map[string]string{"big.csv": sb.String()}, map[string]any{
+		"path":   "big.csv",
+		"limit":  10,
+		"sample": true,
+	})
+	lines := dataResultLines(t, result)
+	assert.Contains(t, lines[0], "showing 10 sampled")
+	assert.Len(t, lines, 12)
+}
+
+func TestDataQueryTool_Errors(t *testing.T) {
+	files := map[string]string{"sales.csv": salesCSV, "notes.txt": "hello"}
+	tests := []struct {
+		name string
+		args map[string]any
+		want string
+	}{
+		{"unknown column", map[string]any{"path": "sales.csv", "columns": []any{"price"}}, "available columns"},
+		{"bad op", map[string]any{
+			"path":  "sales.csv",
+			"where": []any{map[string]any{"column": "amount", "op": "~"}},
+		}, "unsupported op"},
+		{"bad aggregate", map[string]any{"path": "sales.csv", "aggregate": []any{"median:amount"}}, "unsupported aggregate"},
+		{"aggregate needs column", map[string]any{"path": "sales.csv", "aggregate": []any{"sum"}}, "needs a column"},
+		{"unknown format", map[string]any{"path": "notes.txt"}, "cannot infer the format"},
+		{"limit", map[string]any{"path": "sales.csv", "limit": 0}, "limit must be"},
+		{"bad json", map[string]any{"path": "notes.txt", "format": "json"}, "failed to parse"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := queryData(t, files, tt.args)
+			require.True(t, result.IsError)
+			assert.Contains(t, result.ForLLM, tt.want)
+		})
+	}
+}
+
+func TestRenderDataTable_TruncatesCellsAndOutput(t *testing.T) {
+	table := &dataTable{
+		columns: []string{"text"},
+		rows:    [][]string{{strings.Repeat("a", 100) + "|x"}, {"line1\nline2"}, {"third"}},
+	}
+	out := renderDataTable(table, 1<<20)
+	lines := strings.Split(out, "\n")
+	assert.Equal(t, maxDataCellWidth, len([]rune(lines[1])))
+	assert.Equal(t, `line1\nline2`, lines[2])
+
+	out = renderDataTable(table, 40)
+	assert.Contains(t, out, "[TRUNCATED - 3 more rows not shown")
+}
@@ -0,0 +1,70 @@
+package fstools
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+)
+
+// DeleteFileTool removes a single file or an empty directory. It never
+// deletes recursively, so a mistaken path can cost at most one entry.
+type DeleteFileTool struct {
+	fs fileSystem
+}
+
+// NewDeleteFileTool creates a new DeleteFileTool with optional directory restriction.
+func NewDeleteFileTool(workspace string, restrict bool, allowPaths ...[]*regexp.Regexp) *DeleteFileTool {
+	var patterns []*regexp.Regexp
+	if len(allowPaths) > 0 {
+		patterns = allowPaths[0]
+	}
+	return &DeleteFileTool{fs: buildFs(workspace, restrict, patterns)}
+}
+
+func (t *DeleteFileTool) Name() string {
+	return "delete_file"
+}
+
+func (t *DeleteFileTool) Description() string {
+	return "Delete a file or an empty directory. Directories must be emptied first; deletion is never recursive. Use this to clean up temporary files you created."
+}
+
+func (t *DeleteFileTool) Parameters() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"path": map[string]any{
+				"type":        "string",
+				"description": "Path to the file or empty directory to delete",
+			},
+		},
+		"required": []string{"path"},
+	}
+}
+
+func (t *DeleteFileTool) Execute(ctx context.Context, args map[string]any) *ToolResult {
+	path, ok := args["path"].(string)
+	if !ok || path == "" {
+		return ErrorResult("path is required")
+	}
+
+	unlock, err := t.fs.Lock(path)
+	if err != nil {
+		return ErrorResult(err.Error())
+	}
+	defer unlock()
+
+	// Report non-empty directories explicitly instead of surfacing the
+	// platform-specific error from the underlying remove call.
+	if entries, err := t.fs.ReadDir(path); err == nil && len(entries) > 0 {
+		return ErrorResult(fmt.Sprintf(
+			"failed to delete: directory %s is not empty (%d entries); delete its contents first",
+			path, len(entries),
+		))
+	}
+
+	if err := t.fs.Remove(path); err != nil {
+		return ErrorResult(err.Error())
+	}
+	return SilentResult(fmt.Sprintf("Deleted: %s", path))
+}
@@ -0,0 +1,113 @@
+package fstools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeleteFileTool_Restricted_DeletesFile(t *testing.T) {
+	workspace := t.TempDir()
+	target := filepath.Join(workspace, "tmp.txt")
+	require.NoError(t, os.WriteFile(target, []byte("scratch"), 0o644))
+
+	tool := NewDeleteFileTool(workspace, true)
+	result := tool.Execute(context.Background(), map[string]any{"path": "tmp.txt"})
+
+	assert.False(t, result.IsError, "Expected success, got: %s", result.ForLLM)
+	assert.True(t, result.Silent)
+	_, err := os.Stat(target)
+	assert.True(t, os.IsNotExist(err), "expected file to be removed, stat err = %v", err)
+}
+
+func TestDeleteFileTool_Restricted_DeletesEmptyDirectory(t *testing.T) {
+	workspace := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(workspace, "build"), 0o755))
+
+	tool := NewDeleteFileTool(workspace, true)
+	result := tool.Execute(context.Background(), map[string]any{"path": "build"})
+
+	assert.False(t, result.IsError, "Expected success, got: %s", result.ForLLM)
+	_, err := os.Stat(filepath.Join(workspace, "build"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestDeleteFileTool_RejectsNonEmptyDirectory(t *testing.T) {
+	workspace := t.TempDir()
+	dir := filepath.Join(workspace, "build")
+	require.NoError(t, os.Mkdir(dir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "out.o"), []byte("x"), 0o644))
+
+	for _, restrict := range []bool{true, false} {
+		tool := NewDeleteFileTool(workspace, restrict)
+		result := tool.Execute(context.Background(), map[string]any{"path": dir})
+
+		assert.True(t, result.IsError, "restrict=%v: expected error", restrict)
+		assert.Contains(t, result.ForLLM, "not empty")
+		_, err := os.Stat(filepath.Join(dir, "out.o"))
+		assert.NoError(t, err, "restrict=%v: directory contents must be kept", restrict)
+	}
+}
+
+func TestDeleteFileTool_Restricted_OutsideWorkspace(t *testing.T) {
+	workspace := t.TempDir()
+	outside := filepath.Join(t.TempDir(), "keep.txt")
+	require.NoError(t, os.WriteFile(outside, []byte("keep"), 0o644))
+
+	tool := NewDeleteFileTool(workspace, true)
+	result := tool.Execute(context.Background(), map[string]any{"path": outside})
+
+	assert.True(t, result.IsError)
+	_, err := os.Stat(outside)
+	assert.NoError(t, err, "file outside the workspace must not be deleted")
+}
+
+func TestDeleteFileTool_Restricted_RefusesWorkspaceRoot(t *testing.T) {
+	workspace := t.TempDir()
+
+	tool := NewDeleteFileTool(workspace, true)
+	result := tool.Execute(context.Background(), map[string]any{"path": "."})
+
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.ForLLM, "workspace root")
+	_, err := os.Stat(workspace)
+	assert.NoError(t, err)
+}
+
+func TestDeleteFileTool_NotFound(t *testing.T) {
+	workspace := t.TempDir()
+
+	for _, restrict := range []bool{true, false} {
+		tool := NewDeleteFileTool(workspace, restrict)
+		result := tool.Execute(context.Background(), map[string]any{
+			"path": filepath.Join(workspace, "missing.txt"),
+		})
+
+		assert.True(t, result.IsError, "restrict=%v: expected error", restrict)
+		assert.Contains(t, result.ForLLM, "not found")
+	}
+}
+
+func TestDeleteFileTool_Unrestricted_DeletesFile(t *testing.T) {
+	target := filepath.Join(t.TempDir(), "anywhere.txt")
+	require.NoError(t, os.WriteFile(target, []byte("x"), 0o644))
+
+	tool := NewDeleteFileTool(t.TempDir(), false)
+	result := tool.Execute(context.Background(), map[string]any{"path": target})
+
+	assert.False(t, result.IsError, "Expected success, got: %s", result.ForLLM)
+	_, err := os.Stat(target)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestDeleteFileTool_MissingPath(t *testing.T) {
+	tool := NewDeleteFileTool(t.TempDir(), true)
+	result := tool.Execute(context.Background(), map[string]any{})
+
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.ForLLM, "path is required")
+}
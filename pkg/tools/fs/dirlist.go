@@ -0,0 +1,150 @@
+package fstools
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// dirListing describes one entry of a flat list_dir listing.
+type dirListing struct {
+	Name     string    `json:"name"`
+	Type     string    `json:"type"`
+	Size     int64     `json:"size,omitempty"`
+	Modified time.Time `json:"modified"`
+	Entries  *int      `json:"entries,omitempty"`
+}
+
+// listFlat lists the direct children of dir with their size, modification
+// time and, for directories, the number of entries they contain.
+func (t *ListDirTool) listFlat(dir string, args map[string]any) *ToolResult {
+	sortBy, _ := args["sort"].(string)
+	if sortBy == "" {
+		sortBy = "name"
+	}
+	if sortBy != "name" && sortBy != "mtime" && sortBy != "size" {
+		return ErrorResult(fmt.Sprintf("unknown sort %q: use name, mtime or size", sortBy))
+	}
+	format, _ := args["format"].(string)
+	if format == "" {
+		format = "text"
+	}
+	if format != "text" && format != "json" {
+		return ErrorResult(fmt.Sprintf("unknown format %q: use text or json", format))
+	}
+	limit, err := getInt64Arg(args, "limit", 0)
+	if err != nil {
+		return ErrorResult(err.Error())
+	}
+	if limit < 0 {
+		return ErrorResult("limit must be >= 0")
+	}
+
+	entries, err := t.fs.ReadDir(dir)
+	if err != nil {
+		return ErrorResult(fmt.Sprintf("failed to read directory: %v", err))
+	}
+
+	listing := make([]dirListing, 0, len(entries))
+	dirs := 0
+	for _, entry := range entries {
+		item := dirListing{Name: entry.Name(), Type: "file"}
+		if info, err := entry.Info(); err == nil {
+			item.Modified = info.ModTime()
+			if info.Mode().IsRegular() {
+				item.Size = info.Size()
+			}
+		}
+		switch {
+		case entry.IsDir():
+			item.Type = "dir"
+			dirs++
+			if children, err := t.fs.ReadDir(filepath.Join(dir, entry.Name())); err == nil {
+				n := len(children)
+				item.Entries = &n
+			}
+		case entry.Type()&os.ModeSymlink != 0:
+			item.Type = "symlink"
+		}
+		listing = append(listing, item)
+	}
+
+	// Name order lists directories first; mtime and size put the newest and
+	// largest entries first, since those are what the caller is looking for.
+	switch sortBy {
+	case "name":
+		slices.SortStableFunc(listing, func(a, b dirListing) int {
+			if (a.Type == "dir") != (b.Type == "dir") {
+				if a.Type == "dir" {
+					return -1
+				}
+				return 1
+			}
+			return strings.Compare(a.Name, b.Name)
+		})
+	case "mtime":
+		slices.SortStableFunc(listing, func(a, b dirListing) int { return b.Modified.Compare(a.Modified) })
+	case "size":
+		slices.SortStableFunc(listing, func(a, b dirListing) int {
+			if c := cmp.Compare(b.Size, a.Size); c != 0 {
+				return c
+			}
+			return strings.Compare(a.Name, b.Name)
+		})
+	}
+
+	total := len(listing)
+	if limit > 0 && int(limit) < total {
+		listing = listing[:limit]
+	}
+
+	if format == "json" {
+		out, err := json.Marshal(map[string]any{
+			"path":    dir,
+			"total":   total,
+			"dirs":    dirs,
+			"files":   total - dirs,
+			"entries": listing,
+		})
+		if err != nil {
+			return ErrorResult(fmt.Sprintf("failed to encode listing: %v", err))
+		}
+		return NewToolResult(string(out))
+	}
+	return NewToolResult(formatDirListing(dir, listing, total, dirs))
+}
+
+// formatDirListing renders a flat listing as one line per entry below a
+// summary header.
+func formatDirListing(dir string, listing []dirListing, total, dirs int) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "[dir: %s | %d entries (%d dirs, %d files)", dir, total, dirs, total-dirs)
+	if len(listing) < total {
+		fmt.Fprintf(&sb, " | showing %d", len(listing))
+	}
+	sb.WriteString("]\n")
+	for _, item := range listing {
+		modified := ""
+		if !item.Modified.IsZero() {
+			modified = "  " + item.Modified.Format("2006-01-02 15:04")
+		}
+		switch item.Type {
+		case "dir":
+			count := ""
+			if item.Entries != nil {
+				count = fmt.Sprintf("  (%d entries)", *item.Entries)
+			}
+			fmt.Fprintf(&sb, "DIR:  %s/%s%s\n", item.Name, count, modified)
+		case "symlink":
+			fmt.Fprintf(&sb, "LINK: %s%s\n", item.Name, modified)
+		default:
+			fmt.Fprintf(&sb, "FILE: %s  %s%s\n", item.Name, formatFileSize(item.Size), modified)
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,106 @@
+package fstools
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeListFixture(t *testing.T) string {
+	t.Helper()
+	workspace := t.TempDir()
+	writeFindFixture(t, workspace, map[string]string{
+		"a.log":      strings.Repeat("a", 10),
+		"b.log":      strings.Repeat("b", 300),
+		"c.log":      strings.Repeat("c", 20),
+		"logs/x.txt": "x",
+		"logs/y.txt": "y",
+	})
+	base := time.Date(2026, 1, 2, 3, 4, 0, 0, time.Local)
+	for i, name := range []string{"b.log", "a.log", "logs", "c.log"} {
+		mtime := base.Add(time.Duration(i) * time.Hour)
+		require.NoError(t, os.Chtimes(filepath.Join(workspace, name), mtime, mtime))
+	}
+	return workspace
+}
+
+func TestListDirTool_FlatShowsDetails(t *testing.T) {
+	workspace := writeListFixture(t)
+	tool := NewListDirTool(workspace, true)
+
+	result := tool.Execute(context.Background(), map[string]any{"path": "."})
+	require.False(t, result.IsError, result.ForLLM)
+	assert.Equal(t, strings.Join([]string{
+		"[dir: . | 4 entries (1 dirs, 3 files)]",
+		"DIR:  logs/  (2 entries)  2026-01-02 05:04",
+		"FILE: a.log  10 B  2026-01-02 04:04",
+		"FILE: b.log  300 B  2026-01-02 03:04",
+		"FILE: c.log  20 B  2026-01-02 06:04",
+		"",
+	}, "\n"), result.ForLLM)
+}
+
+func TestListDirTool_FlatSortAndLimit(t *testing.T) {
+	workspace := writeListFixture(t)
+	tool := NewListDirTool(workspace, true)
+
+	result := tool.Execute(context.Background(), map[string]any{"path": ".", "sort": "mtime", "limit": 1})
+	require.False(t, result.IsError, result.ForLLM)
+	lines := strings.Split(strings.TrimSpace(result.ForLLM), "\n")
+	assert.Equal(t, "[dir: . | 4 entries (1 dirs, 3 files) | showing 1]", lines[0])
+	assert.Equal(t, []string{"FILE: c.log  20 B  2026-01-02 06:04"}, lines[1:])
+
+	result = tool.Execute(context.Background(), map[string]any{"path": ".", "sort": "size", "limit": 2})
+	require.False(t, result.IsError, result.ForLLM)
+	lines = strings.Split(strings.TrimSpace(result.ForLLM), "\n")
+	assert.Equal(t, []string{"FILE: b.log  300 B  2026-01-02 03:04", "FILE: c.log  20 B  2026-01-02 06:04"}, lines[1:])
+}
+
+func TestListDirTool_FlatJSON(t *testing.T) {
+	workspace := writeListFixture(t)
+	tool := NewListDirTool(workspace, true)
+
+	result := tool.Execute(context.Background(), map[string]any{"path": ".", "format": "json", "sort": "mtime"})
+	require.False(t, result.IsError, result.ForLLM)
+
+	var out struct {
+		Total   int `json:"total"`
+		Dirs    int `json:"dirs"`
+		Entries []struct {
+			Name     string    `json:"name"`
+			Type     string    `json:"type"`
+			Size     int64     `json:"size"`
+			Modified time.Time `json:"modified"`
+			Entries  *int      `json:"entries"`
+		} `json:"entries"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(result.ForLLM), &out))
+	assert.Equal(t, 4, out.Total)
+	assert.Equal(t, 1, out.Dirs)
+	require.Len(t, out.Entries, 4)
+	assert.Equal(t, "c.log", out.Entries[0].Name)
+	assert.Equal(t, int64(20), out.Entries[0].Size)
+	assert.Equal(t, "logs", out.Entries[1].Name)
+	assert.Equal(t, "dir", out.Entries[1].Type)
+	require.NotNil(t, out.Entries[1].Entries)
+	assert.Equal(t, 2, *out.Entries[1].Entries)
+}
+
+func TestListDirTool_FlatRejectsBadArgs(t *testing.T) {
+	tool := NewListDirTool(t.TempDir(), true)
+	for _, args := range []map[string]any{
+		{"path": ".", "sort": "owner"},
+		{"path": ".", "format": "xml"},
+		{"path": ".", "limit": -1},
+	} {
+		result := tool.Execute(context.Background(), args)
+		assert.True(t, result.IsError, "%v", args)
+	}
+}
@@ -10,7 +10,8 @@ import (
 )
 
 // EditFileTool edits a file by replacing old_text with new_text.
-// The old_text must exist exactly in the file.
+// The old_text should exist exactly in the file; a unique match that differs
+// only in whitespace or indentation is accepted as a fallback.
 type EditFileTool struct {
 	fs fileSystem
 }
@@ -29,7 +30,7 @@ func (t *EditFileTool) Name() string {
 }
 
 func (t *EditFileTool) Description() string {
-	return "Edit a file by replacing old_text with new_text. The old_text must exist exactly in the file. Standard JSON escaping applies: \\n for newline and \\\\n for literal backslash-n."
+	return "Edit a file by replacing old_text with new_text. The old_text should match the file exactly; if it does not, a unique match that differs only in whitespace or indentation is used, and otherwise the error shows the closest matching lines. Standard JSON escaping applies: \\n for newline and \\\\n for literal backslash-n."
 }
 
 func (t *EditFileTool) Parameters() map[string]any {
@@ -48,6 +49,11 @@ func (t *EditFileTool) Parameters() map[string]any {
 				"type":        "string",
 				"description": "The text to replace with. Standard JSON escaping applies: \\n for newline and \\\\n for literal backslash-n.",
 			},
+			"preview": map[string]any{
+				"type":        "boolean",
+				"description": "Set to true to return a unified diff of the edit without writing the file.",
+				"default":     false,
+			},
 		},
 		"required": []string{"path", "old_text", "new_text"},
 	}
@@ -69,6 +75,22 @@ func (t *EditFileTool) Execute(ctx context.Context, args map[string]any) *ToolRe
 		return ErrorResult("new_text is required")
 	}
 
+	if preview, _ := args["preview"].(bool); preview {
+		raw, err := t.fs.ReadFile(path)
+		if err != nil {
+			return ErrorResult(err.Error())
+		}
+		beforeContent, _, _, err := decodeFileText(raw)
+		if err != nil {
+			return ErrorResult(err.Error())
+		}
+		afterContent, err := replaceEditContent(beforeContent, oldText, newText)
+		if err != nil {
+			return ErrorResult(err.Error())
+		}
+		return DiffPreviewResult(path, beforeContent, afterContent)
+	}
+
 	beforeContent, afterContent, err := editFile(t.fs, path, oldText, newText)
 	if err != nil {
 		return ErrorResult(err.Error())
@@ -130,10 +152,154 @@ func (t *AppendFileTool) Execute(ctx context.Context, args map[string]any) *Tool
 	return SilentResult(fmt.Sprintf("Appended to %s", path))
 }
 
+// MultiEditTool applies several old_text/new_text replacements to one file
+// in order. Either every edit applies and the file is written once, or the
+// file is left untouched.
+type MultiEditTool struct {
+	fs fileSystem
+}
+
+// NewMultiEditTool creates a new MultiEditTool with optional directory restriction.
+func NewMultiEditTool(workspace string, restrict bool, allowPaths ...[]*regexp.Regexp) *MultiEditTool {
+	var patterns []*regexp.Regexp
+	if len(allowPaths) > 0 {
+		patterns = allowPaths[0]
+	}
+	return &MultiEditTool{fs: buildFs(workspace, restrict, patterns)}
+}
+
+func (t *MultiEditTool) Name() string {
+	return "multi_edit"
+}
+
+func (t *MultiEditTool) Description() string {
+	return "Apply several edits to one file in a single call. Each edit replaces old_text with new_text, in order, against the result of the previous edits; each old_text must match exactly once. All edits are applied together or, if any fails, none are. Standard JSON escaping applies: \\n for newline and \\\\n for literal backslash-n."
+}
+
+func (t *MultiEditTool) Parameters() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"path": map[string]any{
+				"type":        "string",
+				"description": "The file path to edit",
+			},
+			"edits": map[string]any{
+				"type":        "array",
+				"description": "Replacements to apply in order",
+				"minItems":    1,
+				"items": map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"old_text": map[string]any{
+							"type":        "string",
+							"description": "The exact text to find and replace",
+						},
+						"new_text": map[string]any{
+							"type":        "string",
+							"description": "The text to replace with",
+						},
+					},
+					"required": []string{"old_text", "new_text"},
+				},
+			},
+		},
+		"required": []string{"path", "edits"},
+	}
+}
+
+type textEdit struct {
+	oldText string
+	newText string
+}
+
+func (t *MultiEditTool) Execute(ctx context.Context, args map[string]any) *ToolResult {
+	path, ok := args["path"].(string)
+	if !ok {
+		return ErrorResult("path is required")
+	}
+
+	rawEdits, ok := args["edits"].([]any)
+	if !ok || len(rawEdits) == 0 {
+		return ErrorResult("edits is required and must be a non-empty list")
+	}
+	edits := make([]textEdit, 0, len(rawEdits))
+	for i, raw := range rawEdits {
+		item, ok := raw.(map[string]any)
+		if !ok {
+			return ErrorResult(fmt.Sprintf("edit %d must be an object with old_text and new_text", i+1))
+		}
+		oldText, ok := item["old_text"].(string)
+		if !ok {
+			return ErrorResult(fmt.Sprintf("edit %d: old_text is required", i+1))
+		}
+		newText, ok := item["new_text"].(string)
+		if !ok {
+			return ErrorResult(fmt.Sprintf("edit %d: new_text is required", i+1))
+		}
+		edits = append(edits, textEdit{oldText: oldText, newText: newText})
+	}
+
+	beforeContent, afterContent, err := multiEditFile(t.fs, path, edits)
+	if err != nil {
+		return ErrorResult(err.Error())
+	}
+	return DiffResult(path, beforeContent, afterContent)
+}
+
+// multiEditFile applies edits in memory and writes the file only when all of
+// them succeed.
+func multiEditFile(sysFs fileSystem, path string, edits []textEdit) ([]byte, []byte, error) {
+	unlock, err := sysFs.Lock(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer unlock()
+
+	raw, err := sysFs.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	content, enc, hasBOM, err := decodeFileText(raw)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	newContent := content
+	for i, e := range edits {
+		newContent, err = replaceEditContent(newContent, e.oldText, e.newText)
+		if err != nil {
+			return nil, nil, fmt.Errorf("edit %d of %d failed, no changes were written: %w", i+1, len(edits), err)
+		}
+	}
+
+	encoded, err := encodeFileText(newContent, enc, hasBOM)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := sysFs.WriteFile(path, encoded); err != nil {
+		return nil, nil, err
+	}
+
+	return content, newContent, nil
+}
+
 // editFile reads the file via sysFs, performs the replacement, and writes back.
 // It uses a fileSystem interface, allowing the same logic for both restricted and unrestricted modes.
+// UTF-16 and Windows-1252 files are edited as UTF-8 and written back in their
+// original encoding.
 func editFile(sysFs fileSystem, path, oldText, newText string) ([]byte, []byte, error) {
-	content, err := sysFs.ReadFile(path)
+	unlock, err := sysFs.Lock(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer unlock()
+
+	raw, err := sysFs.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	content, enc, hasBOM, err := decodeFileText(raw)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -143,7 +309,11 @@ func editFile(sysFs fileSystem, path, oldText, newText string) ([]byte, []byte,
 		return nil, nil, err
 	}
 
-	if err := sysFs.WriteFile(path, newContent); err != nil {
+	encoded, err := encodeFileText(newContent, enc, hasBOM)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := sysFs.WriteFile(path, encoded); err != nil {
 		return nil, nil, err
 	}
 
@@ -152,12 +322,25 @@ func editFile(sysFs fileSystem, path, oldText, newText string) ([]byte, []byte,
 
 // appendFile reads the existing content (if any) via sysFs, appends new content, and writes back.
 func appendFile(sysFs fileSystem, path, appendContent string) error {
+	unlock, err := sysFs.Lock(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	content, err := sysFs.ReadFile(path)
 	if err != nil && !errors.Is(err, fs.ErrNotExist) {
 		return err
 	}
 
-	newContent := append(content, []byte(appendContent)...)
+	// Keep a non-UTF-8 file in its own encoding.
+	addition := []byte(appendContent)
+	if enc := detectTextEncoding(content[:min(len(content), 512)]); enc != nil {
+		if addition, err = encodeText(addition, enc, false); err != nil {
+			return err
+		}
+	}
+	newContent := append(content, addition...)
 	return sysFs.WriteFile(path, newContent)
 }
 
@@ -166,7 +349,11 @@ func replaceEditContent(content []byte, oldText, newText string) ([]byte, error)
 	contentStr := string(content)
 
 	if !strings.Contains(contentStr, oldText) {
-		return nil, fmt.Errorf("old_text not found in file. Make sure it matches exactly")
+		newContent, err := fuzzyReplace(contentStr, oldText, newText)
+		if err != nil {
+			return nil, err
+		}
+		return []byte(newContent), nil
 	}
 
 	count := strings.Count(contentStr, oldText)
@@ -0,0 +1,307 @@
+package fstools
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+const (
+	// maxClosestMatchLines bounds the file size, in lines, scanned for a
+	// closest-match hint when old_text is not found.
+	maxClosestMatchLines = 20000
+	// minClosestMatchSimilarity is the score below which no hint is given.
+	minClosestMatchSimilarity = 0.5
+	maxClosestMatchSnippet    = 12
+)
+
+// lineNormalizers are applied in order when comparing lines: exact, then
+// ignoring trailing whitespace, then with every whitespace run collapsed.
+var lineNormalizers = []func(string) string{
+	func(s string) string { return s },
+	func(s string) string { return strings.TrimRight(s, " \t\r") },
+	func(s string) string { return strings.Join(strings.Fields(s), " ") },
+}
+
+var errOldTextNotFound = errors.New("old_text not found in file. Make sure it matches exactly")
+
+// fuzzyReplace is the fallback for replaceEditContent when old_text does not
+// occur verbatim. It matches old_text line by line while ignoring whitespace
+// differences and, if exactly one block of lines matches, replaces it. When
+// the indentation of the match differs consistently from old_text, new_text
+// is re-indented to match the file. If nothing matches, the error includes
+// the most similar block of the file so the caller can correct old_text.
+func fuzzyReplace(content, oldText, newText string) (string, error) {
+	if strings.TrimSpace(oldText) == "" {
+		return "", errOldTextNotFound
+	}
+
+	spans := splitLineSpans(content)
+	lines := make([]string, len(spans))
+	for i, span := range spans {
+		lines[i] = content[span[0]:span[1]]
+	}
+	oldLines := strings.Split(strings.TrimSuffix(oldText, "\n"), "\n")
+
+	for _, normalize := range lineNormalizers {
+		matches := findLineBlocks(lines, oldLines, normalize)
+		if len(matches) == 0 {
+			continue
+		}
+		if len(matches) > 1 {
+			return "", fmt.Errorf(
+				"old_text not found exactly, and it matches %d places when whitespace is ignored. "+
+					"Please provide more context to make it unique",
+				len(matches),
+			)
+		}
+
+		first := matches[0]
+		matched := lines[first : first+len(oldLines)]
+		start := spans[first][0]
+		end := spans[first+len(oldLines)-1][1]
+		if strings.HasSuffix(oldText, "\n") && end < len(content) {
+			end++
+		} else if strings.HasSuffix(matched[len(matched)-1], "\r") && !strings.HasSuffix(oldLines[len(oldLines)-1], "\r") {
+			// Keep the CR of a CRLF line that old_text did not mention.
+			end--
+		}
+		replacement := reindent(newText, oldLines, matched)
+		if strings.HasSuffix(matched[0], "\r") && !strings.Contains(replacement, "\r") {
+			replacement = strings.ReplaceAll(replacement, "\n", "\r\n")
+		}
+		return content[:start] + replacement + content[end:], nil
+	}
+
+	return "", fmt.Errorf("%w%s", errOldTextNotFound, closestMatchHint(lines, oldLines))
+}
+
+// splitLineSpans returns the [start, end) byte offsets of each line in s,
+// excluding the newline.
+func splitLineSpans(s string) [][2]int {
+	var spans [][2]int
+	start := 0
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\n' {
+			spans = append(spans, [2]int{start, i})
+			start = i + 1
+		}
+	}
+	if start < len(s) {
+		spans = append(spans, [2]int{start, len(s)})
+	}
+	return spans
+}
+
+// findLineBlocks returns the index of every block of len(want) consecutive
+// lines that equals want after normalization.
+func findLineBlocks(lines, want []string, normalize func(string) string) []int {
+	normalizedWant := make([]string, len(want))
+	for i, line := range want {
+		normalizedWant[i] = normalize(line)
+	}
+
+	var matches []int
+	for i := 0; i+len(want) <= len(lines); i++ {
+		if linesMatch(lines[i:i+len(want)], normalizedWant, normalize) {
+			matches = append(matches, i)
+		}
+	}
+	return matches
+}
+
+func linesMatch(lines, want []string, normalize func(string) string) bool {
+	for i := range want {
+		if normalize(lines[i]) != want[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// reindent rewrites the indentation of newText when old_text matched lines
+// indented differently, e.g. old_text written with two spaces per level
+// against a file indented with tabs, or indented one level less than the
+// file. Indentation is mapped level by level; if the mapping is not
+// consistent across the matched lines, newText is returned unchanged.
+func reindent(newText string, oldLines, matched []string) string {
+	var oldLeads, fileLeads []string
+	shifted := false
+	for i, line := range oldLines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		oldLeads = append(oldLeads, leadingWhitespace(line))
+		fileLeads = append(fileLeads, leadingWhitespace(matched[i]))
+		shifted = shifted || oldLeads[len(oldLeads)-1] != fileLeads[len(fileLeads)-1]
+	}
+	if !shifted {
+		return newText
+	}
+
+	lines := strings.Split(newText, "\n")
+	newLeads := make([]string, 0, len(lines))
+	for _, line := range lines {
+		if strings.TrimSpace(line) != "" {
+			newLeads = append(newLeads, leadingWhitespace(line))
+		}
+	}
+
+	// newText is written in old_text's style, so both inform its unit.
+	oldUnit, ok := indentUnit(append(slices.Clone(oldLeads), newLeads...))
+	if !ok {
+		return newText
+	}
+	fileUnit, ok := indentUnit(fileLeads)
+	if !ok {
+		return newText
+	}
+	if fileUnit == "" {
+		fileUnit = oldUnit
+	}
+	if oldUnit == "" {
+		oldUnit = fileUnit
+	}
+	if oldUnit == "" {
+		return newText
+	}
+
+	shift := len(fileLeads[0])/max(len(fileUnit), 1) - len(oldLeads[0])/len(oldUnit)
+	for i := range oldLeads {
+		if len(fileLeads[i])/max(len(fileUnit), 1)-len(oldLeads[i])/len(oldUnit) != shift {
+			return newText
+		}
+	}
+
+	for i, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		lead := leadingWhitespace(line)
+		level := len(lead)/len(oldUnit) + shift
+		if level < 0 {
+			return newText
+		}
+		lines[i] = strings.Repeat(fileUnit, level) + line[len(lead):]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// indentUnit returns one level of indentation for a set of leading
+// whitespace strings: a tab, or the largest run of spaces that divides every
+// lead. It returns "" when no line is indented, and false when the leads mix
+// tabs and spaces.
+func indentUnit(leads []string) (string, bool) {
+	width, tabs, spaces := 0, false, false
+	for _, lead := range leads {
+		if lead == "" {
+			continue
+		}
+		switch strings.Trim(lead, " ") {
+		case "":
+			spaces = true
+		default:
+			if strings.Trim(lead, "\t") != "" {
+				return "", false
+			}
+			tabs = true
+		}
+		width = gcd(width, len(lead))
+	}
+	switch {
+	case tabs && spaces:
+		return "", false
+	case tabs:
+		return "\t", true
+	case spaces:
+		return strings.Repeat(" ", width), true
+	}
+	return "", true
+}
+
+func gcd(a, b int) int {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+func leadingWhitespace(s string) string {
+	return s[:len(s)-len(strings.TrimLeft(s, " \t"))]
+}
+
+// closestMatchHint finds the block of lines most similar to old_text and
+// formats it with line numbers, or returns "" when nothing is close enough.
+func closestMatchHint(lines, oldLines []string) string {
+	if len(lines) == 0 || len(lines) > maxClosestMatchLines {
+		return ""
+	}
+
+	normalize := lineNormalizers[len(lineNormalizers)-1]
+	fileLines := make([]string, len(lines))
+	fileGrams := make([]map[string]int, len(lines))
+	for i, line := range lines {
+		fileLines[i] = normalize(line)
+		fileGrams[i] = bigrams(fileLines[i])
+	}
+	wantGrams := make([]map[string]int, len(oldLines))
+	wantLines := make([]string, len(oldLines))
+	for i, line := range oldLines {
+		wantLines[i] = normalize(line)
+		wantGrams[i] = bigrams(wantLines[i])
+	}
+
+	size := min(len(oldLines), len(lines))
+	best, bestScore := -1, 0.0
+	for i := 0; i+size <= len(lines); i++ {
+		score := 0.0
+		for j := 0; j < size; j++ {
+			score += bigramSimilarity(fileLines[i+j], wantLines[j], fileGrams[i+j], wantGrams[j])
+		}
+		score /= float64(len(oldLines))
+		if score > bestScore {
+			best, bestScore = i, score
+		}
+	}
+	if best < 0 || bestScore < minClosestMatchSimilarity {
+		return ""
+	}
+
+	var hint strings.Builder
+	fmt.Fprintf(&hint, ". Closest match at line %d (%.0f%% similar):", best+1, bestScore*100)
+	for j := 0; j < min(size, maxClosestMatchSnippet); j++ {
+		hint.WriteString("\n" + formatReadFileLinePrefix(int64(best+j+1)) + lines[best+j])
+	}
+	if size > maxClosestMatchSnippet {
+		hint.WriteString("\n...")
+	}
+	return hint.String()
+}
+
+func bigrams(s string) map[string]int {
+	grams := make(map[string]int, len(s))
+	for i := 0; i+1 < len(s); i++ {
+		grams[s[i:i+2]]++
+	}
+	return grams
+}
+
+// bigramSimilarity returns the Dice coefficient of the character bigrams of
+// a and b, from 0 (nothing shared) to 1 (identical).
+func bigramSimilarity(a, b string, aGrams, bGrams map[string]int) float64 {
+	if a == b {
+		return 1
+	}
+	if len(a) < 2 || len(b) < 2 {
+		return 0
+	}
+	if len(aGrams) > len(bGrams) {
+		aGrams, bGrams = bGrams, aGrams
+	}
+	shared := 0
+	for gram, count := range aGrams {
+		shared += min(count, bGrams[gram])
+	}
+	return 2 * float64(shared) / float64(len(a)+len(b)-2)
+}
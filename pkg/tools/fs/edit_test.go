@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // TestEditTool_EditFile_Success verifies successful file editing
@@ -462,3 +463,162 @@ func TestEditFileTool_Restricted_FileNotFound(t *testing.T) {
 	assert.True(t, result.IsError)
 	assert.Contains(t, result.ForLLM, "not found")
 }
+
+// TestMultiEditTool_AppliesEditsInOrder verifies that later edits see the result of earlier ones.
+func TestMultiEditTool_AppliesEditsInOrder(t *testing.T) {
+	workspace := t.TempDir()
+	testFile := "config.go"
+	err := os.WriteFile(filepath.Join(workspace, testFile), []byte("port := 80\nhost := \"a\"\n"), 0o644)
+	assert.NoError(t, err)
+
+	tool := NewMultiEditTool(workspace, true)
+	result := tool.Execute(context.Background(), map[string]any{
+		"path": testFile,
+		"edits": []any{
+			map[string]any{"old_text": "port := 80", "new_text": "port := 8080"},
+			map[string]any{"old_text": "8080", "new_text": "9090"},
+			map[string]any{"old_text": "\"a\"", "new_text": "\"b\""},
+		},
+	})
+
+	assert.False(t, result.IsError, "Expected success, got: %s", result.ForLLM)
+	assert.Equal(t, "File edited: config.go", result.ForLLM)
+	data, err := os.ReadFile(filepath.Join(workspace, testFile))
+	assert.NoError(t, err)
+	assert.Equal(t, "port := 9090\nhost := \"b\"\n", string(data))
+}
+
+// TestMultiEditTool_AllOrNothing verifies that a failing edit leaves the file untouched.
+func TestMultiEditTool_AllOrNothing(t *testing.T) {
+	workspace := t.TempDir()
+	testFile := "notes.txt"
+	err := os.WriteFile(filepath.Join(workspace, testFile), []byte("alpha beta"), 0o644)
+	assert.NoError(t, err)
+
+	tool := NewMultiEditTool(workspace, true)
+	result := tool.Execute(context.Background(), map[string]any{
+		"path": testFile,
+		"edits": []any{
+			map[string]any{"old_text": "alpha", "new_text": "ALPHA"},
+			map[string]any{"old_text": "gamma", "new_text": "GAMMA"},
+		},
+	})
+
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.ForLLM, "edit 2 of 2 failed, no changes were written")
+	data, err := os.ReadFile(filepath.Join(workspace, testFile))
+	assert.NoError(t, err)
+	assert.Equal(t, "alpha beta", string(data))
+}
+
+// TestMultiEditTool_InvalidEdits verifies argument validation.
+func TestMultiEditTool_InvalidEdits(t *testing.T) {
+	tool := NewMultiEditTool(t.TempDir(), true)
+
+	result := tool.Execute(context.Background(), map[string]any{"path": "a.txt", "edits": []any{}})
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.ForLLM, "non-empty list")
+
+	result = tool.Execute(context.Background(), map[string]any{
+		"path":  "a.txt",
+		"edits": []any{map[string]any{"old_text": "x"}},
+	})
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.ForLLM, "edit 1: new_text is required")
+}
+
+func TestReplaceEditContent_WhitespaceTolerant(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		oldText  string
+		newText  string
+		expected string
+	}{
+		{
+			name:     "trailing whitespace in file",
+			content:  "a := 1  \nb := 2\n",
+			oldText:  "a := 1\nb := 2",
+			newText:  "a := 3\nb := 4",
+			expected: "a := 3\nb := 4\n",
+		},
+		{
+			name:     "indentation reapplied",
+			content:  "func f() {\n\tif x {\n\t\treturn\n\t}\n}\n",
+			oldText:  "  if x {\n    return\n  }\n",
+			newText:  "  if x {\n    panic(x)\n  }\n",
+			expected: "func f() {\n\tif x {\n\t\tpanic(x)\n\t}\n}\n",
+		},
+		{
+			name:     "shifted by one level",
+			content:  "class A:\n    def f(self):\n        return 1\n",
+			oldText:  "def f(self):\n    return 1",
+			newText:  "def f(self):\n    if self:\n        return 2",
+			expected: "class A:\n    def f(self):\n        if self:\n            return 2\n",
+		},
+		{
+			name:     "crlf line endings kept",
+			content:  "one\r\ntwo  \r\nthree\r\n",
+			oldText:  "two\nthree",
+			newText:  "TWO\nTHREE",
+			expected: "one\r\nTWO\r\nTHREE\r\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := replaceEditContent([]byte(tt.content), tt.oldText, tt.newText)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, string(result))
+		})
+	}
+}
+
+func TestReplaceEditContent_FuzzyMatchMustBeUnique(t *testing.T) {
+	content := "x = 1 \ny = 2\nx = 1\t\n"
+	_, err := replaceEditContent([]byte(content), "x = 1\n", "x = 2\n")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "matches 2 places")
+}
+
+func TestReplaceEditContent_ClosestMatchHint(t *testing.T) {
+	content := "package main\n\nfunc main() {\n\tfmt.Println(\"hello, world\")\n}\n"
+	_, err := replaceEditContent([]byte(content), "fmt.Println(\"hello world\")", "x")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "old_text not found")
+	assert.Contains(t, err.Error(), "Closest match at line 4")
+	assert.Contains(t, err.Error(), "4|\tfmt.Println(\"hello, world\")")
+
+	_, err = replaceEditContent([]byte(content), "completely unrelated text", "x")
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), "Closest match")
+}
+
+func TestEditFileTool_PreviewDoesNotWrite(t *testing.T) {
+	workspace := t.TempDir()
+	target := filepath.Join(workspace, "main.go")
+	require.NoError(t, os.WriteFile(target, []byte("a := 1\n"), 0o644))
+
+	tool := NewEditFileTool(workspace, true)
+	result := tool.Execute(context.Background(), map[string]any{
+		"path":     "main.go",
+		"old_text": "a := 1",
+		"new_text": "a := 2",
+		"preview":  true,
+	})
+	require.False(t, result.IsError, result.ForLLM)
+	assert.Contains(t, result.ForLLM, "nothing was written")
+	assert.Contains(t, result.ForLLM, "a := 2")
+
+	data, err := os.ReadFile(target)
+	require.NoError(t, err)
+	assert.Equal(t, "a := 1\n", string(data))
+
+	result = tool.Execute(context.Background(), map[string]any{
+		"path":     "main.go",
+		"old_text": "missing",
+		"new_text": "x",
+		"preview":  true,
+	})
+	assert.True(t, result.IsError)
+}
@@ -0,0 +1,182 @@
+package fstools
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/unicode"
+)
+
+// textEncoding is a non-UTF-8 text encoding the filesystem tools can read and
+// write. Files in these encodings are converted to UTF-8 for the model and
+// back when they are changed, so an edit does not corrupt them.
+type textEncoding struct {
+	name string
+	enc  encoding.Encoding
+	bom  []byte
+}
+
+var (
+	utf8BOM    = []byte{0xEF, 0xBB, 0xBF}
+	utf16LEBOM = []byte{0xFF, 0xFE}
+	utf16BEBOM = []byte{0xFE, 0xFF}
+
+	encodingUTF16LE = &textEncoding{
+		name: "utf-16le",
+		enc:  unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM),
+		bom:  utf16LEBOM,
+	}
+	encodingUTF16BE = &textEncoding{
+		name: "utf-16be",
+		enc:  unicode.UTF16(unicode.BigEndian, unicode.IgnoreBOM),
+		bom:  utf16BEBOM,
+	}
+	encodingWindows1252 = &textEncoding{name: "windows-1252", enc: charmap.Windows1252}
+)
+
+// writeEncodings lists the values accepted by write_file's encoding parameter.
+var writeEncodings = map[string]*textEncoding{
+	"utf-16le":     encodingUTF16LE,
+	"utf-16be":     encodingUTF16BE,
+	"utf-16":       encodingUTF16LE,
+	"windows-1252": encodingWindows1252,
+	"cp1252":       encodingWindows1252,
+	"latin-1":      {name: "latin-1", enc: charmap.ISO8859_1},
+	"iso-8859-1":   {name: "latin-1", enc: charmap.ISO8859_1},
+}
+
+// detectTextEncoding reports the encoding of a file that starts with sample
+// when it is UTF-16 or Windows-1252 text. It returns nil for UTF-8, ASCII and
+// binary data, which the tools already handle as raw bytes.
+func detectTextEncoding(sample []byte) *textEncoding {
+	switch {
+	case len(sample) == 0 || bytes.HasPrefix(sample, utf8BOM):
+		return nil
+	case bytes.HasPrefix(sample, utf16LEBOM):
+		return encodingUTF16LE
+	case bytes.HasPrefix(sample, utf16BEBOM):
+		return encodingUTF16BE
+	}
+
+	// Without a BOM, UTF-16 text that is mostly ASCII has a NUL in every
+	// other byte.
+	if len(sample) >= 4 {
+		var evenZeros, oddZeros int
+		pairs := len(sample) / 2
+		for i := 0; i+1 < len(sample); i += 2 {
+			if sample[i] == 0 {
+				evenZeros++
+			}
+			if sample[i+1] == 0 {
+				oddZeros++
+			}
+		}
+		switch {
+		case oddZeros*10 >= pairs*4 && evenZeros*20 < pairs:
+			return plausibleText(sample, encodingUTF16LE)
+		case evenZeros*10 >= pairs*4 && oddZeros*20 < pairs:
+			return plausibleText(sample, encodingUTF16BE)
+		}
+	}
+
+	if bytes.IndexByte(sample, 0) >= 0 || validUTF8Prefix(sample) {
+		return nil
+	}
+	// These bytes are unassigned in Windows-1252, so data containing them is
+	// not text in that encoding either.
+	for _, b := range sample {
+		switch b {
+		case 0x81, 0x8d, 0x8f, 0x90, 0x9d:
+			return nil
+		}
+	}
+	return plausibleText(sample, encodingWindows1252)
+}
+
+// plausibleText returns enc when sample decodes to something that reads as
+// text rather than binary.
+func plausibleText(sample []byte, enc *textEncoding) *textEncoding {
+	if len(enc.bom) == 2 {
+		sample = sample[:len(sample)&^1]
+	}
+	decoded, err := enc.enc.NewDecoder().Bytes(sample)
+	if err != nil || isBinaryReadFileData(trimIncompleteRune(decoded)) {
+		return nil
+	}
+	return enc
+}
+
+// validUTF8Prefix reports whether data is valid UTF-8, allowing a rune cut
+// off at the end, as happens when data is a fixed-size sample of a file.
+func validUTF8Prefix(data []byte) bool {
+	return utf8.Valid(trimIncompleteRune(data))
+}
+
+func trimIncompleteRune(data []byte) []byte {
+	for i := 1; i <= utf8.UTFMax-1 && i <= len(data); i++ {
+		if utf8.RuneStart(data[len(data)-i]) {
+			if !utf8.FullRune(data[len(data)-i:]) {
+				return data[:len(data)-i]
+			}
+			break
+		}
+	}
+	return data
+}
+
+// decodeText converts content in enc to UTF-8, dropping a leading BOM.
+func decodeText(content []byte, enc *textEncoding) ([]byte, error) {
+	content = bytes.TrimPrefix(content, enc.bom)
+	decoded, err := enc.enc.NewDecoder().Bytes(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s text: %w", enc.name, err)
+	}
+	return decoded, nil
+}
+
+// encodeText converts UTF-8 text to enc. withBOM prepends the encoding's byte
+// order mark, if it has one.
+func encodeText(text []byte, enc *textEncoding, withBOM bool) ([]byte, error) {
+	encoded, err := enc.enc.NewEncoder().Bytes(text)
+	if err != nil {
+		return nil, fmt.Errorf("content cannot be represented in %s: %w", enc.name, err)
+	}
+	if withBOM && len(enc.bom) > 0 {
+		encoded = append(bytes.Clone(enc.bom), encoded...)
+	}
+	return encoded, nil
+}
+
+// decodeFileText returns content as UTF-8 together with its original
+// encoding and whether it started with a BOM. UTF-8 content is returned
+// unchanged with a nil encoding.
+func decodeFileText(content []byte) ([]byte, *textEncoding, bool, error) {
+	sample := content[:min(len(content), 512)]
+	enc := detectTextEncoding(sample)
+	if enc == nil {
+		return content, nil, false, nil
+	}
+	hasBOM := len(enc.bom) > 0 && bytes.HasPrefix(content, enc.bom)
+	decoded, err := decodeText(content, enc)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	return decoded, enc, hasBOM, nil
+}
+
+// encodeFileText is the inverse of decodeFileText.
+func encodeFileText(text []byte, enc *textEncoding, withBOM bool) ([]byte, error) {
+	if enc == nil {
+		return text, nil
+	}
+	return encodeText(text, enc, withBOM)
+}
+
+// writeEncodingNames lists the accepted write encodings for error messages.
+func writeEncodingNames() string {
+	return strings.Join([]string{"utf-8", "utf-8-bom", "utf-16le", "utf-16be", "windows-1252", "latin-1"}, ", ")
+}
@@ -0,0 +1,135 @@
+package fstools
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func utf16LE(t *testing.T, s string, withBOM bool) []byte {
+	t.Helper()
+	data, err := encodeText([]byte(s), encodingUTF16LE, withBOM)
+	require.NoError(t, err)
+	return data
+}
+
+func TestDetectTextEncoding(t *testing.T) {
+	be, err := encodeText([]byte("hello world"), encodingUTF16BE, false)
+	require.NoError(t, err)
+	tests := []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{"ascii", []byte("plain text\n"), ""},
+		{"utf-8", []byte("café naïve\n"), ""},
+		{"utf-8 cut mid rune", []byte("café")[:4], ""},
+		{"utf-16le bom", utf16LE(t, "name,value\n", true), "utf-16le"},
+		{"utf-16le no bom", utf16LE(t, "name,value\n", false), "utf-16le"},
+		{"utf-16be no bom", be, "utf-16be"},
+		{"windows-1252", []byte("caf\xe9 \x93quoted\x94\n"), "windows-1252"},
+		{"binary", []byte{0x89, 'P', 'N', 'G', 0, 0, 0, 0x0d, 1, 2, 3, 0}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ""
+			if enc := detectTextEncoding(tt.data); enc != nil {
+				got = enc.name
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestReadFileTool_ConvertsUTF16(t *testing.T) {
+	workspace := t.TempDir()
+	content := "id,city\n1,München\n"
+	require.NoError(t, os.WriteFile(filepath.Join(workspace, "export.csv"), utf16LE(t, content, true), 0o644))
+
+	result := NewReadFileTool(workspace, true, 0).Execute(context.Background(), map[string]any{"path": "export.csv"})
+	require.False(t, result.IsError, result.ForLLM)
+	assert.Contains(t, result.ForLLM, "encoding: utf-16le, converted to UTF-8")
+	assert.True(t, strings.HasSuffix(result.ForLLM, "\n\n"+content), result.ForLLM)
+
+	result = NewReadFileLinesTool(workspace, true, 0).Execute(context.Background(), map[string]any{"path": "export.csv"})
+	require.False(t, result.IsError, result.ForLLM)
+	assert.Contains(t, result.ForLLM, "encoding: utf-16le")
+	assert.Contains(t, result.ForLLM, "2|1,München")
+}
+
+func TestReadFileTool_UTF16PagesStayAligned(t *testing.T) {
+	workspace := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(workspace, "a.txt"), utf16LE(t, "abcdef", true), 0o644))
+	tool := NewReadFileTool(workspace, true, 0)
+
+	result := tool.Execute(context.Background(), map[string]any{"path": "a.txt", "length": 5})
+	require.False(t, result.IsError, result.ForLLM)
+	assert.Contains(t, result.ForLLM, "offset=4")
+	assert.True(t, strings.HasSuffix(result.ForLLM, "\n\na"), result.ForLLM)
+
+	result = tool.Execute(context.Background(), map[string]any{"path": "a.txt", "offset": 3})
+	assert.True(t, result.IsError)
+}
+
+func TestReadFileTool_ConvertsWindows1252(t *testing.T) {
+	workspace := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(workspace, "notes.txt"), []byte("caf\xe9 \x80 5\n"), 0o644))
+
+	result := NewReadFileTool(workspace, true, 0).Execute(context.Background(), map[string]any{"path": "notes.txt"})
+	require.False(t, result.IsError, result.ForLLM)
+	assert.Contains(t, result.ForLLM, "encoding: windows-1252")
+	assert.True(t, strings.HasSuffix(result.ForLLM, "café € 5\n"), result.ForLLM)
+}
+
+func TestWriteFileTool_Encoding(t *testing.T) {
+	workspace := t.TempDir()
+	tool := NewWriteFileTool(workspace, true)
+
+	result := tool.Execute(context.Background(), map[string]any{
+		"path": "out.txt", "content": "München", "encoding": "utf-16le",
+	})
+	require.False(t, result.IsError, result.ForLLM)
+	data, err := os.ReadFile(filepath.Join(workspace, "out.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, utf16LE(t, "München", true), data)
+
+	result = tool.Execute(context.Background(), map[string]any{
+		"path": "latin.txt", "content": "café", "encoding": "latin-1",
+	})
+	require.False(t, result.IsError, result.ForLLM)
+	data, err = os.ReadFile(filepath.Join(workspace, "latin.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("caf\xe9"), data)
+
+	result = tool.Execute(context.Background(), map[string]any{
+		"path": "bad.txt", "content": "中文", "encoding": "latin-1",
+	})
+	require.True(t, result.IsError)
+	assert.Contains(t, result.ForLLM, "cannot be represented")
+
+	result = tool.Execute(context.Background(), map[string]any{"path": "x.txt", "content": "x", "encoding": "ebcdic"})
+	require.True(t, result.IsError)
+	assert.Contains(t, result.ForLLM, "unsupported encoding")
+}
+
+func TestEditFileTool_PreservesUTF16(t *testing.T) {
+	workspace := t.TempDir()
+	path := filepath.Join(workspace, "data.csv")
+	require.NoError(t, os.WriteFile(path, utf16LE(t, "city\nMunich\n", true), 0o644))
+
+	result := NewEditFileTool(workspace, true).Execute(context.Background(), map[string]any{
+		"path": "data.csv", "old_text": "Munich", "new_text": "München",
+	})
+	require.False(t, result.IsError, result.ForLLM)
+	require.NoError(t, appendFile(buildFs(workspace, true, nil), "data.csv", "Berlin\n"))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.True(t, bytes.Equal(utf16LE(t, "city\nMünchen\nBerlin\n", true), data), "got % x", data)
+}
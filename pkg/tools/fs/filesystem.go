@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"io"
@@ -18,6 +19,8 @@ import (
 	"time"
 	"unicode/utf8"
 
+	"golang.org/x/text/transform"
+
 	"github.com/sipeed/picoclaw/pkg/fileutil"
 	"github.com/sipeed/picoclaw/pkg/logger"
 )
@@ -340,11 +343,11 @@ func (t *ReadFileLinesTool) Name() string {
 }
 
 func (t *ReadFileTool) Description() string {
-	return "Read the contents of a file. Supports pagination via `offset` and `length`."
+	return "Read the contents of a file. Supports pagination via `offset` and `length`. Binary files are summarized with a hex dump and their printable strings; set `base64` to true to get the raw bytes base64-encoded instead."
 }
 
 func (t *ReadFileLinesTool) Description() string {
-	return "Read a UTF-8 text file from the filesystem. Output always includes line numbers in the format `LINE_NUMBER|LINE_CONTENT` (1-indexed). Supports partial reads via `start_line` and `max_lines` for large text files."
+	return "Read a UTF-8 text file from the filesystem. Output always includes line numbers in the format `LINE_NUMBER|LINE_CONTENT` (1-indexed). Supports partial reads via `start_line` and either `end_line` or `max_lines` for large text files; the header reports the file's total line count."
 }
 
 func (t *ReadFileTool) Parameters() map[string]any {
@@ -365,6 +368,11 @@ func (t *ReadFileTool) Parameters() map[string]any {
 				"description": "Maximum number of bytes to read.",
 				"default":     t.maxSize,
 			},
+			"base64": map[string]any{
+				"type":        "boolean",
+				"description": "Set to true to return the bytes base64-encoded, for binary files whose raw content is needed.",
+				"default":     false,
+			},
 		},
 		"required": []string{"path"},
 	}
@@ -383,6 +391,10 @@ func (t *ReadFileLinesTool) Parameters() map[string]any {
 				"description": "Line number to start reading from (1-indexed, inclusive).",
 				"default":     1,
 			},
+			"end_line": map[string]any{
+				"type":        "integer",
+				"description": "Line number to stop reading at (1-indexed, inclusive). Cannot be combined with max_lines.",
+			},
 			"max_lines": map[string]any{
 				"type":        "integer",
 				"description": "Maximum number of lines to read.",
@@ -418,6 +430,7 @@ func (t *ReadFileTool) Execute(ctx context.Context, args map[string]any) *ToolRe
 	if length > t.maxSize {
 		length = t.maxSize
 	}
+	encodeBase64, _ := args["base64"].(bool)
 
 	file, err := t.fs.Open(path)
 	if err != nil {
@@ -435,6 +448,19 @@ func (t *ReadFileTool) Execute(ctx context.Context, args map[string]any) *ToolRe
 	// it into the LLM context. Seeking back to 0 afterwards restores state.
 	sniff := make([]byte, 512)
 	sniffN, _ := file.Read(sniff)
+	binary := isBinaryReadFileData(sniff[:sniffN])
+	// UTF-16 and Windows-1252 text is converted to UTF-8 instead of being
+	// summarized as binary.
+	var textEnc *textEncoding
+	if !encodeBase64 {
+		textEnc = detectTextEncoding(sniff[:sniffN])
+	}
+	if textEnc != nil {
+		binary = false
+		if len(textEnc.bom) == 2 && offset%2 != 0 {
+			return ErrorResult(fmt.Sprintf("offset must be even for %s files", textEnc.name))
+		}
+	}
 
 	// Reset read position to beginning before applying the caller's offset.
 	if seeker, ok := file.(io.Seeker); ok {
@@ -487,6 +513,10 @@ func (t *ReadFileTool) Execute(ctx context.Context, args map[string]any) *ToolRe
 	// hasMore is true only when we actually got the extra probe byte.
 	hasMore := int64(n) > length
 	data := probe[:min(int64(n), length)]
+	if textEnc != nil && len(textEnc.bom) == 2 && len(data)%2 != 0 && hasMore {
+		// Stop on a code unit boundary so the next page decodes cleanly.
+		data = data[:len(data)-1]
+	}
 
 	if len(data) == 0 {
 		return NewToolResult("[END OF FILE - no content at this offset]")
@@ -500,17 +530,27 @@ func (t *ReadFileTool) Execute(ctx context.Context, args map[string]any) *ToolRe
 	// header parseable by downstream tools and log processors.
 	readRange := fmt.Sprintf("bytes %d-%d", offset, readEnd-1)
 
+	var contentNote string
+	switch {
+	case encodeBase64:
+		contentNote = " | encoding: base64"
+	case binary:
+		contentNote = " | binary: " + http.DetectContentType(sniff[:sniffN])
+	case textEnc != nil:
+		contentNote = " | encoding: " + textEnc.name + ", converted to UTF-8"
+	}
+
 	displayPath := filepath.Base(path)
 	var header string
 	if totalSize >= 0 {
 		header = fmt.Sprintf(
-			"[file: %s | total: %d bytes | read: %s]",
-			displayPath, totalSize, readRange,
+			"[file: %s | total: %d bytes | read: %s%s]",
+			displayPath, totalSize, readRange, contentNote,
 		)
 	} else {
 		header = fmt.Sprintf(
-			"[file: %s | read: %s | total size unknown]",
-			displayPath, readRange,
+			"[file: %s | read: %s | total size unknown%s]",
+			displayPath, readRange, contentNote,
 		)
 	}
 
@@ -528,8 +568,24 @@ func (t *ReadFileTool) Execute(ctx context.Context, args map[string]any) *ToolRe
 			"path":       path,
 			"bytes_read": len(data),
 			"has_more":   hasMore,
+			"binary":     binary,
 		})
 
+	switch {
+	case encodeBase64:
+		return NewToolResult(header + "\n\n" + base64.StdEncoding.EncodeToString(data))
+	case binary:
+		return NewToolResult(header + "\n\n" + formatBinaryPreview(data, offset))
+	case textEnc != nil:
+		if offset == 0 {
+			data = bytes.TrimPrefix(data, textEnc.bom)
+		}
+		decoded, err := decodeText(data, textEnc)
+		if err != nil {
+			return ErrorResult(err.Error())
+		}
+		data = decoded
+	}
 	return NewToolResult(header + "\n\n" + string(data))
 }
 
@@ -566,6 +622,19 @@ func (t *ReadFileLinesTool) Execute(ctx context.Context, args map[string]any) *T
 			return ErrorResult("max_lines, if provided, must be > 0")
 		}
 	}
+	if raw, exists := args["end_line"]; exists && raw != nil {
+		if limit > 0 {
+			return ErrorResult("end_line and max_lines cannot be combined; use one of them")
+		}
+		endLine, endErr := getInt64Arg(args, "end_line", 0)
+		if endErr != nil {
+			return ErrorResult(endErr.Error())
+		}
+		if endLine < startLine {
+			return ErrorResult("end_line must be >= start_line")
+		}
+		limit = endLine - startLine + 1
+	}
 
 	file, err := t.fs.Open(path)
 	if err != nil {
@@ -573,8 +642,12 @@ func (t *ReadFileLinesTool) Execute(ctx context.Context, args map[string]any) *T
 	}
 	defer file.Close()
 
-	if info, statErr := file.Stat(); statErr == nil && info.IsDir() {
-		return ErrorResult(fmt.Sprintf("failed to open file: path is a directory: %s", path))
+	fileSize := int64(-1)
+	if info, statErr := file.Stat(); statErr == nil {
+		if info.IsDir() {
+			return ErrorResult(fmt.Sprintf("failed to open file: path is a directory: %s", path))
+		}
+		fileSize = info.Size()
 	}
 
 	sample := make([]byte, 512)
@@ -583,11 +656,18 @@ func (t *ReadFileLinesTool) Execute(ctx context.Context, args map[string]any) *T
 		return ErrorResult(fmt.Sprintf("failed to read file: %v", readErr))
 	}
 	sample = sample[:sampleN]
-	if isBinaryReadFileData(sample) {
+	textEnc := detectTextEncoding(sample)
+	if textEnc == nil && isBinaryReadFileData(sample) {
 		return ErrorResult("file appears to be binary; switch read_file mode to 'bytes' for byte-based inspection")
 	}
 
-	reader := bufio.NewReaderSize(io.MultiReader(bytes.NewReader(sample), file), 32*1024)
+	var source io.Reader = io.MultiReader(bytes.NewReader(sample), file)
+	if textEnc != nil {
+		// Lines are counted and returned in UTF-8.
+		source = io.MultiReader(bytes.NewReader(bytes.TrimPrefix(sample, textEnc.bom)), file)
+		source = transform.NewReader(source, textEnc.enc.NewDecoder())
+	}
+	reader := bufio.NewReaderSize(source, 32*1024)
 
 	var content strings.Builder
 	lineIndex := int64(1)
@@ -656,13 +736,33 @@ func (t *ReadFileLinesTool) Execute(ctx context.Context, args map[string]any) *T
 		return NewToolResult(fmt.Sprintf("[END OF FILE - no content at or after start_line=%d]", startLine))
 	}
 
+	// Report the total line count so the model can plan further reads. The
+	// rest of the file is only scanned, not buffered; a line cut mid-way
+	// leaves the reader in an unknown position, so it is skipped there.
+	totalLines := int64(-1)
+	switch {
+	case reachedEOF:
+		totalLines = lineIndex - 1
+	case !lineTruncated && fileSize >= 0 && fileSize <= maxStatLineCountSize:
+		if remaining, countErr := countLines(reader); countErr == nil {
+			totalLines = lineIndex - 1 + int64(remaining)
+		}
+	}
+
 	start := startLine
 	endLine := startLine + linesRead - 1
 	displayPath := filepath.Base(path)
 	header := fmt.Sprintf(
-		"[file: %s | read: lines %d-%d (1-indexed) | file_bytes: %d | output_bytes: %d]",
+		"[file: %s | read: lines %d-%d (1-indexed) | file_bytes: %d | output_bytes: %d",
 		displayPath, start, endLine, fileBytesRead, outputBytesRead,
 	)
+	if totalLines >= 0 {
+		header += fmt.Sprintf(" | total_lines: %d", totalLines)
+	}
+	if textEnc != nil {
+		header += " | encoding: " + textEnc.name + ", converted to UTF-8"
+	}
+	header += "]"
 
 	switch {
 	case lineTruncated:
@@ -908,7 +1008,7 @@ func (t *WriteFileTool) Name() string {
 }
 
 func (t *WriteFileTool) Description() string {
-	desc := "Write content to a file, replacing any existing content. Content is written byte-for-byte after argument decoding. Standard JSON escaping applies: \\n for newline and \\\\n for a literal backslash-n sequence. If the file already exists you must set overwrite=true, which replaces the ENTIRE file."
+	desc := "Write content to a file, replacing any existing content. Content is written byte-for-byte after argument decoding, as UTF-8 unless encoding is set. Standard JSON escaping applies: \\n for newline and \\\\n for a literal backslash-n sequence. If the file already exists you must set overwrite=true, which replaces the ENTIRE file."
 	if phrase := t.altToolsPhrase(); phrase != "" {
 		desc += fmt.Sprintf(
 			" To add to or change part of an existing file without losing its current contents, use %s instead.",
@@ -942,6 +1042,18 @@ func (t *WriteFileTool) Parameters() map[string]any {
 				"description": overwriteDesc,
 				"default":     false,
 			},
+			"preview": map[string]any{
+				"type":        "boolean",
+				"description": "Set to true to return a unified diff of the change without writing the file.",
+				"default":     false,
+			},
+			"encoding": map[string]any{
+				"type": "string",
+				"enum": []string{"utf-8", "utf-8-bom", "utf-16le", "utf-16be", "windows-1252", "latin-1"},
+				"description": "Encoding to store the file in. Use the encoding read_file reported " +
+					"when rewriting a non-UTF-8 file; utf-16 is written with a byte order mark.",
+				"default": "utf-8",
+			},
 		},
 		"required": []string{"path", "content"},
 	}
@@ -960,6 +1072,23 @@ func (t *WriteFileTool) Execute(ctx context.Context, args map[string]any) *ToolR
 
 	overwrite, _ := args["overwrite"].(bool)
 
+	data := []byte(content)
+	switch name, _ := args["encoding"].(string); strings.ToLower(name) {
+	case "", "utf-8", "utf8":
+	case "utf-8-bom":
+		data = append(bytes.Clone(utf8BOM), data...)
+	default:
+		enc, ok := writeEncodings[strings.ToLower(name)]
+		if !ok {
+			return ErrorResult(fmt.Sprintf("unsupported encoding %q: use one of %s", name, writeEncodingNames()))
+		}
+		encoded, err := encodeText(data, enc, true)
+		if err != nil {
+			return ErrorResult(err.Error())
+		}
+		data = encoded
+	}
+
 	if !overwrite {
 		if _, err := t.fs.Open(path); err == nil {
 			if phrase := t.altToolsPhrase(); phrase != "" {
@@ -980,7 +1109,23 @@ func (t *WriteFileTool) Execute(ctx context.Context, args map[string]any) *ToolR
 		}
 	}
 
-	if err := t.fs.WriteFile(path, []byte(content)); err != nil {
+	if preview, _ := args["preview"].(bool); preview {
+		before, err := t.fs.ReadFile(path)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return ErrorResult(err.Error())
+		}
+		if before, _, _, err = decodeFileText(before); err != nil {
+			return ErrorResult(err.Error())
+		}
+		return DiffPreviewResult(path, before, []byte(content))
+	}
+
+	unlock, err := t.fs.Lock(path)
+	if err != nil {
+		return ErrorResult(err.Error())
+	}
+	defer unlock()
+	if err := t.fs.WriteFile(path, data); err != nil {
 		return ErrorResult(err.Error())
 	}
 
@@ -1004,7 +1149,7 @@ func (t *ListDirTool) Name() string {
 }
 
 func (t *ListDirTool) Description() string {
-	return "List files and directories in a path"
+	return "List files and directories in a path with their size, modification time and, for directories, entry count. Use sort=mtime or sort=size with limit to find the newest or largest entries, and format=json for structured output. Set recursive=true to get an indented tree with file sizes, limited by max_depth and max_entries; .git, node_modules, .venv and __pycache__ are skipped unless ignore is given."
 }
 
 func (t *ListDirTool) Parameters() map[string]any {
@@ -1015,6 +1160,43 @@ func (t *ListDirTool) Parameters() map[string]any {
 				"type":        "string",
 				"description": "Path to list",
 			},
+			"sort": map[string]any{
+				"type":        "string",
+				"enum":        []string{"name", "mtime", "size"},
+				"description": "Flat mode: name (directories first), mtime (newest first) or size (largest first)",
+				"default":     "name",
+			},
+			"limit": map[string]any{
+				"type":        "integer",
+				"description": "Flat mode: maximum number of entries to return after sorting; 0 returns all",
+				"default":     0,
+			},
+			"format": map[string]any{
+				"type":        "string",
+				"enum":        []string{"text", "json"},
+				"description": "Flat mode: text lines or a JSON object with an entries array",
+				"default":     "text",
+			},
+			"recursive": map[string]any{
+				"type":        "boolean",
+				"description": "List subdirectories recursively as an indented tree",
+				"default":     false,
+			},
+			"max_depth": map[string]any{
+				"type":        "integer",
+				"description": "Recursive mode: number of directory levels to descend",
+				"default":     defaultTreeDepth,
+			},
+			"max_entries": map[string]any{
+				"type":        "integer",
+				"description": "Recursive mode: maximum number of entries to print",
+				"default":     defaultTreeMaxEntries,
+			},
+			"ignore": map[string]any{
+				"type":        "array",
+				"items":       map[string]any{"type": "string"},
+				"description": "Recursive mode: glob patterns for file or directory names to skip. Replaces the default list.",
+			},
 		},
 		"required": []string{"path"},
 	}
@@ -1026,32 +1208,31 @@ func (t *ListDirTool) Execute(ctx context.Context, args map[string]any) *ToolRes
 		path = "."
 	}
 
-	entries, err := t.fs.ReadDir(path)
-	if err != nil {
-		return ErrorResult(fmt.Sprintf("failed to read directory: %v", err))
-	}
-	return formatDirEntries(entries)
-}
-
-func formatDirEntries(entries []os.DirEntry) *ToolResult {
-	var result strings.Builder
-	for _, entry := range entries {
-		if entry.IsDir() {
-			result.WriteString("DIR:  " + entry.Name() + "\n")
-		} else {
-			result.WriteString("FILE: " + entry.Name() + "\n")
-		}
+	if recursive, _ := args["recursive"].(bool); recursive {
+		return t.listTree(ctx, path, args)
 	}
-	return NewToolResult(result.String())
+	return t.listFlat(path, args)
 }
 
-// fileSystem abstracts reading, writing, and listing files, allowing both
+// fileSystem abstracts reading, writing, listing, and removing files, allowing both
 // unrestricted (host filesystem) and sandbox (os.Root) implementations to share the same polymorphic interface.
 type fileSystem interface {
 	ReadFile(path string) ([]byte, error)
 	WriteFile(path string, data []byte) error
 	ReadDir(path string) ([]os.DirEntry, error)
 	Open(path string) (fs.File, error)
+	Remove(path string) error
+	Lstat(path string) (fs.FileInfo, error)
+	Chmod(path string, mode fs.FileMode) error
+	// MkdirAll creates path and any missing parents. WriteFile creates parent
+	// directories itself, so this is only needed for directories that stay
+	// empty.
+	MkdirAll(path string) error
+	// Lock takes the advisory write locks for paths and returns their release
+	// function. Tools hold them across a read-modify-write so concurrent
+	// agents cannot lose each other's edits. The locks are not reentrant, so
+	// every path an operation touches is passed in one call.
+	Lock(paths ...string) (func(), error)
 }
 
 // hostFs is an unrestricted fileReadWriter that operates directly on the host filesystem.
@@ -1095,6 +1276,68 @@ func (h *hostFs) Open(path string) (fs.File, error) {
 	return f, nil
 }
 
+func (h *hostFs) Remove(path string) error {
+	if err := os.Remove(path); err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("failed to delete: file not found: %w", err)
+		}
+		if os.IsPermission(err) {
+			return fmt.Errorf("failed to delete: access denied: %w", err)
+		}
+		return fmt.Errorf("failed to delete: %w", err)
+	}
+	return nil
+}
+
+func (h *hostFs) Lstat(path string) (fs.FileInfo, error) {
+	info, err := os.Lstat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to stat: file not found: %w", err)
+		}
+		if os.IsPermission(err) {
+			return nil, fmt.Errorf("failed to stat: access denied: %w", err)
+		}
+		return nil, fmt.Errorf("failed to stat: %w", err)
+	}
+	return info, nil
+}
+
+func (h *hostFs) Chmod(path string, mode fs.FileMode) error {
+	if err := os.Chmod(path, mode); err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("failed to chmod: file not found: %w", err)
+		}
+		if os.IsPermission(err) {
+			return fmt.Errorf("failed to chmod: access denied: %w", err)
+		}
+		return fmt.Errorf("failed to chmod: %w", err)
+	}
+	return nil
+}
+
+func (h *hostFs) MkdirAll(path string) error {
+	if err := os.MkdirAll(path, 0o755); err != nil {
+		if os.IsPermission(err) {
+			return fmt.Errorf("failed to create directory: access denied: %w", err)
+		}
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	return nil
+}
+
+func (h *hostFs) Lock(paths ...string) (func(), error) {
+	return lockResolved(h.lockTarget, paths)
+}
+
+func (h *hostFs) lockTarget(path string) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to lock: %w", err)
+	}
+	return abs, nil
+}
+
 // sandboxFs is a sandboxed fileSystem that operates within a strictly defined workspace using os.Root.
 type sandboxFs struct {
 	workspace string
@@ -1229,6 +1472,89 @@ func (r *sandboxFs) Open(path string) (fs.File, error) {
 	return f, err
 }
 
+func (r *sandboxFs) Remove(path string) error {
+	return r.execute(path, func(root *os.Root, relPath string) error {
+		if relPath == "." {
+			return fmt.Errorf("failed to delete: refusing to delete the workspace root")
+		}
+		if err := root.Remove(relPath); err != nil {
+			if os.IsNotExist(err) {
+				return fmt.Errorf("failed to delete: file not found: %w", err)
+			}
+			if os.IsPermission(err) || strings.Contains(err.Error(), "escapes from parent") ||
+				strings.Contains(err.Error(), "permission denied") {
+				return fmt.Errorf("failed to delete: access denied: %w", err)
+			}
+			return fmt.Errorf("failed to delete: %w", err)
+		}
+		return nil
+	})
+}
+
+func (r *sandboxFs) Lstat(path string) (fs.FileInfo, error) {
+	var info fs.FileInfo
+	err := r.execute(path, func(root *os.Root, relPath string) error {
+		fileInfo, err := root.Lstat(relPath)
+		if err != nil {
+			if os.IsNotExist(err) {
+				return fmt.Errorf("failed to stat: file not found: %w", err)
+			}
+			if os.IsPermission(err) || strings.Contains(err.Error(), "escapes from parent") ||
+				strings.Contains(err.Error(), "permission denied") {
+				return fmt.Errorf("failed to stat: access denied: %w", err)
+			}
+			return fmt.Errorf("failed to stat: %w", err)
+		}
+		info = fileInfo
+		return nil
+	})
+	return info, err
+}
+
+func (r *sandboxFs) Chmod(path string, mode fs.FileMode) error {
+	return r.execute(path, func(root *os.Root, relPath string) error {
+		if err := root.Chmod(relPath, mode); err != nil {
+			if os.IsNotExist(err) {
+				return fmt.Errorf("failed to chmod: file not found: %w", err)
+			}
+			if os.IsPermission(err) || strings.Contains(err.Error(), "escapes from parent") ||
+				strings.Contains(err.Error(), "permission denied") {
+				return fmt.Errorf("failed to chmod: access denied: %w", err)
+			}
+			return fmt.Errorf("failed to chmod: %w", err)
+		}
+		return nil
+	})
+}
+
+func (r *sandboxFs) MkdirAll(path string) error {
+	return r.execute(path, func(root *os.Root, relPath string) error {
+		if err := root.MkdirAll(relPath, 0o755); err != nil {
+			if os.IsPermission(err) || strings.Contains(err.Error(), "escapes from parent") ||
+				strings.Contains(err.Error(), "permission denied") {
+				return fmt.Errorf("failed to create directory: access denied: %w", err)
+			}
+			return fmt.Errorf("failed to create directory: %w", err)
+		}
+		return nil
+	})
+}
+
+func (r *sandboxFs) Lock(paths ...string) (func(), error) {
+	return lockResolved(r.lockTarget, paths)
+}
+
+func (r *sandboxFs) lockTarget(path string) (string, error) {
+	if r.workspace == "" {
+		return "", fmt.Errorf("workspace is not defined")
+	}
+	relPath, err := getSafeRelPath(r.workspace, path)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(r.workspace, relPath), nil
+}
+
 // whitelistFs wraps a sandboxFs and allows access to specific paths outside
 // the workspace when they match any of the provided patterns.
 type whitelistFs struct {
@@ -1269,6 +1595,43 @@ func (w *whitelistFs) Open(path string) (fs.File, error) {
 	return w.sandbox.Open(path)
 }
 
+func (w *whitelistFs) Remove(path string) error {
+	if w.matches(path) {
+		return w.host.Remove(path)
+	}
+	return w.sandbox.Remove(path)
+}
+
+func (w *whitelistFs) Lstat(path string) (fs.FileInfo, error) {
+	if w.matches(path) {
+		return w.host.Lstat(path)
+	}
+	return w.sandbox.Lstat(path)
+}
+
+func (w *whitelistFs) Chmod(path string, mode fs.FileMode) error {
+	if w.matches(path) {
+		return w.host.Chmod(path, mode)
+	}
+	return w.sandbox.Chmod(path, mode)
+}
+
+func (w *whitelistFs) MkdirAll(path string) error {
+	if w.matches(path) {
+		return w.host.MkdirAll(path)
+	}
+	return w.sandbox.MkdirAll(path)
+}
+
+func (w *whitelistFs) Lock(paths ...string) (func(), error) {
+	return lockResolved(func(path string) (string, error) {
+		if w.matches(path) {
+			return w.host.lockTarget(path)
+		}
+		return w.sandbox.lockTarget(path)
+	}, paths)
+}
+
 // buildFs returns the appropriate fileSystem implementation based on restriction
 // settings and optional path whitelist patterns.
 func buildFs(workspace string, restrict bool, patterns []*regexp.Regexp) fileSystem {
@@ -1282,6 +1645,21 @@ func buildFs(workspace string, restrict bool, patterns []*regexp.Regexp) fileSys
 	return sandbox
 }
 
+// statPath returns the FileInfo for path through sysFs, which has no Stat of
+// its own.
+func statPath(sysFs fileSystem, path string) (fs.FileInfo, error) {
+	f, err := sysFs.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat %s: %w", path, err)
+	}
+	return info, nil
+}
+
 func normalizeRootRelPath(relPath string) string {
 	return normalizeRootRelPathForSeparator(relPath, os.PathSeparator)
 }
@@ -370,6 +370,49 @@ func TestFilesystemTool_WriteFile_NewFileNoOverwriteFlag(t *testing.T) {
 	assert.Equal(t, "brand new", string(data))
 }
 
+// TestFilesystemTool_WriteFile_PreviewDoesNotWrite verifies that preview=true
+// returns a diff against the current contents and leaves the file untouched.
+func TestFilesystemTool_WriteFile_PreviewDoesNotWrite(t *testing.T) {
+	tmpDir := t.TempDir()
+	testFile := filepath.Join(tmpDir, "existing.txt")
+	os.WriteFile(testFile, []byte("original\n"), 0o644)
+
+	tool := NewWriteFileTool(tmpDir, true)
+	result := tool.Execute(context.Background(), map[string]any{
+		"path":      "existing.txt",
+		"content":   "replaced\n",
+		"overwrite": true,
+		"preview":   true,
+	})
+
+	assert.False(t, result.IsError, "expected preview to succeed, got: %s", result.ForLLM)
+	assert.Contains(t, result.ForLLM, "nothing was written")
+	assert.Contains(t, result.ForLLM, "original")
+	assert.Contains(t, result.ForLLM, "replaced")
+
+	data, err := os.ReadFile(testFile)
+	assert.NoError(t, err)
+	assert.Equal(t, "original\n", string(data))
+
+	// A preview still reports the overwrite guard, and a new file diffs
+	// against empty content.
+	result = tool.Execute(context.Background(), map[string]any{
+		"path":    "existing.txt",
+		"content": "replaced\n",
+		"preview": true,
+	})
+	assert.True(t, result.IsError)
+
+	result = tool.Execute(context.Background(), map[string]any{
+		"path":    "new.txt",
+		"content": "fresh\n",
+		"preview": true,
+	})
+	assert.False(t, result.IsError, "expected preview of a new file to succeed, got: %s", result.ForLLM)
+	_, err = os.Stat(filepath.Join(tmpDir, "new.txt"))
+	assert.True(t, os.IsNotExist(err))
+}
+
 // TestFilesystemTool_WriteFile_OverwriteFalseExplicitBlocked verifies that
 // explicitly passing overwrite=false also blocks overwriting.
 func TestFilesystemTool_WriteFile_OverwriteFalseExplicitBlocked(t *testing.T) {
@@ -1218,6 +1261,46 @@ func TestReadFileLinesTool_BinaryFileRejected(t *testing.T) {
 	}
 }
 
+func TestReadFileTool_BinaryFilePreview(t *testing.T) {
+	tmpDir := t.TempDir()
+	data := append([]byte{0x00, 0x01, 0x02, 0xff}, []byte("HELLO_WORLD\x00abc\x00config.version")...)
+	if err := os.WriteFile(filepath.Join(tmpDir, "blob.bin"), data, 0o644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	tool := NewReadFileTool(tmpDir, true, MaxReadFileSize)
+	result := tool.Execute(context.Background(), map[string]any{"path": "blob.bin"})
+	assert.False(t, result.IsError, "expected binary preview, got: %s", result.ForLLM)
+	assert.Contains(t, result.ForLLM, "| binary: application/octet-stream]")
+	assert.Contains(t, result.ForLLM, "00000000  00 01 02 ff 48 45 4c 4c")
+	assert.Contains(t, result.ForLLM, "|....HELLO_WORLD.|")
+	assert.Contains(t, result.ForLLM, "strings:\nHELLO_WORLD\nconfig.version\n")
+	assert.NotContains(t, result.ForLLM, "\nabc\n")
+	assert.Contains(t, result.ForLLM, "base64=true")
+
+	result = tool.Execute(context.Background(), map[string]any{"path": "blob.bin", "offset": 4})
+	assert.Contains(t, result.ForLLM, "00000004  48 45 4c 4c")
+}
+
+func TestReadFileTool_Base64Mode(t *testing.T) {
+	tmpDir := t.TempDir()
+	data := []byte{0x00, 0x01, 0x02, 0xff, 'a', 'b'}
+	if err := os.WriteFile(filepath.Join(tmpDir, "blob.bin"), data, 0o644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	tool := NewReadFileTool(tmpDir, true, MaxReadFileSize)
+	result := tool.Execute(context.Background(), map[string]any{
+		"path":   "blob.bin",
+		"base64": true,
+		"offset": 1,
+		"length": 3,
+	})
+	assert.False(t, result.IsError, "expected base64 read, got: %s", result.ForLLM)
+	assert.Contains(t, result.ForLLM, "read: bytes 1-3 | encoding: base64]")
+	assert.True(t, strings.HasSuffix(result.ForLLM, "\n\nAQL/"), "unexpected output: %s", result.ForLLM)
+}
+
 func TestReadFileLinesTool_TruncatesSingleLongLineAtByteBudget(t *testing.T) {
 	tmpDir := t.TempDir()
 	testFile := filepath.Join(tmpDir, "long_line.txt")
@@ -1311,3 +1394,61 @@ func TestReadFileLinesTool_ExactByteBudgetBoundaryIncludesPrefix(t *testing.T) {
 		t.Fatalf("expected continuation at line 2, got: %s", result.ForLLM)
 	}
 }
+
+func TestReadFileLinesTool_EndLineAndTotalLines(t *testing.T) {
+	tmpDir := t.TempDir()
+	testFile := filepath.Join(tmpDir, "range.log")
+
+	err := os.WriteFile(testFile, []byte("a\nb\nc\nd\ne\nf"), 0o644)
+	if err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	tool := NewReadFileLinesTool(tmpDir, false, MaxReadFileSize)
+	result := tool.Execute(context.Background(), map[string]any{
+		"path":       testFile,
+		"start_line": 2,
+		"end_line":   4,
+	})
+	if result.IsError {
+		t.Fatalf("Execute() error = %s", result.ForLLM)
+	}
+	if !strings.Contains(result.ForLLM, "2|b\n3|c\n4|d\n") || strings.Contains(result.ForLLM, "5|") {
+		t.Fatalf("expected lines 2-4 only, got: %s", result.ForLLM)
+	}
+	if !strings.Contains(result.ForLLM, "| total_lines: 6]") {
+		t.Fatalf("expected total line count of the whole file, got: %s", result.ForLLM)
+	}
+
+	result = tool.Execute(context.Background(), map[string]any{"path": testFile, "start_line": 5})
+	if !strings.Contains(result.ForLLM, "| total_lines: 6]") {
+		t.Fatalf("expected total line count when reading to EOF, got: %s", result.ForLLM)
+	}
+}
+
+func TestReadFileLinesTool_EndLineValidation(t *testing.T) {
+	tmpDir := t.TempDir()
+	testFile := filepath.Join(tmpDir, "range.txt")
+	if err := os.WriteFile(testFile, []byte("a\nb\n"), 0o644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	tool := NewReadFileLinesTool(tmpDir, false, MaxReadFileSize)
+	result := tool.Execute(context.Background(), map[string]any{
+		"path":       testFile,
+		"start_line": 3,
+		"end_line":   2,
+	})
+	if !result.IsError || !strings.Contains(result.ForLLM, "end_line must be >= start_line") {
+		t.Fatalf("expected end_line validation error, got: %s", result.ForLLM)
+	}
+
+	result = tool.Execute(context.Background(), map[string]any{
+		"path":      testFile,
+		"end_line":  2,
+		"max_lines": 1,
+	})
+	if !result.IsError || !strings.Contains(result.ForLLM, "cannot be combined") {
+		t.Fatalf("expected end_line/max_lines conflict error, got: %s", result.ForLLM)
+	}
+}
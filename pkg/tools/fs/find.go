@@ -0,0 +1,256 @@
+package fstools
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const defaultFindMaxResults = 200
+
+// FindFilesTool locates files under a directory by glob pattern, optionally
+// filtered by modification time and size.
+type FindFilesTool struct {
+	fs fileSystem
+}
+
+// NewFindFilesTool creates a new FindFilesTool with optional directory restriction.
+func NewFindFilesTool(workspace string, restrict bool, allowPaths ...[]*regexp.Regexp) *FindFilesTool {
+	var patterns []*regexp.Regexp
+	if len(allowPaths) > 0 {
+		patterns = allowPaths[0]
+	}
+	return &FindFilesTool{fs: buildFs(workspace, restrict, patterns)}
+}
+
+func (t *FindFilesTool) Name() string {
+	return "find_files"
+}
+
+func (t *FindFilesTool) Description() string {
+	return "Find files by glob pattern in a directory tree and return their paths relative to the search directory. `**` matches any number of directories (e.g. `**/*.go`, `src/**/test_*.py`); a pattern without `/` matches file names at any depth (e.g. `*.csv`). Optional filters: modified_since, min_size, max_size."
+}
+
+func (t *FindFilesTool) Parameters() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"pattern": map[string]any{
+				"type":        "string",
+				"description": "Glob pattern to match, using / as separator",
+			},
+			"path": map[string]any{
+				"type":        "string",
+				"description": "Directory to search",
+				"default":     ".",
+			},
+			"modified_since": map[string]any{
+				"type":        "string",
+				"description": "Only return files modified after this time: an RFC 3339 timestamp, a YYYY-MM-DD date, or a duration such as 24h meaning \"within the last 24 hours\"",
+			},
+			"min_size": map[string]any{
+				"type":        "integer",
+				"description": "Only return files at least this many bytes large",
+			},
+			"max_size": map[string]any{
+				"type":        "integer",
+				"description": "Only return files at most this many bytes large",
+			},
+			"max_results": map[string]any{
+				"type":        "integer",
+				"description": "Maximum number of paths to return",
+				"default":     defaultFindMaxResults,
+			},
+		},
+		"required": []string{"pattern"},
+	}
+}
+
+type findFilter struct {
+	pattern  string
+	since    time.Time
+	minSize  int64
+	maxSize  int64
+	maxItems int64
+}
+
+func (f *findFilter) matches(rel string, entry os.DirEntry) bool {
+	if !matchGlob(f.pattern, rel) {
+		return false
+	}
+	info, err := entry.Info()
+	if err != nil {
+		return false
+	}
+	if !f.since.IsZero() && !info.ModTime().After(f.since) {
+		return false
+	}
+	return info.Size() >= f.minSize && (f.maxSize < 0 || info.Size() <= f.maxSize)
+}
+
+func (t *FindFilesTool) Execute(ctx context.Context, args map[string]any) *ToolResult {
+	pattern, ok := args["pattern"].(string)
+	if !ok || strings.TrimSpace(pattern) == "" {
+		return ErrorResult("pattern is required")
+	}
+	pattern = strings.TrimPrefix(filepath.ToSlash(pattern), "./")
+	if _, err := path.Match(strings.ReplaceAll(pattern, "**", "*"), ""); err != nil {
+		return ErrorResult(fmt.Sprintf("invalid pattern %q: %v", pattern, err))
+	}
+
+	root, ok := args["path"].(string)
+	if !ok || root == "" {
+		root = "."
+	}
+
+	filter := findFilter{pattern: pattern, maxSize: -1}
+	if raw, ok := args["modified_since"].(string); ok && raw != "" {
+		since, err := parseModifiedSince(raw, time.Now())
+		if err != nil {
+			return ErrorResult(err.Error())
+		}
+		filter.since = since
+	}
+	var err error
+	if filter.minSize, err = getInt64Arg(args, "min_size", 0); err != nil {
+		return ErrorResult(err.Error())
+	}
+	if filter.maxSize, err = getInt64Arg(args, "max_size", -1); err != nil {
+		return ErrorResult(err.Error())
+	}
+	if filter.maxItems, err = getInt64Arg(args, "max_results", defaultFindMaxResults); err != nil {
+		return ErrorResult(err.Error())
+	}
+	if filter.maxItems <= 0 {
+		return ErrorResult("max_results must be > 0")
+	}
+
+	var matches []string
+	truncated, err := walkFiles(ctx, t.fs, root, nil, func(rel string, entry os.DirEntry) bool {
+		if !filter.matches(rel, entry) {
+			return true
+		}
+		if int64(len(matches)) >= filter.maxItems {
+			return false
+		}
+		matches = append(matches, rel)
+		return true
+	})
+	if err != nil {
+		return ErrorResult(err.Error())
+	}
+
+	if len(matches) == 0 {
+		return NewToolResult(fmt.Sprintf("No files matching %q found in %s", pattern, root))
+	}
+	var result strings.Builder
+	fmt.Fprintf(&result, "Found %d file(s) matching %q in %s", len(matches), pattern, root)
+	if truncated {
+		fmt.Fprintf(&result, " (stopped at max_results=%d; narrow the pattern or path to see more)", filter.maxItems)
+	}
+	result.WriteString(":\n")
+	for _, match := range matches {
+		result.WriteString(match + "\n")
+	}
+	return NewToolResult(result.String())
+}
+
+// walkFiles visits every non-directory entry below root in lexical order,
+// passing its slash-separated path relative to root. Directories for which
+// skipDir returns true are not entered, and unreadable subdirectories are
+// skipped. Returning false from visit stops the walk; walkFiles then reports
+// true.
+func walkFiles(
+	ctx context.Context,
+	sysFs fileSystem,
+	root string,
+	skipDir func(name string) bool,
+	visit func(rel string, entry os.DirEntry) bool,
+) (bool, error) {
+	var walk func(rel string) (bool, error)
+	walk = func(rel string) (bool, error) {
+		if err := ctx.Err(); err != nil {
+			return false, fmt.Errorf("search cancelled: %w", err)
+		}
+		entries, err := sysFs.ReadDir(filepath.Join(root, filepath.FromSlash(rel)))
+		if err != nil {
+			if rel == "" {
+				return false, fmt.Errorf("failed to read directory: %w", err)
+			}
+			return false, nil
+		}
+		for _, entry := range entries {
+			entryRel := path.Join(rel, entry.Name())
+			if entry.IsDir() {
+				if skipDir != nil && skipDir(entry.Name()) {
+					continue
+				}
+				stopped, err := walk(entryRel)
+				if err != nil || stopped {
+					return stopped, err
+				}
+				continue
+			}
+			if !visit(entryRel, entry) {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+	return walk("")
+}
+
+// matchGlob reports whether a slash-separated relative path matches pattern.
+// `**` matches zero or more path segments; other segments use path.Match
+// syntax. Patterns without a separator are matched against the base name.
+func matchGlob(pattern, rel string) bool {
+	if !strings.Contains(pattern, "/") {
+		ok, _ := path.Match(pattern, path.Base(rel))
+		return ok
+	}
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(rel, "/"))
+}
+
+func matchSegments(pattern, parts []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(parts); i++ {
+				if matchSegments(pattern[1:], parts[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(parts) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], parts[0]); !ok {
+			return false
+		}
+		pattern, parts = pattern[1:], parts[1:]
+	}
+	return len(parts) == 0
+}
+
+// parseModifiedSince accepts an RFC 3339 timestamp, a YYYY-MM-DD date, or a
+// Go duration that is interpreted as "this long before now".
+func parseModifiedSince(raw string, now time.Time) (time.Time, error) {
+	if ts, err := time.Parse(time.RFC3339, raw); err == nil {
+		return ts, nil
+	}
+	if ts, err := time.ParseInLocation(time.DateOnly, raw, time.Local); err == nil {
+		return ts, nil
+	}
+	if d, err := time.ParseDuration(raw); err == nil && d > 0 {
+		return now.Add(-d), nil
+	}
+	return time.Time{}, fmt.Errorf(
+		"invalid modified_since %q: use an RFC 3339 timestamp, a YYYY-MM-DD date, or a duration such as 24h",
+		raw,
+	)
+}
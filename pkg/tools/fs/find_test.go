@@ -0,0 +1,139 @@
+package fstools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFindFixture(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		full := filepath.Join(root, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(full), 0o755))
+		require.NoError(t, os.WriteFile(full, []byte(content), 0o644))
+	}
+}
+
+func findResultPaths(output string) []string {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if len(lines) <= 1 {
+		return nil
+	}
+	return lines[1:]
+}
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{"*.go", "main.go", true},
+		{"*.go", "pkg/tools/fs.go", true},
+		{"*.go", "main.go.bak", false},
+		{"**/*.go", "main.go", true},
+		{"**/*.go", "pkg/tools/fs.go", true},
+		{"pkg/*.go", "pkg/tools/fs.go", false},
+		{"pkg/**/fs.go", "pkg/fs.go", true},
+		{"pkg/**/fs.go", "pkg/tools/fs/fs.go", true},
+		{"src/**", "src/a/b.txt", true},
+		{"src/**", "docs/a.txt", false},
+		{"data/report-?.csv", "data/report-1.csv", true},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, matchGlob(tt.pattern, tt.path), "matchGlob(%q, %q)", tt.pattern, tt.path)
+	}
+}
+
+func TestFindFilesTool_Restricted_MatchesNestedFiles(t *testing.T) {
+	workspace := t.TempDir()
+	writeFindFixture(t, workspace, map[string]string{
+		"main.go":             "package main",
+		"pkg/util/strings.go": "package util",
+		"pkg/util/notes.md":   "notes",
+		"data/sales.csv":      "a,b",
+	})
+
+	tool := NewFindFilesTool(workspace, true)
+	result := tool.Execute(context.Background(), map[string]any{"pattern": "**/*.go"})
+
+	assert.False(t, result.IsError, "Expected success, got: %s", result.ForLLM)
+	assert.Equal(t, []string{"main.go", "pkg/util/strings.go"}, findResultPaths(result.ForLLM))
+
+	result = tool.Execute(context.Background(), map[string]any{"pattern": "*.csv", "path": "data"})
+	assert.Equal(t, []string{"sales.csv"}, findResultPaths(result.ForLLM))
+}
+
+func TestFindFilesTool_SizeAndTimeFilters(t *testing.T) {
+	workspace := t.TempDir()
+	writeFindFixture(t, workspace, map[string]string{
+		"small.log": "x",
+		"large.log": strings.Repeat("x", 100),
+		"old.log":   strings.Repeat("x", 100),
+	})
+	old := time.Now().Add(-48 * time.Hour)
+	require.NoError(t, os.Chtimes(filepath.Join(workspace, "old.log"), old, old))
+
+	tool := NewFindFilesTool(workspace, true)
+	result := tool.Execute(context.Background(), map[string]any{
+		"pattern":        "*.log",
+		"min_size":       float64(10),
+		"modified_since": "24h",
+	})
+
+	assert.False(t, result.IsError, "Expected success, got: %s", result.ForLLM)
+	assert.Equal(t, []string{"large.log"}, findResultPaths(result.ForLLM))
+
+	result = tool.Execute(context.Background(), map[string]any{"pattern": "*.log", "max_size": float64(10)})
+	assert.Equal(t, []string{"small.log"}, findResultPaths(result.ForLLM))
+}
+
+func TestFindFilesTool_MaxResults(t *testing.T) {
+	workspace := t.TempDir()
+	writeFindFixture(t, workspace, map[string]string{"a.txt": "", "b.txt": "", "c.txt": ""})
+
+	tool := NewFindFilesTool(workspace, true)
+	result := tool.Execute(context.Background(), map[string]any{"pattern": "*.txt", "max_results": float64(2)})
+
+	assert.False(t, result.IsError)
+	assert.Contains(t, result.ForLLM, "stopped at max_results=2")
+	assert.Len(t, findResultPaths(result.ForLLM), 2)
+}
+
+func TestFindFilesTool_NoMatches(t *testing.T) {
+	tool := NewFindFilesTool(t.TempDir(), true)
+	result := tool.Execute(context.Background(), map[string]any{"pattern": "*.xyz"})
+
+	assert.False(t, result.IsError)
+	assert.Contains(t, result.ForLLM, "No files matching")
+}
+
+func TestFindFilesTool_InvalidArguments(t *testing.T) {
+	tool := NewFindFilesTool(t.TempDir(), true)
+
+	result := tool.Execute(context.Background(), map[string]any{})
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.ForLLM, "pattern is required")
+
+	result = tool.Execute(context.Background(), map[string]any{"pattern": "[a-"})
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.ForLLM, "invalid pattern")
+
+	result = tool.Execute(context.Background(), map[string]any{"pattern": "*", "modified_since": "yesterday"})
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.ForLLM, "invalid modified_since")
+}
+
+func TestFindFilesTool_Restricted_OutsideWorkspace(t *testing.T) {
+	tool := NewFindFilesTool(t.TempDir(), true)
+	result := tool.Execute(context.Background(), map[string]any{"pattern": "*", "path": t.TempDir()})
+
+	assert.True(t, result.IsError)
+}
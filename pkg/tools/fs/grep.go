@@ -0,0 +1,248 @@
+package fstools
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+const (
+	defaultGrepMaxResults = 100
+	maxGrepContextLines   = 10
+	// maxGrepFileSize skips files too large to be source or config files.
+	maxGrepFileSize = 1024 * 1024
+	// maxGrepLineLength truncates matched lines such as minified assets.
+	maxGrepLineLength = 300
+)
+
+// grepSkippedDirs are never searched: version-control directories, the
+// undo_file backup store, unfinished write_session parts and snapshot copies,
+// which would duplicate every match.
+var grepSkippedDirs = map[string]bool{
+	".git": true, ".hg": true, ".svn": true, "file_backups": true, "write_sessions": true,
+	"snapshots": true,
+}
+
+// GrepTool searches file contents below a directory for a regular expression
+// or literal string and returns matching lines with optional context.
+type GrepTool struct {
+	fs fileSystem
+}
+
+// NewGrepTool creates a new GrepTool with optional directory restriction.
+func NewGrepTool(workspace string, restrict bool, allowPaths ...[]*regexp.Regexp) *GrepTool {
+	var patterns []*regexp.Regexp
+	if len(allowPaths) > 0 {
+		patterns = allowPaths[0]
+	}
+	return &GrepTool{fs: buildFs(workspace, restrict, patterns)}
+}
+
+func (t *GrepTool) Name() string {
+	return "grep"
+}
+
+func (t *GrepTool) Description() string {
+	return "Search inside files for a regular expression (or a literal string with literal=true) and return matches as `path:line:text`, with optional surrounding context lines shown as `path-line-text`. Binary files, files over 1MB and VCS directories are skipped. Use include to limit the search to file names matching a glob such as `*.go` or `src/**/*.ts`."
+}
+
+func (t *GrepTool) Parameters() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"pattern": map[string]any{
+				"type":        "string",
+				"description": "Regular expression (RE2 syntax) or literal text to search for",
+			},
+			"path": map[string]any{
+				"type":        "string",
+				"description": "File or directory to search",
+				"default":     ".",
+			},
+			"literal": map[string]any{
+				"type":        "boolean",
+				"description": "Treat pattern as a literal string instead of a regular expression",
+				"default":     false,
+			},
+			"ignore_case": map[string]any{
+				"type":        "boolean",
+				"description": "Match case-insensitively",
+				"default":     false,
+			},
+			"include": map[string]any{
+				"type":        "string",
+				"description": "Only search files matching this glob pattern",
+			},
+			"context": map[string]any{
+				"type":        "integer",
+				"description": fmt.Sprintf("Lines of context to show before and after each match (max %d)", maxGrepContextLines),
+				"default":     0,
+			},
+			"max_results": map[string]any{
+				"type":        "integer",
+				"description": "Maximum number of matching lines to return",
+				"default":     defaultGrepMaxResults,
+			},
+		},
+		"required": []string{"pattern"},
+	}
+}
+
+type grepSearch struct {
+	re         *regexp.Regexp
+	context    int
+	maxResults int
+	hits       int
+	files      int
+	out        strings.Builder
+}
+
+func (t *GrepTool) Execute(ctx context.Context, args map[string]any) *ToolResult {
+	pattern, ok := args["pattern"].(string)
+	if !ok || pattern == "" {
+		return ErrorResult("pattern is required")
+	}
+	root, ok := args["path"].(string)
+	if !ok || root == "" {
+		root = "."
+	}
+	literal, _ := args["literal"].(bool)
+	ignoreCase, _ := args["ignore_case"].(bool)
+	include, _ := args["include"].(string)
+	include = strings.TrimPrefix(filepath.ToSlash(include), "./")
+
+	expr := pattern
+	if literal {
+		expr = regexp.QuoteMeta(pattern)
+	}
+	if ignoreCase {
+		expr = "(?i)" + expr
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return ErrorResult(fmt.Sprintf(
+			"invalid regular expression %q: %v (set literal=true to search for it as text)",
+			pattern, err,
+		))
+	}
+
+	contextLines, err := getInt64Arg(args, "context", 0)
+	if err != nil {
+		return ErrorResult(err.Error())
+	}
+	if contextLines < 0 {
+		return ErrorResult("context must be >= 0")
+	}
+	maxResults, err := getInt64Arg(args, "max_results", defaultGrepMaxResults)
+	if err != nil {
+		return ErrorResult(err.Error())
+	}
+	if maxResults <= 0 {
+		return ErrorResult("max_results must be > 0")
+	}
+
+	search := &grepSearch{
+		re:         re,
+		context:    int(min(contextLines, maxGrepContextLines)),
+		maxResults: int(maxResults),
+	}
+
+	var truncated bool
+	if info, statErr := statPath(t.fs, root); statErr == nil && !info.IsDir() {
+		truncated = !t.searchFile(search, root, filepath.ToSlash(root))
+	} else {
+		truncated, err = walkFiles(ctx, t.fs, root, func(name string) bool {
+			return grepSkippedDirs[name]
+		}, func(rel string, entry os.DirEntry) bool {
+			if include != "" && !matchGlob(include, rel) {
+				return true
+			}
+			if info, infoErr := entry.Info(); infoErr != nil || info.Size() > maxGrepFileSize {
+				return true
+			}
+			return t.searchFile(search, filepath.Join(root, filepath.FromSlash(rel)), rel)
+		})
+		if err != nil {
+			return ErrorResult(err.Error())
+		}
+	}
+
+	if search.hits == 0 {
+		return NewToolResult(fmt.Sprintf("No matches for %q in %s", pattern, root))
+	}
+	header := fmt.Sprintf("Found %d match(es) in %d file(s)", search.hits, search.files)
+	if truncated {
+		header += fmt.Sprintf(
+			" (stopped at max_results=%d; narrow the pattern, path or include to see more)",
+			maxResults,
+		)
+	}
+	return NewToolResult(header + ":\n" + search.out.String())
+}
+
+// searchFile appends the matches in one file to the search output. It
+// returns false once max_results has been reached and more matches exist.
+func (t *GrepTool) searchFile(search *grepSearch, fullPath, displayPath string) bool {
+	data, err := t.fs.ReadFile(fullPath)
+	if err != nil || isBinaryReadFileData(data) {
+		return true
+	}
+
+	var lines []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), maxGrepFileSize+1)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+
+	lastPrinted := -1
+	fileHits := 0
+	for i, line := range lines {
+		if !search.re.MatchString(line) {
+			continue
+		}
+		if search.hits >= search.maxResults {
+			if fileHits > 0 {
+				search.files++
+			}
+			return false
+		}
+		start := max(i-search.context, lastPrinted+1)
+		if search.context > 0 && lastPrinted >= 0 && start > lastPrinted+1 {
+			search.out.WriteString("--\n")
+		}
+		for j := start; j < i; j++ {
+			writeGrepLine(&search.out, displayPath, j+1, '-', lines[j])
+		}
+		writeGrepLine(&search.out, displayPath, i+1, ':', line)
+		lastPrinted = i
+		for j := i + 1; j <= min(i+search.context, len(lines)-1); j++ {
+			if search.re.MatchString(lines[j]) {
+				break
+			}
+			writeGrepLine(&search.out, displayPath, j+1, '-', lines[j])
+			lastPrinted = j
+		}
+		search.hits++
+		fileHits++
+	}
+	if fileHits > 0 {
+		search.files++
+		if search.context > 0 {
+			search.out.WriteString("--\n")
+		}
+	}
+	return true
+}
+
+func writeGrepLine(out *strings.Builder, displayPath string, lineNumber int, sep byte, text string) {
+	if len(text) > maxGrepLineLength {
+		text = text[:maxGrepLineLength] + "..."
+	}
+	fmt.Fprintf(out, "%s%c%d%c%s\n", displayPath, sep, lineNumber, sep, text)
+}
@@ -0,0 +1,114 @@
+package fstools
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGrepTool_Restricted_FindsRegexMatches(t *testing.T) {
+	workspace := t.TempDir()
+	writeFindFixture(t, workspace, map[string]string{
+		"main.go":           "package main\n\nfunc main() {\n\trun()\n}\n",
+		"pkg/run.go":        "package pkg\n\nfunc run() error {\n\treturn nil\n}\n",
+		"docs/readme.md":    "call run() to start\n",
+		".git/objects/blob": "func run()\n",
+	})
+
+	tool := NewGrepTool(workspace, true)
+	result := tool.Execute(context.Background(), map[string]any{"pattern": `func \w+\(`})
+
+	assert.False(t, result.IsError, "Expected success, got: %s", result.ForLLM)
+	assert.Contains(t, result.ForLLM, "Found 2 match(es) in 2 file(s)")
+	assert.Contains(t, result.ForLLM, "main.go:3:func main() {")
+	assert.Contains(t, result.ForLLM, "pkg/run.go:3:func run() error {")
+	assert.NotContains(t, result.ForLLM, ".git")
+}
+
+func TestGrepTool_LiteralIgnoreCaseAndInclude(t *testing.T) {
+	workspace := t.TempDir()
+	writeFindFixture(t, workspace, map[string]string{
+		"a.go":   "x := Foo(1)\n",
+		"b.txt":  "foo(1) here\n",
+		"c.go":   "bar()\n",
+		"d/e.go": "FOO(1)\n",
+	})
+
+	tool := NewGrepTool(workspace, true)
+	result := tool.Execute(context.Background(), map[string]any{
+		"pattern":     "foo(1)",
+		"literal":     true,
+		"ignore_case": true,
+		"include":     "*.go",
+	})
+
+	assert.False(t, result.IsError, "Expected success, got: %s", result.ForLLM)
+	assert.Contains(t, result.ForLLM, "a.go:1:x := Foo(1)")
+	assert.Contains(t, result.ForLLM, "d/e.go:1:FOO(1)")
+	assert.NotContains(t, result.ForLLM, "b.txt")
+}
+
+func TestGrepTool_ContextLines(t *testing.T) {
+	workspace := t.TempDir()
+	writeFindFixture(t, workspace, map[string]string{
+		"app.conf": "one\ntwo\nport = 8080\nfour\nfive\nsix\nseven\nport = 9090\nnine\n",
+	})
+
+	tool := NewGrepTool(workspace, true)
+	result := tool.Execute(context.Background(), map[string]any{
+		"pattern": "port",
+		"path":    "app.conf",
+		"context": float64(1),
+	})
+
+	assert.False(t, result.IsError, "Expected success, got: %s", result.ForLLM)
+	want := strings.Join([]string{
+		"app.conf-2-two",
+		"app.conf:3:port = 8080",
+		"app.conf-4-four",
+		"--",
+		"app.conf-7-seven",
+		"app.conf:8:port = 9090",
+		"app.conf-9-nine",
+		"--",
+	}, "\n")
+	assert.Contains(t, result.ForLLM, want)
+}
+
+func TestGrepTool_MaxResultsAndBinaryFiles(t *testing.T) {
+	workspace := t.TempDir()
+	writeFindFixture(t, workspace, map[string]string{
+		"log.txt":   "error 1\nerror 2\nerror 3\n",
+		"image.bin": "error\x00\x01\x02",
+	})
+
+	tool := NewGrepTool(workspace, true)
+	result := tool.Execute(context.Background(), map[string]any{"pattern": "error", "max_results": float64(2)})
+
+	assert.False(t, result.IsError)
+	assert.Contains(t, result.ForLLM, "Found 2 match(es) in 1 file(s)")
+	assert.Contains(t, result.ForLLM, "stopped at max_results=2")
+	assert.NotContains(t, result.ForLLM, "image.bin")
+	assert.NotContains(t, result.ForLLM, "error 3")
+}
+
+func TestGrepTool_NoMatchesAndInvalidPattern(t *testing.T) {
+	tool := NewGrepTool(t.TempDir(), true)
+
+	result := tool.Execute(context.Background(), map[string]any{"pattern": "nothing"})
+	assert.False(t, result.IsError)
+	assert.Contains(t, result.ForLLM, "No matches")
+
+	result = tool.Execute(context.Background(), map[string]any{"pattern": "func("})
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.ForLLM, "literal=true")
+}
+
+func TestGrepTool_Restricted_OutsideWorkspace(t *testing.T) {
+	tool := NewGrepTool(t.TempDir(), true)
+	result := tool.Execute(context.Background(), map[string]any{"pattern": "x", "path": t.TempDir()})
+
+	assert.True(t, result.IsError)
+}
@@ -0,0 +1,85 @@
+package fstools
+
+import (
+	"bytes"
+	"image"
+	"image/draw"
+	_ "image/gif" // register the GIF decoder for image.Decode
+	"image/jpeg"
+	"image/png"
+)
+
+// defaultImageMaxDimension is the longest side, in pixels, load_image sends to
+// the model. Vision models downscale larger images themselves, so sending the
+// original only costs tokens and upload time.
+const defaultImageMaxDimension = 1568
+
+// minImageMaxDimension keeps a requested max_dimension large enough for the
+// image to stay legible.
+const minImageMaxDimension = 64
+
+// fitDimensions scales w×h down so that neither side exceeds maxDim, keeping
+// the aspect ratio. Images that already fit are returned unchanged.
+func fitDimensions(w, h, maxDim int) (int, int) {
+	if w <= maxDim && h <= maxDim {
+		return w, h
+	}
+	if w >= h {
+		return maxDim, max(1, (h*maxDim+w/2)/w)
+	}
+	return max(1, (w*maxDim+h/2)/h), maxDim
+}
+
+// downscaleImage resizes src to w×h by averaging the source pixels that fall
+// under each destination pixel. It is only meant for shrinking.
+func downscaleImage(src image.Image, w, h int) *image.RGBA {
+	bounds := src.Bounds()
+	rgba, ok := src.(*image.RGBA)
+	if !ok || bounds.Min != (image.Point{}) {
+		rgba = image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+		draw.Draw(rgba, rgba.Bounds(), src, bounds.Min, draw.Src)
+	}
+	sw, sh := rgba.Bounds().Dx(), rgba.Bounds().Dy()
+
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := range h {
+		y0, y1 := y*sh/h, max((y+1)*sh/h, y*sh/h+1)
+		for x := range w {
+			x0, x1 := x*sw/w, max((x+1)*sw/w, x*sw/w+1)
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				row := rgba.Pix[sy*rgba.Stride:]
+				for sx := x0; sx < x1; sx++ {
+					p := row[sx*4 : sx*4+4]
+					r += uint64(p[0])
+					g += uint64(p[1])
+					b += uint64(p[2])
+					a += uint64(p[3])
+					n++
+				}
+			}
+			o := dst.PixOffset(x, y)
+			dst.Pix[o] = uint8(r / n)
+			dst.Pix[o+1] = uint8(g / n)
+			dst.Pix[o+2] = uint8(b / n)
+			dst.Pix[o+3] = uint8(a / n)
+		}
+	}
+	return dst
+}
+
+// encodeImage encodes img as JPEG, or as PNG when it has transparency that
+// JPEG would lose. It returns the encoded bytes and the file extension.
+func encodeImage(img *image.RGBA) ([]byte, string, error) {
+	var buf bytes.Buffer
+	if img.Opaque() {
+		if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 85}); err != nil {
+			return nil, "", err
+		}
+		return buf.Bytes(), ".jpg", nil
+	}
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, "", err
+	}
+	return buf.Bytes(), ".png", nil
+}
@@ -2,7 +2,10 @@ package fstools
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"image"
+	"mime"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -18,6 +21,11 @@ import (
 // vision on local files — the same pipeline used when a user sends an image
 // through a chat channel.
 //
+// JPEG, PNG and GIF files are validated before they are registered, and images
+// larger than max_dimension are downscaled into a temporary copy so a phone
+// photo does not cost the model thousands of tokens. WebP and BMP files are
+// passed through as they are.
+//
 // This is intentionally different from SendFileTool:
 //   - SendFileTool  → MediaResult + WithResponseHandled() → sends file to user, ends turn
 //   - LoadImageTool → plain ToolResult with media:// in ForLLM  → LLM sees the image next turn
@@ -27,6 +35,7 @@ type LoadImageTool struct {
 	maxFileSize int
 	mediaStore  media.MediaStore
 	allowPaths  []*regexp.Regexp
+	policy      *FilePolicy
 
 	defaultChannel string
 	defaultChatID  string
@@ -60,6 +69,8 @@ func (t *LoadImageTool) Name() string { return "load_image" }
 func (t *LoadImageTool) Description() string {
 	return "Load a local image file so you can analyze its contents with vision. " +
 		"Supported formats: JPEG, PNG, GIF, WebP, BMP. " +
+		fmt.Sprintf("JPEG, PNG and GIF images larger than %d pixels on a side are downscaled first. ",
+			defaultImageMaxDimension) +
 		"After calling this tool, describe or analyze the image in your next response."
 }
 
@@ -71,6 +82,14 @@ func (t *LoadImageTool) Parameters() map[string]any {
 				"type":        "string",
 				"description": "Path to the local image file. Relative paths are resolved from workspace.",
 			},
+			"max_dimension": map[string]any{
+				"type": "integer",
+				"description": fmt.Sprintf(
+					"Longest side in pixels to downscale to (default %d, minimum %d). "+
+						"Raise it only when fine detail such as small text matters.",
+					defaultImageMaxDimension, minImageMaxDimension,
+				),
+			},
 		},
 		"required": []string{"path"},
 	}
@@ -108,10 +127,21 @@ func (t *LoadImageTool) Execute(ctx context.Context, args map[string]any) *ToolR
 		return ErrorResult("media store not configured")
 	}
 
+	maxDim, err := getInt64Arg(args, "max_dimension", defaultImageMaxDimension)
+	if err != nil {
+		return ErrorResult(err.Error())
+	}
+	if maxDim < minImageMaxDimension {
+		return ErrorResult(fmt.Sprintf("max_dimension must be at least %d", minImageMaxDimension))
+	}
+
 	resolved, err := validatePathWithAllowPaths(path, t.workspace, t.restrict, t.allowPaths)
 	if err != nil {
 		return ErrorResult(fmt.Sprintf("invalid path: %v", err))
 	}
+	if err := t.policy.checkAccess(resolved, false); err != nil {
+		return ErrorResult(err.Error())
+	}
 
 	info, err := os.Stat(resolved)
 	if err != nil {
@@ -134,23 +164,48 @@ func (t *LoadImageTool) Execute(ctx context.Context, args map[string]any) *ToolR
 		))
 	}
 
+	imgCfg, decodable, err := probeImageFile(resolved)
+	if err != nil {
+		return ErrorResult(fmt.Sprintf("file is not a valid image: %v", err))
+	}
+
 	filename := filepath.Base(resolved)
 	scope := fmt.Sprintf("tool:load_image:%s:%s", channel, chatID)
-
-	ref, err := t.mediaStore.Store(resolved, media.MediaMeta{
+	meta := media.MediaMeta{
 		Filename:      filename,
 		ContentType:   mediaType,
 		Source:        "tool:load_image",
 		CleanupPolicy: media.CleanupPolicyForgetOnly,
-	}, scope)
+	}
+	storePath := resolved
+	size := ""
+	if decodable {
+		size = fmt.Sprintf(" (%dx%d)", imgCfg.Width, imgCfg.Height)
+		w, h := fitDimensions(imgCfg.Width, imgCfg.Height, int(maxDim))
+		if w != imgCfg.Width || h != imgCfg.Height {
+			scaled, contentType, err := writeDownscaledImage(resolved, w, h)
+			if err != nil {
+				return ErrorResult(fmt.Sprintf("failed to downscale image: %v", err))
+			}
+			storePath = scaled
+			meta.ContentType = contentType
+			meta.CleanupPolicy = media.CleanupPolicyDeleteOnCleanup
+			size = fmt.Sprintf(" (%dx%d, downscaled from %dx%d)", w, h, imgCfg.Width, imgCfg.Height)
+		}
+	}
+
+	ref, err := t.mediaStore.Store(storePath, meta, scope)
 	if err != nil {
+		if storePath != resolved {
+			_ = os.Remove(storePath)
+		}
 		return ErrorResult(fmt.Sprintf("failed to register image in media store: %v", err))
 	}
 
 	// Build the tool result text. The media:// ref in Media will be picked
 	// up by resolveMediaRefs in agent_media.go and base64-encoded for tool
 	// result messages (role="tool"), so the LLM can see the image content.
-	msg := fmt.Sprintf("Image loaded: %s\n[image: photo]", filename)
+	msg := fmt.Sprintf("Image loaded: %s%s\n[image: photo]", filename, size)
 
 	return &ToolResult{
 		ForLLM:  msg,
@@ -161,3 +216,55 @@ func (t *LoadImageTool) Execute(ctx context.Context, args map[string]any) *ToolR
 		Media: []string{ref},
 	}
 }
+
+// probeImageFile reads the header of an image. decodable is false for formats
+// the standard library cannot decode, which are then passed through unchecked.
+func probeImageFile(path string) (cfg image.Config, decodable bool, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return image.Config{}, false, err
+	}
+	defer f.Close()
+	cfg, _, err = image.DecodeConfig(f)
+	if errors.Is(err, image.ErrFormat) {
+		return image.Config{}, false, nil
+	}
+	return cfg, err == nil, err
+}
+
+// writeDownscaledImage decodes the image at path, resizes it to w×h and
+// writes the result to a temporary file in the media directory. It returns
+// the new file's path and content type.
+func writeDownscaledImage(path string, w, h int) (string, string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", "", err
+	}
+	src, _, err := image.Decode(f)
+	f.Close()
+	if err != nil {
+		return "", "", err
+	}
+	data, ext, err := encodeImage(downscaleImage(src, w, h))
+	if err != nil {
+		return "", "", err
+	}
+
+	if err := os.MkdirAll(media.TempDir(), 0o700); err != nil {
+		return "", "", err
+	}
+	out, err := os.CreateTemp(media.TempDir(), "load-image-*"+ext)
+	if err != nil {
+		return "", "", err
+	}
+	if _, err := out.Write(data); err != nil {
+		out.Close()
+		os.Remove(out.Name())
+		return "", "", err
+	}
+	if err := out.Close(); err != nil {
+		os.Remove(out.Name())
+		return "", "", err
+	}
+	return out.Name(), mime.TypeByExtension(ext), nil
+}
@@ -2,6 +2,9 @@ package fstools
 
 import (
 	"context"
+	"image"
+	"image/color"
+	"image/png"
 	"os"
 	"path/filepath"
 	"strings"
@@ -150,3 +153,125 @@ func TestLoadImage_SuccessPath(t *testing.T) {
 		t.Errorf("expected resolved path %q, got %q", imgPath, resolved)
 	}
 }
+
+func writeTestPNG(t *testing.T, path string, w, h int) {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := range h {
+		for x := range w {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("failed to create test PNG: %v", err)
+	}
+	defer f.Close()
+	if err := png.Encode(f, img); err != nil {
+		t.Fatalf("failed to encode test PNG: %v", err)
+	}
+}
+
+func TestLoadImage_DownscalesLargeImage(t *testing.T) {
+	dir := t.TempDir()
+	imgPath := filepath.Join(dir, "wide.png")
+	writeTestPNG(t, imgPath, 400, 200)
+
+	store := media.NewFileMediaStore()
+	tool := NewLoadImageTool(dir, false, 0, store)
+	ctx := WithToolContext(context.Background(), "test", "chat1")
+	result := tool.Execute(ctx, map[string]any{"path": imgPath, "max_dimension": 100})
+	if result.IsError {
+		t.Fatalf("expected success, got error: %s", result.ForLLM)
+	}
+	if !strings.Contains(result.ForLLM, "(100x50, downscaled from 400x200)") {
+		t.Errorf("expected downscale note, got: %s", result.ForLLM)
+	}
+
+	resolved, meta, err := store.ResolveWithMeta(result.Media[0])
+	if err != nil {
+		t.Fatalf("media ref not resolvable: %v", err)
+	}
+	t.Cleanup(func() { os.Remove(resolved) })
+	if resolved == imgPath {
+		t.Fatal("expected a downscaled copy, got the original file")
+	}
+	if meta.ContentType != "image/jpeg" {
+		t.Errorf("expected image/jpeg for an opaque image, got %q", meta.ContentType)
+	}
+	f, err := os.Open(resolved)
+	if err != nil {
+		t.Fatalf("failed to open downscaled image: %v", err)
+	}
+	defer f.Close()
+	cfg, _, err := image.DecodeConfig(f)
+	if err != nil {
+		t.Fatalf("downscaled image is not decodable: %v", err)
+	}
+	if cfg.Width != 100 || cfg.Height != 50 {
+		t.Errorf("expected 100x50, got %dx%d", cfg.Width, cfg.Height)
+	}
+}
+
+func TestLoadImage_SmallImageKeepsOriginal(t *testing.T) {
+	dir := t.TempDir()
+	imgPath := filepath.Join(dir, "small.png")
+	writeTestPNG(t, imgPath, 40, 30)
+
+	store := media.NewFileMediaStore()
+	tool := NewLoadImageTool(dir, false, 0, store)
+	ctx := WithToolContext(context.Background(), "test", "chat1")
+	result := tool.Execute(ctx, map[string]any{"path": imgPath})
+	if result.IsError {
+		t.Fatalf("expected success, got error: %s", result.ForLLM)
+	}
+	if !strings.Contains(result.ForLLM, "small.png (40x30)") {
+		t.Errorf("expected dimensions in result, got: %s", result.ForLLM)
+	}
+	if resolved, _ := store.Resolve(result.Media[0]); resolved != imgPath {
+		t.Errorf("expected original path %q, got %q", imgPath, resolved)
+	}
+}
+
+func TestLoadImage_CorruptImage(t *testing.T) {
+	dir := t.TempDir()
+	imgPath := filepath.Join(dir, "broken.png")
+	data := append([]byte{0x89, 0x50, 0x4E, 0x47, 0x0D, 0x0A, 0x1A, 0x0A}, []byte("not really a png")...)
+	if err := os.WriteFile(imgPath, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tool := NewLoadImageTool(dir, false, 0, media.NewFileMediaStore())
+	ctx := WithToolContext(context.Background(), "test", "chat1")
+	result := tool.Execute(ctx, map[string]any{"path": imgPath})
+	if !result.IsError || !strings.Contains(result.ForLLM, "not a valid image") {
+		t.Fatalf("expected invalid image error, got: %s", result.ForLLM)
+	}
+}
+
+func TestLoadImage_MaxDimensionTooSmall(t *testing.T) {
+	tool := NewLoadImageTool("/tmp", false, 0, media.NewFileMediaStore())
+	ctx := WithToolContext(context.Background(), "test", "chat1")
+	result := tool.Execute(ctx, map[string]any{"path": "x.png", "max_dimension": 8})
+	if !result.IsError || !strings.Contains(result.ForLLM, "max_dimension") {
+		t.Fatalf("expected max_dimension error, got: %s", result.ForLLM)
+	}
+}
+
+func TestFitDimensions(t *testing.T) {
+	tests := []struct {
+		w, h, maxDim int
+		wantW, wantH int
+	}{
+		{800, 600, 1000, 800, 600},
+		{4000, 3000, 1568, 1568, 1176},
+		{3000, 4000, 1568, 1176, 1568},
+		{5000, 2, 100, 100, 1},
+	}
+	for _, tt := range tests {
+		w, h := fitDimensions(tt.w, tt.h, tt.maxDim)
+		if w != tt.wantW || h != tt.wantH {
+			t.Errorf("fitDimensions(%d, %d, %d) = %dx%d, want %dx%d", tt.w, tt.h, tt.maxDim, w, h, tt.wantW, tt.wantH)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package fstools
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/sipeed/picoclaw/pkg/logger"
+)
+
+const fileLockRetryInterval = 25 * time.Millisecond
+
+// fileLockStripes is the number of locks paths are hashed onto. A fixed set
+// keeps fileLockDir from growing with every path ever written and bounds the
+// descriptors a bulk operation holds, at the cost of unrelated paths now and
+// then waiting for each other.
+const fileLockStripes = 256
+
+// fileLockTimeout bounds how long a tool waits for another process to release
+// a file before giving up.
+var fileLockTimeout = 10 * time.Second
+
+// fileLockDir holds the lock files shared by every picoclaw process on the
+// host. They live outside the workspace so locking never creates files next
+// to the ones being edited.
+var fileLockDir = filepath.Join(os.TempDir(), "picoclaw-locks")
+
+// stripeLocks serializes the tools of one process on each stripe.
+var stripeLocks [fileLockStripes]sync.Mutex
+
+func lockStripeOf(abs string) int {
+	sum := sha256.Sum256([]byte(filepath.Clean(abs)))
+	return int(sum[0]) % fileLockStripes
+}
+
+// lockPath takes the advisory write lock for the absolute path abs.
+func lockPath(abs string) (func(), error) {
+	return lockAbsPaths(abs)
+}
+
+// lockAbsPaths takes the advisory write locks for the absolute paths abs.
+// Each path maps to a stripe: agents and subagents in this process are
+// serialized with the stripe's mutex, and other processes with an OS file
+// lock on the stripe's lock file. Stripes are taken once each and in
+// ascending order, so overlapping calls cannot deadlock and a path given
+// twice, under any spelling, does not wait for itself. The locks are not
+// reentrant, so a caller takes every path it needs in one call. If a lock
+// file cannot be used, its stripe degrades to in-process locking. The
+// returned function releases every lock.
+func lockAbsPaths(abs ...string) (func(), error) {
+	byStripe := make(map[int]string, len(abs))
+	for _, path := range abs {
+		stripe := lockStripeOf(path)
+		if _, ok := byStripe[stripe]; !ok {
+			byStripe[stripe] = path
+		}
+	}
+	stripes := make([]int, 0, len(byStripe))
+	for stripe := range byStripe {
+		stripes = append(stripes, stripe)
+	}
+	slices.Sort(stripes)
+
+	var unlocks []func()
+	unlockAll := func() {
+		for i := len(unlocks) - 1; i >= 0; i-- {
+			unlocks[i]()
+		}
+	}
+	for _, stripe := range stripes {
+		unlock, err := lockStripe(stripe, byStripe[stripe])
+		if err != nil {
+			unlockAll()
+			return nil, err
+		}
+		unlocks = append(unlocks, unlock)
+	}
+	return unlockAll, nil
+}
+
+// lockStripe locks one stripe; abs names it in logs and errors.
+func lockStripe(stripe int, abs string) (func(), error) {
+	m := &stripeLocks[stripe]
+	m.Lock()
+
+	f, err := openLockFile(stripe)
+	if err != nil {
+		logger.DebugCF("tool", "File lock unavailable, locking within this process only",
+			map[string]any{"path": abs, "error": err.Error()})
+		return m.Unlock, nil
+	}
+	deadline := time.Now().Add(fileLockTimeout)
+	for {
+		locked, err := tryLockFile(f)
+		if err != nil {
+			_ = f.Close()
+			logger.DebugCF("tool", "File lock unavailable, locking within this process only",
+				map[string]any{"path": abs, "error": err.Error()})
+			return m.Unlock, nil
+		}
+		if locked {
+			break
+		}
+		if time.Now().After(deadline) {
+			_ = f.Close()
+			m.Unlock()
+			return nil, fmt.Errorf("failed to lock %s: another process is still writing it", abs)
+		}
+		time.Sleep(fileLockRetryInterval)
+	}
+
+	return func() {
+		_ = unlockFile(f)
+		_ = f.Close()
+		m.Unlock()
+	}, nil
+}
+
+func openLockFile(stripe int) (*os.File, error) {
+	if err := os.MkdirAll(fileLockDir, 0o700); err != nil {
+		return nil, err
+	}
+	name := filepath.Join(fileLockDir, fmt.Sprintf("%02x.lock", stripe))
+	return os.OpenFile(name, os.O_RDWR|os.O_CREATE, 0o600)
+}
+
+// lockResolved resolves every path with resolve and locks the results.
+func lockResolved(resolve func(path string) (string, error), paths []string) (func(), error) {
+	abs := make([]string, 0, len(paths))
+	for _, path := range paths {
+		resolved, err := resolve(path)
+		if err != nil {
+			return nil, err
+		}
+		abs = append(abs, resolved)
+	}
+	return lockAbsPaths(abs...)
+}
+
+// lockPaths locks every non-empty path through sysFs in one call.
+func lockPaths(sysFs fileSystem, paths ...string) (func(), error) {
+	return sysFs.Lock(slices.DeleteFunc(slices.Clone(paths), func(path string) bool {
+		return path == ""
+	})...)
+}
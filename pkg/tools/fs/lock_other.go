@@ -0,0 +1,15 @@
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd || windows)
+
+package fstools
+
+import "os"
+
+// tryLockFile reports success without locking: this platform has no file
+// lock we rely on, so writes are only serialized within the process.
+func tryLockFile(f *os.File) (bool, error) {
+	return true, nil
+}
+
+func unlockFile(f *os.File) error {
+	return nil
+}
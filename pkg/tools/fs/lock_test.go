@@ -0,0 +1,127 @@
+package fstools
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func useTestLockDir(t *testing.T) {
+	t.Helper()
+	dir, timeout := fileLockDir, fileLockTimeout
+	fileLockDir = t.TempDir()
+	t.Cleanup(func() { fileLockDir, fileLockTimeout = dir, timeout })
+}
+
+func TestAppendFileTool_ConcurrentAppendsKeepEveryLine(t *testing.T) {
+	useTestLockDir(t)
+	workspace := t.TempDir()
+	tool := NewAppendFileTool(workspace, true)
+
+	const writers = 20
+	var wg sync.WaitGroup
+	for i := range writers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result := tool.Execute(context.Background(), map[string]any{
+				"path":    "log.txt",
+				"content": fmt.Sprintf("line %d\n", i),
+			})
+			assert.False(t, result.IsError, result.ForLLM)
+		}()
+	}
+	wg.Wait()
+
+	data, err := os.ReadFile(filepath.Join(workspace, "log.txt"))
+	require.NoError(t, err)
+	assert.Len(t, strings.Split(strings.TrimSpace(string(data)), "\n"), writers)
+}
+
+func TestLockPath_WaitsForOtherHolder(t *testing.T) {
+	useTestLockDir(t)
+	abs := filepath.Join(t.TempDir(), "a.txt")
+
+	unlock, err := lockPath(abs)
+	require.NoError(t, err)
+
+	acquired := make(chan struct{})
+	go func() {
+		second, err := lockPath(abs)
+		assert.NoError(t, err)
+		close(acquired)
+		second()
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("second lock acquired while the first was held")
+	case <-time.After(50 * time.Millisecond):
+	}
+	unlock()
+	<-acquired
+}
+
+func TestLockPath_TimesOutOnForeignFileLock(t *testing.T) {
+	useTestLockDir(t)
+	fileLockTimeout = 100 * time.Millisecond
+	abs := filepath.Join(t.TempDir(), "a.txt")
+
+	// A second descriptor on the lock file stands in for another process.
+	f, err := openLockFile(lockStripeOf(abs))
+	require.NoError(t, err)
+	defer f.Close()
+	locked, err := tryLockFile(f)
+	require.NoError(t, err)
+	require.True(t, locked)
+
+	_, err = lockPath(abs)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "another process")
+
+	require.NoError(t, unlockFile(f))
+	unlock, err := lockPath(abs)
+	require.NoError(t, err)
+	unlock()
+}
+
+func TestLockPaths_IgnoresDuplicates(t *testing.T) {
+	useTestLockDir(t)
+	workspace := t.TempDir()
+	sysFs := buildFs(workspace, true, nil)
+
+	unlock, err := lockPaths(sysFs, "b.txt", "a.txt", "b.txt", "")
+	require.NoError(t, err)
+	unlock()
+
+	// Two spellings of one file must not wait for each other.
+	unlock, err = lockPaths(sysFs, "a.txt", "./sub/../a.txt")
+	require.NoError(t, err)
+	unlock()
+}
+
+func TestLockPaths_BoundsLockFiles(t *testing.T) {
+	useTestLockDir(t)
+	workspace := t.TempDir()
+	sysFs := buildFs(workspace, true, nil)
+
+	paths := make([]string, 0, 2*fileLockStripes)
+	for i := range 2 * fileLockStripes {
+		paths = append(paths, fmt.Sprintf("f%d.txt", i))
+	}
+	unlock, err := lockPaths(sysFs, paths...)
+	require.NoError(t, err)
+	unlock()
+
+	entries, err := os.ReadDir(fileLockDir)
+	require.NoError(t, err)
+	assert.LessOrEqual(t, len(entries), fileLockStripes)
+}
@@ -0,0 +1,94 @@
+package fstools
+
+// observedFs reports every file a tool wrote or deleted, as an absolute path,
+// once the change succeeded. The file watch service uses it to tell the
+// agent's own writes apart from changes made by someone else.
+type observedFs struct {
+	fileSystem
+	workspace    string
+	hostRelative bool
+	observe      func(path string)
+}
+
+func newObservedFs(inner fileSystem, workspace string, observe func(path string)) *observedFs {
+	return &observedFs{
+		fileSystem:   inner,
+		workspace:    workspace,
+		hostRelative: isHostRelative(inner),
+		observe:      observe,
+	}
+}
+
+func (o *observedFs) WriteFile(path string, data []byte) error {
+	err := o.fileSystem.WriteFile(path, data)
+	if err == nil {
+		o.observe(absoluteToolPath(o.workspace, path, o.hostRelative))
+	}
+	return err
+}
+
+func (o *observedFs) Remove(path string) error {
+	err := o.fileSystem.Remove(path)
+	if err == nil {
+		o.observe(absoluteToolPath(o.workspace, path, o.hostRelative))
+	}
+	return err
+}
+
+// writeObserverTarget is implemented by the tools that create, change or
+// delete files.
+type writeObserverTarget interface {
+	applyWriteObserver(workspace string, observe func(path string))
+}
+
+// ApplyWriteObserver makes a filesystem tool call observe with the absolute
+// path of every file it writes or deletes. Relative paths are resolved
+// against workspace. It reports false for tools that never modify files, and
+// is a no-op when observe is nil.
+func ApplyWriteObserver(tool any, workspace string, observe func(path string)) bool {
+	target, ok := tool.(writeObserverTarget)
+	if ok && observe != nil {
+		target.applyWriteObserver(workspace, observe)
+	}
+	return ok
+}
+
+func (t *WriteFileTool) applyWriteObserver(w string, fn func(string)) {
+	t.fs = newObservedFs(t.fs, w, fn)
+}
+
+func (t *EditFileTool) applyWriteObserver(w string, fn func(string)) {
+	t.fs = newObservedFs(t.fs, w, fn)
+}
+
+func (t *AppendFileTool) applyWriteObserver(w string, fn func(string)) {
+	t.fs = newObservedFs(t.fs, w, fn)
+}
+
+func (t *MultiEditTool) applyWriteObserver(w string, fn func(string)) {
+	t.fs = newObservedFs(t.fs, w, fn)
+}
+
+func (t *ApplyPatchTool) applyWriteObserver(w string, fn func(string)) {
+	t.fs = newObservedFs(t.fs, w, fn)
+}
+
+func (t *DeleteFileTool) applyWriteObserver(w string, fn func(string)) {
+	t.fs = newObservedFs(t.fs, w, fn)
+}
+
+func (t *CopyFileTool) applyWriteObserver(w string, fn func(string)) {
+	t.fs = newObservedFs(t.fs, w, fn)
+}
+
+func (t *ArchiveTool) applyWriteObserver(w string, fn func(string)) {
+	t.fs = newObservedFs(t.fs, w, fn)
+}
+
+func (t *WriteSessionTool) applyWriteObserver(w string, fn func(string)) {
+	t.fs = newObservedFs(t.fs, w, fn)
+}
+
+func (t *UndoFileTool) applyWriteObserver(w string, fn func(string)) {
+	t.fs = newObservedFs(t.fs, w, fn)
+}
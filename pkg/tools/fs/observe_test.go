@@ -0,0 +1,33 @@
+package fstools
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyWriteObserver_ReportsSuccessfulWrites(t *testing.T) {
+	workspace := t.TempDir()
+	var observed []string
+	observe := func(path string) { observed = append(observed, path) }
+
+	write := NewWriteFileTool(workspace, true)
+	require.True(t, ApplyWriteObserver(write, workspace, observe))
+	del := NewDeleteFileTool(workspace, true)
+	require.True(t, ApplyWriteObserver(del, workspace, observe))
+	assert.False(t, ApplyWriteObserver(NewListDirTool(workspace, true), workspace, observe))
+
+	ctx := context.Background()
+	result := write.Execute(ctx, map[string]any{"path": "notes.txt", "content": "v1"})
+	require.False(t, result.IsError, result.ForLLM)
+	result = del.Execute(ctx, map[string]any{"path": "notes.txt"})
+	require.False(t, result.IsError, result.ForLLM)
+	result = write.Execute(ctx, map[string]any{"path": "../outside.txt", "content": "x"})
+	require.True(t, result.IsError)
+
+	want := filepath.Join(workspace, "notes.txt")
+	assert.Equal(t, []string{want, want}, observed)
+}
@@ -0,0 +1,430 @@
+package fstools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const maxPatchPreviewBytes = 16 * 1024
+
+// ApplyPatchTool applies a unified diff, possibly touching several files.
+// Hunks are located by their context rather than trusted line numbers, and a
+// hunk whose context differs from the file only in whitespace still applies.
+// Every file is patched in memory first; nothing is written unless all hunks
+// apply.
+type ApplyPatchTool struct {
+	fs fileSystem
+}
+
+// NewApplyPatchTool creates a new ApplyPatchTool with optional directory restriction.
+func NewApplyPatchTool(workspace string, restrict bool, allowPaths ...[]*regexp.Regexp) *ApplyPatchTool {
+	var patterns []*regexp.Regexp
+	if len(allowPaths) > 0 {
+		patterns = allowPaths[0]
+	}
+	return &ApplyPatchTool{fs: buildFs(workspace, restrict, patterns)}
+}
+
+func (t *ApplyPatchTool) Name() string {
+	return "apply_patch"
+}
+
+func (t *ApplyPatchTool) Description() string {
+	return "Apply a unified diff (as produced by `diff -u` or `git diff`) that may change, create (--- /dev/null), delete (+++ /dev/null) or rename several files. Hunk line numbers are only hints: each hunk is located by its context and removed lines, tolerating whitespace differences. The patch is applied to all files or to none."
+}
+
+func (t *ApplyPatchTool) Parameters() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"patch": map[string]any{
+				"type":        "string",
+				"description": "The unified diff to apply. Paths may use git's a/ and b/ prefixes.",
+			},
+		},
+		"required": []string{"patch"},
+	}
+}
+
+type patchLine struct {
+	op   byte // ' ', '-' or '+'
+	text string
+	// bare marks a context line written as an empty line without the
+	// leading space.
+	bare bool
+}
+
+type patchHunk struct {
+	oldStart int
+	oldCount int
+	lines    []patchLine
+	// noEOL records a "\ No newline at end of file" marker on the new side.
+	noEOL bool
+}
+
+type filePatch struct {
+	oldPath string // empty for /dev/null
+	newPath string // empty for /dev/null
+	hunks   []patchHunk
+}
+
+// patchedFile is the in-memory outcome of applying one filePatch.
+type patchedFile struct {
+	patch   *filePatch
+	content []byte
+	fuzzy   int
+}
+
+// stagedFile is a file as earlier file patches in the same patch left it.
+type stagedFile struct {
+	content []byte
+	removed bool
+}
+
+func (t *ApplyPatchTool) Execute(ctx context.Context, args map[string]any) *ToolResult {
+	patchText, ok := args["patch"].(string)
+	if !ok || strings.TrimSpace(patchText) == "" {
+		return ErrorResult("patch is required")
+	}
+
+	files, err := parseUnifiedDiff(patchText)
+	if err != nil {
+		return ErrorResult(fmt.Sprintf("failed to parse patch: %v", err))
+	}
+
+	// Hold every touched file until the whole patch is written, so no other
+	// writer can change a file between computing its hunks and writing it.
+	paths := make([]string, 0, 2*len(files))
+	for _, p := range files {
+		paths = append(paths, p.oldPath, p.newPath)
+	}
+	unlock, err := lockPaths(t.fs, paths...)
+	if err != nil {
+		return ErrorResult(err.Error())
+	}
+	defer unlock()
+
+	// A path may appear in several file patches; each one applies to the
+	// result of the previous ones, so they are staged in order.
+	staged := make(map[string]stagedFile)
+	results := make([]patchedFile, 0, len(files))
+	for i := range files {
+		result, err := t.patchFile(&files[i], staged)
+		if err != nil {
+			return ErrorResult(fmt.Sprintf("%v; no files were changed", err))
+		}
+		if p := result.patch; p.oldPath != "" && p.oldPath != p.newPath {
+			staged[p.oldPath] = stagedFile{removed: true}
+		}
+		if p := result.patch; p.newPath != "" {
+			staged[p.newPath] = stagedFile{content: result.content}
+		}
+		results = append(results, result)
+	}
+
+	var summary strings.Builder
+	summary.WriteString("Patch applied:\n")
+	for _, result := range results {
+		p := result.patch
+		switch {
+		case p.newPath == "":
+			if err := t.fs.Remove(p.oldPath); err != nil {
+				return ErrorResult(fmt.Sprintf("%v (patch partially applied)", err))
+			}
+			fmt.Fprintf(&summary, "D %s\n", p.oldPath)
+			continue
+		default:
+			if err := t.fs.WriteFile(p.newPath, result.content); err != nil {
+				return ErrorResult(fmt.Sprintf("%v (patch partially applied)", err))
+			}
+		}
+
+		switch {
+		case p.oldPath == "":
+			fmt.Fprintf(&summary, "A %s\n", p.newPath)
+		case p.oldPath != p.newPath:
+			if err := t.fs.Remove(p.oldPath); err != nil {
+				return ErrorResult(fmt.Sprintf("%v (patch partially applied)", err))
+			}
+			fmt.Fprintf(&summary, "R %s -> %s (%d hunks)\n", p.oldPath, p.newPath, len(p.hunks))
+		default:
+			fmt.Fprintf(&summary, "M %s (%d hunks", p.newPath, len(p.hunks))
+			if result.fuzzy > 0 {
+				fmt.Fprintf(&summary, ", %d matched ignoring whitespace", result.fuzzy)
+			}
+			summary.WriteString(")\n")
+		}
+	}
+
+	preview := patchText
+	if len(preview) > maxPatchPreviewBytes {
+		preview = preview[:maxPatchPreviewBytes] + "\n[... patch preview truncated ...]"
+	}
+	return &ToolResult{
+		ForLLM:  summary.String(),
+		ForUser: fmt.Sprintf("%s```diff\n%s\n```", summary.String(), strings.TrimRight(preview, "\n")),
+	}
+}
+
+// readStaged returns the content an earlier file patch staged for path, or
+// the file on disk.
+func (t *ApplyPatchTool) readStaged(staged map[string]stagedFile, path string) ([]byte, error) {
+	if file, ok := staged[path]; ok {
+		if file.removed {
+			return nil, fs.ErrNotExist
+		}
+		return file.content, nil
+	}
+	return t.fs.ReadFile(path)
+}
+
+// patchFile computes the new content for one file without writing it.
+// UTF-16 and Windows-1252 files are patched as UTF-8 and encoded back.
+func (t *ApplyPatchTool) patchFile(p *filePatch, staged map[string]stagedFile) (patchedFile, error) {
+	result := patchedFile{patch: p}
+
+	var (
+		original []byte
+		enc      *textEncoding
+		hasBOM   bool
+	)
+	if p.oldPath != "" {
+		data, err := t.readStaged(staged, p.oldPath)
+		if err != nil {
+			return result, fmt.Errorf("%s: %w", p.oldPath, err)
+		}
+		if original, enc, hasBOM, err = decodeFileText(data); err != nil {
+			return result, fmt.Errorf("%s: %w", p.oldPath, err)
+		}
+	} else if _, err := t.readStaged(staged, p.newPath); err == nil {
+		return result, fmt.Errorf("%s: patch creates the file but it already exists", p.newPath)
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return result, fmt.Errorf("%s: %w", p.newPath, err)
+	}
+
+	lines, eol, trailingNewline := splitPatchLines(original)
+	delta := 0
+	for i, hunk := range p.hunks {
+		oldLines, newLines := hunk.sides()
+		hint := max(hunk.oldStart-1+delta, 0)
+		if hunk.oldCount == 0 {
+			// A pure insertion such as "@@ -5,0 +6 @@" goes after old
+			// line oldStart, not before it.
+			hint = hunk.oldStart + delta
+		}
+
+		pos, fuzzy, ok := locateHunk(lines, oldLines, hint)
+		if !ok {
+			return result, fmt.Errorf(
+				"%s: hunk %d (@@ -%d) does not match the file; re-read the file and regenerate the patch",
+				p.oldPath, i+1, hunk.oldStart,
+			)
+		}
+		if fuzzy {
+			result.fuzzy++
+		}
+
+		// Keep the file's own context lines so a whitespace-tolerant match
+		// does not rewrite lines the patch did not mean to change.
+		replacement := make([]string, 0, len(newLines))
+		cursor := pos
+		for _, line := range hunk.lines {
+			switch line.op {
+			case ' ':
+				replacement = append(replacement, lines[cursor])
+				cursor++
+			case '-':
+				cursor++
+			case '+':
+				replacement = append(replacement, line.text)
+			}
+		}
+
+		atEOF := pos+len(oldLines) == len(lines)
+		lines = append(lines[:pos:pos], append(replacement, lines[pos+len(oldLines):]...)...)
+		if atEOF {
+			trailingNewline = !hunk.noEOL
+		}
+		delta += len(newLines) - len(oldLines)
+	}
+
+	// A deletion must account for the whole file, so a stale patch cannot
+	// delete content the agent has not seen.
+	if p.newPath == "" {
+		if len(lines) > 0 {
+			return result, fmt.Errorf(
+				"%s: patch deletes the file but leaves %d line(s) it does not remove; "+
+					"re-read the file and regenerate the patch",
+				p.oldPath, len(lines),
+			)
+		}
+		return result, nil
+	}
+
+	content := strings.Join(lines, eol)
+	if trailingNewline && len(lines) > 0 {
+		content += eol
+	}
+	encoded, err := encodeFileText([]byte(content), enc, hasBOM)
+	if err != nil {
+		return result, fmt.Errorf("%s: %w", p.newPath, err)
+	}
+	result.content = encoded
+	return result, nil
+}
+
+func (h patchHunk) sides() (oldLines, newLines []string) {
+	for _, line := range h.lines {
+		if line.op != '+' {
+			oldLines = append(oldLines, line.text)
+		}
+		if line.op != '-' {
+			newLines = append(newLines, line.text)
+		}
+	}
+	return oldLines, newLines
+}
+
+// splitPatchLines splits content into lines without their terminators. It
+// returns the file's line ending, taken from its first line, so the patched
+// file keeps it, and reports whether the last line ended with a newline.
+func splitPatchLines(content []byte) ([]string, string, bool) {
+	if len(content) == 0 {
+		return nil, "\n", true
+	}
+	text := string(content)
+	eol := "\n"
+	if i := strings.IndexByte(text, '\n'); i > 0 && text[i-1] == '\r' {
+		eol = "\r\n"
+	}
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	trailing := strings.HasSuffix(text, "\n")
+	text = strings.TrimSuffix(text, "\n")
+	return strings.Split(text, "\n"), eol, trailing
+}
+
+// locateHunk finds where oldLines occur in lines, preferring positions close
+// to hint. It tries an exact match first, then ignores trailing whitespace,
+// then compares with all whitespace runs collapsed. fuzzy reports whether a
+// whitespace-tolerant comparison was needed.
+func locateHunk(lines, oldLines []string, hint int) (pos int, fuzzy bool, ok bool) {
+	if len(oldLines) == 0 {
+		return min(hint, len(lines)), false, true
+	}
+	for level, normalize := range lineNormalizers {
+		want := make([]string, len(oldLines))
+		for i, line := range oldLines {
+			want[i] = normalize(line)
+		}
+		last := len(lines) - len(oldLines)
+		for dist := 0; dist <= max(hint, last-hint); dist++ {
+			for _, candidate := range []int{hint - dist, hint + dist} {
+				if candidate < 0 || candidate > last {
+					continue
+				}
+				if linesMatch(lines[candidate:candidate+len(want)], want, normalize) {
+					return candidate, level > 0, true
+				}
+			}
+		}
+	}
+	return 0, false, false
+}
+
+var hunkHeaderPattern = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+\d+(?:,\d+)? @@`)
+
+// parseUnifiedDiff parses a unified diff leniently: hunk line counts are
+// ignored, because model-written patches often get them wrong, and blank
+// lines inside a hunk are read as empty context lines.
+func parseUnifiedDiff(text string) ([]filePatch, error) {
+	rawLines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	var files []filePatch
+	var current *filePatch
+	var hunk *patchHunk
+
+	flushHunk := func() {
+		if current != nil && hunk != nil {
+			// Bare blank lines at the end of a hunk are usually separators
+			// between files rather than context.
+			for n := len(hunk.lines); n > 0 && hunk.lines[n-1].bare; n-- {
+				hunk.lines = hunk.lines[:n-1]
+			}
+			current.hunks = append(current.hunks, *hunk)
+		}
+		hunk = nil
+	}
+
+	for i := 0; i < len(rawLines); i++ {
+		line := rawLines[i]
+		switch {
+		case strings.HasPrefix(line, "--- ") && i+1 < len(rawLines) && strings.HasPrefix(rawLines[i+1], "+++ "):
+			flushHunk()
+			files = append(files, filePatch{
+				oldPath: parsePatchPath(strings.TrimPrefix(line, "--- "), "a/"),
+				newPath: parsePatchPath(strings.TrimPrefix(rawLines[i+1], "+++ "), "b/"),
+			})
+			current = &files[len(files)-1]
+			i++
+		case strings.HasPrefix(line, "@@"):
+			if current == nil {
+				return nil, fmt.Errorf("line %d: hunk before any --- / +++ file header", i+1)
+			}
+			flushHunk()
+			m := hunkHeaderPattern.FindStringSubmatch(line)
+			if m == nil {
+				return nil, fmt.Errorf("line %d: malformed hunk header %q", i+1, line)
+			}
+			start, _ := strconv.Atoi(m[1])
+			count := 1
+			if m[2] != "" {
+				count, _ = strconv.Atoi(m[2])
+			}
+			hunk = &patchHunk{oldStart: start, oldCount: count}
+		case hunk == nil:
+			// Preamble such as "diff --git" or "index" lines.
+		case strings.HasPrefix(line, `\`):
+			if n := len(hunk.lines); n > 0 && hunk.lines[n-1].op != '-' {
+				hunk.noEOL = true
+			}
+		case line == "":
+			hunk.lines = append(hunk.lines, patchLine{op: ' ', bare: true})
+		case line[0] == ' ' || line[0] == '-' || line[0] == '+':
+			hunk.lines = append(hunk.lines, patchLine{op: line[0], text: line[1:]})
+		default:
+			// Anything else (e.g. the next "diff --git" line) ends the hunk.
+			flushHunk()
+		}
+	}
+	flushHunk()
+
+	if len(files) == 0 {
+		return nil, errors.New("no file headers (--- / +++) found")
+	}
+	for _, f := range files {
+		if f.oldPath == "" && f.newPath == "" {
+			return nil, errors.New("file header has /dev/null on both sides")
+		}
+		if len(f.hunks) == 0 && f.newPath != "" {
+			return nil, fmt.Errorf("%s: no hunks", f.newPath)
+		}
+	}
+	return files, nil
+}
+
+// parsePatchPath strips git's a/ or b/ prefix and any trailing timestamp from
+// a file header path. /dev/null is returned as "".
+func parsePatchPath(raw, prefix string) string {
+	if tab := strings.IndexByte(raw, '\t'); tab >= 0 {
+		raw = raw[:tab]
+	}
+	raw = strings.TrimSpace(raw)
+	if raw == "/dev/null" {
+		return ""
+	}
+	return strings.TrimPrefix(raw, prefix)
+}
@@ -0,0 +1,266 @@
+package fstools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyPatchTool_MultipleFiles(t *testing.T) {
+	workspace := t.TempDir()
+	writeFindFixture(t, workspace, map[string]string{
+		"a.txt":     "one\ntwo\nthree\nfour\n",
+		"old.txt":   "gone\n",
+		"dir/b.txt": "alpha\nbeta\n",
+	})
+
+	patch := `diff --git a/a.txt b/a.txt
+--- a/a.txt
++++ b/a.txt
+@@ -1,4 +1,4 @@
+ one
+-two
++TWO
+ three
+ four
+--- a/dir/b.txt
++++ b/dir/b.txt
+@@ -2,1 +2,2 @@
+ beta
++gamma
+--- /dev/null
++++ b/new.txt
+@@ -0,0 +1,2 @@
++hello
++world
+--- a/old.txt
++++ /dev/null
+@@ -1 +0,0 @@
+-gone
+`
+	tool := NewApplyPatchTool(workspace, true)
+	result := tool.Execute(context.Background(), map[string]any{"patch": patch})
+	require.False(t, result.IsError, result.ForLLM)
+	assert.Contains(t, result.ForLLM, "M a.txt")
+	assert.Contains(t, result.ForLLM, "A new.txt")
+	assert.Contains(t, result.ForLLM, "D old.txt")
+
+	assertFileContent(t, filepath.Join(workspace, "a.txt"), "one\nTWO\nthree\nfour\n")
+	assertFileContent(t, filepath.Join(workspace, "dir", "b.txt"), "alpha\nbeta\ngamma\n")
+	assertFileContent(t, filepath.Join(workspace, "new.txt"), "hello\nworld\n")
+	_, err := os.Stat(filepath.Join(workspace, "old.txt"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestApplyPatchTool_ToleratesOffsetsAndWhitespace(t *testing.T) {
+	workspace := t.TempDir()
+	writeFindFixture(t, workspace, map[string]string{
+		"main.go": "package main\n\n// added later\n\nfunc main() {\n\tprintln(\"hi\")  \n}\n",
+	})
+
+	// Wrong line numbers, and context without the trailing spaces.
+	patch := `--- main.go
++++ main.go
+@@ -10,3 +10,3 @@
+ func main() {
+-	println("hi")
++	println("hello")
+ }
+`
+	tool := NewApplyPatchTool(workspace, true)
+	result := tool.Execute(context.Background(), map[string]any{"patch": patch})
+	require.False(t, result.IsError, result.ForLLM)
+	assert.Contains(t, result.ForLLM, "1 matched ignoring whitespace")
+	assertFileContent(t, filepath.Join(workspace, "main.go"),
+		"package main\n\n// added later\n\nfunc main() {\n\tprintln(\"hello\")\n}\n")
+}
+
+func TestApplyPatchTool_AllOrNothing(t *testing.T) {
+	workspace := t.TempDir()
+	writeFindFixture(t, workspace, map[string]string{
+		"a.txt": "one\ntwo\n",
+		"b.txt": "three\n",
+	})
+
+	patch := `--- a/a.txt
++++ b/a.txt
+@@ -1,2 +1,2 @@
+ one
+-two
++2
+--- a/b.txt
++++ b/b.txt
+@@ -1 +1 @@
+-missing
++x
+`
+	tool := NewApplyPatchTool(workspace, true)
+	result := tool.Execute(context.Background(), map[string]any{"patch": patch})
+	require.True(t, result.IsError)
+	assert.Contains(t, result.ForLLM, "b.txt: hunk 1")
+	assert.Contains(t, result.ForLLM, "no files were changed")
+	assertFileContent(t, filepath.Join(workspace, "a.txt"), "one\ntwo\n")
+}
+
+func TestApplyPatchTool_NoNewlineAtEOF(t *testing.T) {
+	workspace := t.TempDir()
+	writeFindFixture(t, workspace, map[string]string{"a.txt": "x\ny\n"})
+
+	patch := `--- a/a.txt
++++ b/a.txt
+@@ -1,2 +1,2 @@
+ x
+-y
++z
+\ No newline at end of file
+`
+	tool := NewApplyPatchTool(workspace, true)
+	result := tool.Execute(context.Background(), map[string]any{"patch": patch})
+	require.False(t, result.IsError, result.ForLLM)
+	assertFileContent(t, filepath.Join(workspace, "a.txt"), "x\nz")
+}
+
+func TestApplyPatchTool_PreservesCRLF(t *testing.T) {
+	workspace := t.TempDir()
+	writeFindFixture(t, workspace, map[string]string{"a.txt": "one\r\ntwo\r\nthree\r\n"})
+
+	patch := `--- a/a.txt
++++ b/a.txt
+@@ -1,3 +1,3 @@
+ one
+-two
++TWO
+ three
+`
+	tool := NewApplyPatchTool(workspace, true)
+	result := tool.Execute(context.Background(), map[string]any{"patch": patch})
+	require.False(t, result.IsError, result.ForLLM)
+	assertFileContent(t, filepath.Join(workspace, "a.txt"), "one\r\nTWO\r\nthree\r\n")
+}
+
+func TestApplyPatchTool_ZeroContextInsertion(t *testing.T) {
+	workspace := t.TempDir()
+	writeFindFixture(t, workspace, map[string]string{"a.txt": "1\n2\n3\n4\n5\n6\n"})
+
+	patch := `--- a/a.txt
++++ b/a.txt
+@@ -0,0 +1 @@
++top
+@@ -5,0 +7,1 @@
++new
+`
+	tool := NewApplyPatchTool(workspace, true)
+	result := tool.Execute(context.Background(), map[string]any{"patch": patch})
+	require.False(t, result.IsError, result.ForLLM)
+	assertFileContent(t, filepath.Join(workspace, "a.txt"), "top\n1\n2\n3\n4\n5\nnew\n6\n")
+}
+
+func TestApplyPatchTool_PreservesUTF16(t *testing.T) {
+	workspace := t.TempDir()
+	path := filepath.Join(workspace, "a.txt")
+	require.NoError(t, os.WriteFile(path, utf16LE(t, "one\r\ntwo\r\n", true), 0o644))
+
+	patch := `--- a/a.txt
++++ b/a.txt
+@@ -1,2 +1,2 @@
+ one
+-two
++zwei
+`
+	tool := NewApplyPatchTool(workspace, true)
+	result := tool.Execute(context.Background(), map[string]any{"patch": patch})
+	require.False(t, result.IsError, result.ForLLM)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, utf16LE(t, "one\r\nzwei\r\n", true), data)
+}
+
+func TestApplyPatchTool_RepeatedPathAppliesInOrder(t *testing.T) {
+	workspace := t.TempDir()
+	writeFindFixture(t, workspace, map[string]string{"a.txt": "one\ntwo\nthree\n"})
+
+	patch := `--- a/a.txt
++++ b/a.txt
+@@ -1,2 +1,2 @@
+-one
++ONE
+ two
+--- a/a.txt
++++ b/a.txt
+@@ -2,2 +2,2 @@
+ two
+-three
++THREE
+--- a/a.txt
++++ b/b.txt
+@@ -1 +1 @@
+-ONE
++uno
+`
+	tool := NewApplyPatchTool(workspace, true)
+	result := tool.Execute(context.Background(), map[string]any{"patch": patch})
+	require.False(t, result.IsError, result.ForLLM)
+	assert.NoFileExists(t, filepath.Join(workspace, "a.txt"))
+	assertFileContent(t, filepath.Join(workspace, "b.txt"), "uno\ntwo\nTHREE\n")
+}
+
+func TestApplyPatchTool_DeletionMustMatchWholeFile(t *testing.T) {
+	workspace := t.TempDir()
+	writeFindFixture(t, workspace, map[string]string{
+		"stale.txt":   "changed\n",
+		"partial.txt": "one\ntwo\n",
+		"gone.txt":    "bye\n",
+	})
+	tool := NewApplyPatchTool(workspace, true)
+
+	tests := map[string]string{
+		"stale":   "--- a/stale.txt\n+++ /dev/null\n@@ -1 +0,0 @@\n-original\n",
+		"partial": "--- a/partial.txt\n+++ /dev/null\n@@ -1 +0,0 @@\n-one\n",
+	}
+	for name, patch := range tests {
+		t.Run(name, func(t *testing.T) {
+			result := tool.Execute(context.Background(), map[string]any{"patch": patch})
+			assert.True(t, result.IsError)
+		})
+	}
+	assertFileContent(t, filepath.Join(workspace, "stale.txt"), "changed\n")
+	assertFileContent(t, filepath.Join(workspace, "partial.txt"), "one\ntwo\n")
+
+	result := tool.Execute(context.Background(), map[string]any{
+		"patch": "--- a/gone.txt\n+++ /dev/null\n@@ -1 +0,0 @@\n-bye\n",
+	})
+	require.False(t, result.IsError, result.ForLLM)
+	assert.NoFileExists(t, filepath.Join(workspace, "gone.txt"))
+}
+
+func TestApplyPatchTool_RejectsInvalidPatches(t *testing.T) {
+	workspace := t.TempDir()
+	writeFindFixture(t, workspace, map[string]string{"a.txt": "x\n"})
+	tool := NewApplyPatchTool(workspace, true)
+
+	tests := map[string]string{
+		"empty":      "",
+		"no headers": "@@ -1 +1 @@\n-x\n+y\n",
+		"no hunks":   "--- a/a.txt\n+++ b/a.txt\n",
+		"exists":     "--- /dev/null\n+++ b/a.txt\n@@ -0,0 +1 @@\n+y\n",
+		"escape":     "--- a/../a.txt\n+++ b/../a.txt\n@@ -1 +1 @@\n-x\n+y\n",
+	}
+	for name, patch := range tests {
+		t.Run(name, func(t *testing.T) {
+			result := tool.Execute(context.Background(), map[string]any{"patch": patch})
+			assert.True(t, result.IsError)
+		})
+	}
+	assertFileContent(t, filepath.Join(workspace, "a.txt"), "x\n")
+}
+
+func assertFileContent(t *testing.T, path, want string) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, want, string(data))
+}
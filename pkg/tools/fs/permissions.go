@@ -0,0 +1,124 @@
+package fstools
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// SetPermissionsTool changes the permission bits of a single file or
+// directory. It only accepts plain rwx modes: setuid, setgid, sticky and
+// world-writable bits are refused, and symlinks are never followed, so the
+// agent can make a generated script executable without reaching for chmod in
+// exec.
+type SetPermissionsTool struct {
+	fs fileSystem
+}
+
+// NewSetPermissionsTool creates a new SetPermissionsTool with optional directory restriction.
+func NewSetPermissionsTool(workspace string, restrict bool, allowPaths ...[]*regexp.Regexp) *SetPermissionsTool {
+	var patterns []*regexp.Regexp
+	if len(allowPaths) > 0 {
+		patterns = allowPaths[0]
+	}
+	return &SetPermissionsTool{fs: buildFs(workspace, restrict, patterns)}
+}
+
+func (t *SetPermissionsTool) Name() string {
+	return "set_permissions"
+}
+
+func (t *SetPermissionsTool) Description() string {
+	return "Change the permissions of a file or directory. Use mode=+x to make a script you wrote executable. " +
+		"Accepts +x, -x, +w, -w or an octal mode such as 755 or 644; setuid, setgid, sticky and world-writable modes are not allowed."
+}
+
+func (t *SetPermissionsTool) Parameters() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"path": map[string]any{
+				"type":        "string",
+				"description": "Path to the file or directory",
+			},
+			"mode": map[string]any{
+				"type": "string",
+				"description": "+x adds execute wherever read is allowed, -x removes execute, " +
+					"+w adds owner write, -w removes all write; or an octal mode such as 755",
+			},
+		},
+		"required": []string{"path", "mode"},
+	}
+}
+
+func (t *SetPermissionsTool) Execute(ctx context.Context, args map[string]any) *ToolResult {
+	path, ok := args["path"].(string)
+	if !ok || path == "" {
+		return ErrorResult("path is required")
+	}
+	mode, ok := args["mode"].(string)
+	if !ok || strings.TrimSpace(mode) == "" {
+		return ErrorResult("mode is required")
+	}
+
+	info, err := t.fs.Lstat(path)
+	if err != nil {
+		return ErrorResult(err.Error())
+	}
+	if info.Mode()&fs.ModeSymlink != 0 {
+		return ErrorResult(fmt.Sprintf("%s is a symlink; set permissions on its target instead", path))
+	}
+	if !info.Mode().IsRegular() && !info.IsDir() {
+		return ErrorResult(fmt.Sprintf("%s is not a regular file or directory", path))
+	}
+
+	before := info.Mode().Perm()
+	after, err := resolvePermissionMode(before, strings.TrimSpace(mode))
+	if err != nil {
+		return ErrorResult(err.Error())
+	}
+	if after == before {
+		return SilentResult(fmt.Sprintf("Permissions of %s unchanged: %s", path, before))
+	}
+	if err := t.fs.Chmod(path, after); err != nil {
+		return ErrorResult(err.Error())
+	}
+	return SilentResult(fmt.Sprintf("Permissions of %s changed: %s -> %s", path, before, after))
+}
+
+// resolvePermissionMode applies a symbolic mode to current, or parses an octal
+// one, and rejects the result unless it is a safe permission set.
+func resolvePermissionMode(current fs.FileMode, mode string) (fs.FileMode, error) {
+	var next fs.FileMode
+	switch mode {
+	case "+x":
+		// Like chmod +x under a typical umask: execute follows read, so a
+		// private script stays private.
+		next = current | 0o100 | (current&0o044)>>2
+	case "-x":
+		next = current &^ 0o111
+	case "+w":
+		next = current | 0o200
+	case "-w":
+		next = current &^ 0o222
+	default:
+		v, err := strconv.ParseUint(strings.TrimPrefix(strings.TrimPrefix(mode, "0o"), "0"), 8, 32)
+		if err != nil {
+			return 0, fmt.Errorf("invalid mode %q: use +x, -x, +w, -w or an octal mode such as 755", mode)
+		}
+		if v > 0o777 {
+			return 0, fmt.Errorf("mode %s is not allowed: setuid, setgid and sticky bits cannot be set", mode)
+		}
+		next = fs.FileMode(v)
+	}
+	if next&0o002 != 0 {
+		return 0, fmt.Errorf("mode %s is not allowed: files cannot be made world-writable", next)
+	}
+	if next&0o400 == 0 {
+		return 0, fmt.Errorf("mode %s is not allowed: the owner must keep read permission", next)
+	}
+	return next, nil
+}
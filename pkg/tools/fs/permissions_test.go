@@ -0,0 +1,111 @@
+package fstools
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func skipWithoutUnixPermissions(t *testing.T) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("unix permission bits are not supported on windows")
+	}
+}
+
+func TestResolvePermissionMode(t *testing.T) {
+	tests := []struct {
+		current fs.FileMode
+		mode    string
+		want    fs.FileMode
+	}{
+		{0o644, "+x", 0o755},
+		{0o600, "+x", 0o700},
+		{0o640, "+x", 0o750},
+		{0o755, "-x", 0o644},
+		{0o444, "+w", 0o644},
+		{0o664, "-w", 0o444},
+		{0o600, "755", 0o755},
+		{0o600, "0644", 0o644},
+		{0o600, "0o700", 0o700},
+	}
+	for _, tt := range tests {
+		got, err := resolvePermissionMode(tt.current, tt.mode)
+		require.NoError(t, err, tt.mode)
+		assert.Equal(t, tt.want, got, "%s from %s", tt.mode, tt.current)
+	}
+
+	for _, mode := range []string{"4755", "1777", "777", "666", "077", "u+x", "abc"} {
+		_, err := resolvePermissionMode(0o644, mode)
+		assert.Error(t, err, mode)
+	}
+}
+
+func TestSetPermissionsTool_MakesScriptExecutable(t *testing.T) {
+	skipWithoutUnixPermissions(t)
+	workspace := t.TempDir()
+	script := filepath.Join(workspace, "run.sh")
+	require.NoError(t, os.WriteFile(script, []byte("#!/bin/sh\necho hi\n"), 0o644))
+
+	tool := NewSetPermissionsTool(workspace, true)
+	result := tool.Execute(context.Background(), map[string]any{"path": "run.sh", "mode": "+x"})
+	require.False(t, result.IsError, result.ForLLM)
+	assert.Contains(t, result.ForLLM, "-rw-r--r-- -> -rwxr-xr-x")
+
+	info, err := os.Stat(script)
+	require.NoError(t, err)
+	assert.Equal(t, fs.FileMode(0o755), info.Mode().Perm())
+}
+
+func TestSetPermissionsTool_RejectsUnsafeTargets(t *testing.T) {
+	skipWithoutUnixPermissions(t)
+	workspace := t.TempDir()
+	outside := filepath.Join(t.TempDir(), "secret")
+	require.NoError(t, os.WriteFile(outside, []byte("x"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(workspace, "a.txt"), []byte("x"), 0o644))
+	if err := os.Symlink(outside, filepath.Join(workspace, "link")); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+
+	tool := NewSetPermissionsTool(workspace, true)
+	tests := []struct {
+		name string
+		args map[string]any
+		want string
+	}{
+		{"symlink", map[string]any{"path": "link", "mode": "+x"}, "symlink"},
+		{"outside workspace", map[string]any{"path": outside, "mode": "+x"}, "escapes workspace"},
+		{"world writable", map[string]any{"path": "a.txt", "mode": "666"}, "world-writable"},
+		{"setuid", map[string]any{"path": "a.txt", "mode": "4755"}, "setuid"},
+		{"missing mode", map[string]any{"path": "a.txt"}, "mode is required"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := tool.Execute(context.Background(), tt.args)
+			require.True(t, result.IsError)
+			assert.Contains(t, result.ForLLM, tt.want)
+		})
+	}
+
+	info, err := os.Stat(outside)
+	require.NoError(t, err)
+	assert.Equal(t, fs.FileMode(0o600), info.Mode().Perm())
+}
+
+func TestSetPermissionsTool_ReadOnlyPolicy(t *testing.T) {
+	skipWithoutUnixPermissions(t)
+	workspace := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(workspace, "locked.sh"), []byte("x"), 0o644))
+
+	tool := NewSetPermissionsTool(workspace, true)
+	ApplyFilePolicy(tool, NewFilePolicy(workspace, []string{"locked.sh"}, nil))
+	result := tool.Execute(context.Background(), map[string]any{"path": "locked.sh", "mode": "+x"})
+	require.True(t, result.IsError)
+	assert.Contains(t, result.ForLLM, "read-only")
+}
@@ -0,0 +1,324 @@
+package fstools
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/sipeed/picoclaw/pkg/logger"
+)
+
+// FilePolicy protects files from the filesystem tools independently of the
+// workspace sandbox, so it also applies when restrict_to_workspace is off.
+//
+// Patterns use the find_files glob syntax: a pattern without "/" matches a
+// base name anywhere (`*.env`), one with "/" matches a workspace-relative
+// path (`secrets/**`), and one starting with "/" matches an absolute path.
+// A pattern that matches a directory also covers everything beneath it.
+type FilePolicy struct {
+	workspace string
+	resolved  string
+	readOnly  []string
+	deny      []string
+}
+
+// NewFilePolicy compiles the read-only and deny globs for an agent workspace.
+// Invalid patterns are logged and skipped. It returns nil when no rules remain.
+func NewFilePolicy(workspace string, readOnly, deny []string) *FilePolicy {
+	p := &FilePolicy{
+		workspace: filepath.Clean(workspace),
+		readOnly:  cleanPolicyPatterns(readOnly),
+		deny:      cleanPolicyPatterns(deny),
+	}
+	if len(p.readOnly) == 0 && len(p.deny) == 0 {
+		return nil
+	}
+	p.resolved = p.workspace
+	if resolved, err := filepath.EvalSymlinks(p.workspace); err == nil {
+		p.resolved = resolved
+	}
+	return p
+}
+
+func cleanPolicyPatterns(patterns []string) []string {
+	out := make([]string, 0, len(patterns))
+	for _, pattern := range patterns {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" {
+			continue
+		}
+		if strings.HasPrefix(pattern, "~/") {
+			if home, err := os.UserHomeDir(); err == nil {
+				pattern = filepath.Join(home, pattern[2:])
+			}
+		}
+		pattern = filepath.ToSlash(pattern)
+		pattern = strings.TrimPrefix(pattern, "./")
+		pattern = strings.TrimSuffix(pattern, "/")
+		if _, err := path.Match(pattern, ""); err != nil {
+			logger.WarnCF("tool", "invalid file policy pattern", map[string]any{
+				"pattern": pattern,
+				"error":   err.Error(),
+			})
+			continue
+		}
+		out = append(out, pattern)
+	}
+	return out
+}
+
+// checkAccess returns an error when path is denied. Relative paths are
+// resolved against the workspace, or against the working directory when
+// hostRelative is set. A nil policy allows everything.
+func (p *FilePolicy) checkAccess(path string, hostRelative bool) error {
+	if p != nil && p.matches(p.deny, p.absolute(path, hostRelative)) {
+		return fmt.Errorf("access denied: %s is protected by the file policy: %w", path, fs.ErrPermission)
+	}
+	return nil
+}
+
+// checkWrite returns an error when path is denied or read-only.
+func (p *FilePolicy) checkWrite(path string, hostRelative bool) error {
+	if err := p.checkAccess(path, hostRelative); err != nil {
+		return err
+	}
+	if p != nil && p.matches(p.readOnly, p.absolute(path, hostRelative)) {
+		return fmt.Errorf("access denied: %s is read-only under the file policy: %w", path, fs.ErrPermission)
+	}
+	return nil
+}
+
+func (p *FilePolicy) absolute(path string, hostRelative bool) string {
+	return absoluteToolPath(p.workspace, path, hostRelative)
+}
+
+// absoluteToolPath resolves a path the way the fileSystem a tool uses would:
+// relative paths are taken from the workspace, or from the working directory
+// for hostFs.
+func absoluteToolPath(workspace, path string, hostRelative bool) string {
+	if filepath.IsAbs(path) {
+		return filepath.Clean(path)
+	}
+	if hostRelative {
+		if abs, err := filepath.Abs(path); err == nil {
+			return abs
+		}
+	}
+	return filepath.Join(workspace, path)
+}
+
+// matches checks both the path as given and its symlink-resolved form, so a
+// link inside the workspace cannot be used to reach a protected file.
+func (p *FilePolicy) matches(patterns []string, abs string) bool {
+	if len(patterns) == 0 {
+		return false
+	}
+	if p.matchPath(patterns, abs, p.workspace) {
+		return true
+	}
+	resolved := resolveExistingPrefix(abs)
+	return resolved != abs && p.matchPath(patterns, resolved, p.resolved)
+}
+
+func (p *FilePolicy) matchPath(patterns []string, abs, workspace string) bool {
+	slashAbs := filepath.ToSlash(abs)
+	rel, err := filepath.Rel(workspace, abs)
+	inside := err == nil && (rel == "." || filepath.IsLocal(rel))
+	rel = filepath.ToSlash(rel)
+	for _, pattern := range patterns {
+		// Inside the workspace only workspace-relative names are matched, so
+		// directories above it can never cover the whole workspace.
+		target := rel
+		switch {
+		case strings.HasPrefix(pattern, "/"):
+			target = slashAbs
+		case !inside && strings.Contains(pattern, "/"):
+			continue
+		case !inside:
+			target = slashAbs
+		}
+		for candidate := target; candidate != "." && candidate != "/" && candidate != ""; {
+			if matchGlob(pattern, candidate) {
+				return true
+			}
+			parent := path.Dir(candidate)
+			if parent == candidate {
+				break
+			}
+			candidate = parent
+		}
+	}
+	return false
+}
+
+// resolveExistingPrefix evaluates symlinks on the longest existing prefix of
+// abs and re-appends the missing tail, so files about to be created are
+// checked against where they would actually land.
+func resolveExistingPrefix(abs string) string {
+	var tail []string
+	dir := abs
+	for {
+		if resolved, err := filepath.EvalSymlinks(dir); err == nil {
+			return filepath.Join(append([]string{resolved}, tail...)...)
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return abs
+		}
+		tail = append([]string{filepath.Base(dir)}, tail...)
+		dir = parent
+	}
+}
+
+// policyFs enforces a FilePolicy on top of another fileSystem. Denied paths
+// fail every operation and are left out of directory listings; read-only
+// paths fail writes and deletes.
+type policyFs struct {
+	inner  fileSystem
+	policy *FilePolicy
+	// hostRelative resolves relative paths against the process working
+	// directory, matching what hostFs does with them.
+	hostRelative bool
+}
+
+func newPolicyFs(inner fileSystem, policy *FilePolicy) *policyFs {
+	return &policyFs{inner: inner, policy: policy, hostRelative: isHostRelative(inner)}
+}
+
+// isHostRelative reports whether sysFs resolves relative paths against the
+// working directory rather than the workspace.
+func isHostRelative(sysFs fileSystem) bool {
+	switch f := sysFs.(type) {
+	case *hostFs:
+		return true
+	case *policyFs:
+		return f.hostRelative
+	case *backupFs:
+		return f.hostRelative
+	case *observedFs:
+		return f.hostRelative
+	}
+	return false
+}
+
+func (p *policyFs) checkAccess(path string) error {
+	return p.policy.checkAccess(path, p.hostRelative)
+}
+
+func (p *policyFs) checkWrite(path string) error {
+	return p.policy.checkWrite(path, p.hostRelative)
+}
+
+func (p *policyFs) ReadFile(path string) ([]byte, error) {
+	if err := p.checkAccess(path); err != nil {
+		return nil, err
+	}
+	return p.inner.ReadFile(path)
+}
+
+func (p *policyFs) WriteFile(path string, data []byte) error {
+	if err := p.checkWrite(path); err != nil {
+		return err
+	}
+	return p.inner.WriteFile(path, data)
+}
+
+func (p *policyFs) ReadDir(path string) ([]os.DirEntry, error) {
+	if err := p.checkAccess(path); err != nil {
+		return nil, err
+	}
+	entries, err := p.inner.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+	visible := entries[:0]
+	for _, entry := range entries {
+		if p.checkAccess(filepath.Join(path, entry.Name())) == nil {
+			visible = append(visible, entry)
+		}
+	}
+	return visible, nil
+}
+
+func (p *policyFs) Open(path string) (fs.File, error) {
+	if err := p.checkAccess(path); err != nil {
+		return nil, err
+	}
+	return p.inner.Open(path)
+}
+
+func (p *policyFs) Remove(path string) error {
+	if err := p.checkWrite(path); err != nil {
+		return err
+	}
+	return p.inner.Remove(path)
+}
+
+func (p *policyFs) Lstat(path string) (fs.FileInfo, error) {
+	if err := p.checkAccess(path); err != nil {
+		return nil, err
+	}
+	return p.inner.Lstat(path)
+}
+
+func (p *policyFs) Chmod(path string, mode fs.FileMode) error {
+	if err := p.checkWrite(path); err != nil {
+		return err
+	}
+	return p.inner.Chmod(path, mode)
+}
+
+func (p *policyFs) MkdirAll(path string) error {
+	if err := p.checkWrite(path); err != nil {
+		return err
+	}
+	return p.inner.MkdirAll(path)
+}
+
+func (p *policyFs) Lock(paths ...string) (func(), error) {
+	return p.inner.Lock(paths...)
+}
+
+// policyTarget is implemented by the tools that a FilePolicy can restrict.
+type policyTarget interface {
+	applyFilePolicy(policy *FilePolicy)
+}
+
+// ApplyFilePolicy restricts a filesystem tool with policy. It reports false
+// for tools that do not touch local files, and is a no-op when policy is nil.
+func ApplyFilePolicy(tool any, policy *FilePolicy) bool {
+	target, ok := tool.(policyTarget)
+	if ok && policy != nil {
+		target.applyFilePolicy(policy)
+	}
+	return ok
+}
+
+func (t *ReadFileTool) applyFilePolicy(p *FilePolicy)       { t.fs = newPolicyFs(t.fs, p) }
+func (t *ReadFileLinesTool) applyFilePolicy(p *FilePolicy)  { t.fs = newPolicyFs(t.fs, p) }
+func (t *WriteFileTool) applyFilePolicy(p *FilePolicy)      { t.fs = newPolicyFs(t.fs, p) }
+func (t *ListDirTool) applyFilePolicy(p *FilePolicy)        { t.fs = newPolicyFs(t.fs, p) }
+func (t *EditFileTool) applyFilePolicy(p *FilePolicy)       { t.fs = newPolicyFs(t.fs, p) }
+func (t *AppendFileTool) applyFilePolicy(p *FilePolicy)     { t.fs = newPolicyFs(t.fs, p) }
+func (t *MultiEditTool) applyFilePolicy(p *FilePolicy)      { t.fs = newPolicyFs(t.fs, p) }
+func (t *ApplyPatchTool) applyFilePolicy(p *FilePolicy)     { t.fs = newPolicyFs(t.fs, p) }
+func (t *DeleteFileTool) applyFilePolicy(p *FilePolicy)     { t.fs = newPolicyFs(t.fs, p) }
+func (t *CopyFileTool) applyFilePolicy(p *FilePolicy)       { t.fs = newPolicyFs(t.fs, p) }
+func (t *ArchiveTool) applyFilePolicy(p *FilePolicy)        { t.fs = newPolicyFs(t.fs, p) }
+func (t *FindFilesTool) applyFilePolicy(p *FilePolicy)      { t.fs = newPolicyFs(t.fs, p) }
+func (t *GrepTool) applyFilePolicy(p *FilePolicy)           { t.fs = newPolicyFs(t.fs, p) }
+func (t *StatTool) applyFilePolicy(p *FilePolicy)           { t.fs = newPolicyFs(t.fs, p) }
+func (t *TailFileTool) applyFilePolicy(p *FilePolicy)       { t.fs = newPolicyFs(t.fs, p) }
+func (t *UndoFileTool) applyFilePolicy(p *FilePolicy)       { t.fs = newPolicyFs(t.fs, p) }
+func (t *DataQueryTool) applyFilePolicy(p *FilePolicy)      { t.fs = newPolicyFs(t.fs, p) }
+func (t *SetPermissionsTool) applyFilePolicy(p *FilePolicy) { t.fs = newPolicyFs(t.fs, p) }
+func (t *WriteSessionTool) applyFilePolicy(p *FilePolicy)   { t.fs = newPolicyFs(t.fs, p) }
+func (t *SnapshotTool) applyFilePolicy(p *FilePolicy)       { t.policy = p }
+
+// send_file and load_image read through os directly, so they check the
+// resolved path themselves.
+func (t *SendFileTool) applyFilePolicy(p *FilePolicy)  { t.policy = p }
+func (t *LoadImageTool) applyFilePolicy(p *FilePolicy) { t.policy = p }
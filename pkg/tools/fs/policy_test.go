@@ -0,0 +1,152 @@
+package fstools
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewFilePolicy_EmptyIsNil(t *testing.T) {
+	assert.Nil(t, NewFilePolicy(t.TempDir(), nil, []string{" ", ""}))
+	assert.Nil(t, NewFilePolicy(t.TempDir(), nil, []string{"[bad"}))
+}
+
+func TestFilePolicy_Matching(t *testing.T) {
+	workspace := t.TempDir()
+	policy := NewFilePolicy(workspace, []string{"docs/**"}, []string{"*.env", "secrets", "/etc/shadow"})
+	require.NotNil(t, policy)
+
+	tests := []struct {
+		path   string
+		denied bool
+		ro     bool
+	}{
+		{".env", true, true},
+		{"config/prod.env", true, true},
+		{"secrets/key.pem", true, true},
+		{"secrets", true, true},
+		{"notes/secrets.txt", false, false},
+		{"docs/guide.md", false, true},
+		{"docs", false, true},
+		{"README.md", false, false},
+		{".", false, false},
+		{"/etc/shadow", true, true},
+		{"/etc/passwd", false, false},
+		{filepath.Join(workspace, "app.env"), true, true},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.denied, policy.checkAccess(tt.path, false) != nil, "checkAccess(%q)", tt.path)
+		assert.Equal(t, tt.ro, policy.checkWrite(tt.path, false) != nil, "checkWrite(%q)", tt.path)
+	}
+}
+
+func TestFilePolicy_WorkspaceAncestorsNotMatched(t *testing.T) {
+	parent := t.TempDir()
+	workspace := filepath.Join(parent, "secrets", "ws")
+	require.NoError(t, os.MkdirAll(workspace, 0o755))
+
+	policy := NewFilePolicy(workspace, nil, []string{"secrets"})
+	assert.NoError(t, policy.checkAccess("notes.txt", false))
+	assert.Error(t, policy.checkAccess(filepath.Join(parent, "secrets", "other.txt"), false))
+}
+
+func TestPolicyFs_DeniesReadsAndHidesEntries(t *testing.T) {
+	for _, restrict := range []bool{true, false} {
+		workspace := t.TempDir()
+		writeFindFixture(t, workspace, map[string]string{
+			".env":           "TOKEN=1",
+			"secrets/id_rsa": "key",
+			"main.go":        "package main",
+		})
+		policy := NewFilePolicy(workspace, nil, []string{"*.env", "secrets/**"})
+		sysFs := newPolicyFs(buildFs(workspace, restrict, nil), policy)
+
+		_, err := sysFs.ReadFile(filepath.Join(workspace, ".env"))
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, fs.ErrPermission))
+		_, err = sysFs.Open(filepath.Join(workspace, "secrets", "id_rsa"))
+		assert.Error(t, err)
+		_, err = sysFs.Lstat(filepath.Join(workspace, "secrets"))
+		assert.Error(t, err)
+
+		entries, err := sysFs.ReadDir(workspace)
+		require.NoError(t, err)
+		var names []string
+		for _, entry := range entries {
+			names = append(names, entry.Name())
+		}
+		assert.Equal(t, []string{"main.go"}, names, "restrict=%v", restrict)
+	}
+}
+
+func TestPolicyFs_ReadOnlyAllowsReads(t *testing.T) {
+	workspace := t.TempDir()
+	writeFindFixture(t, workspace, map[string]string{"vendor/lib.go": "package lib"})
+	policy := NewFilePolicy(workspace, []string{"vendor/**"}, nil)
+	sysFs := newPolicyFs(buildFs(workspace, true, nil), policy)
+
+	data, err := sysFs.ReadFile("vendor/lib.go")
+	require.NoError(t, err)
+	assert.Equal(t, "package lib", string(data))
+
+	err = sysFs.WriteFile("vendor/lib.go", []byte("changed"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "read-only")
+	assert.Error(t, sysFs.WriteFile("vendor/new.go", []byte("new")))
+	assert.Error(t, sysFs.Remove("vendor/lib.go"))
+	assert.NoError(t, sysFs.WriteFile("main.go", []byte("package main")))
+}
+
+func TestPolicyFs_SymlinkIntoDeniedDir(t *testing.T) {
+	workspace := t.TempDir()
+	writeFindFixture(t, workspace, map[string]string{"secrets/token": "s3cret"})
+	if err := os.Symlink(filepath.Join(workspace, "secrets"), filepath.Join(workspace, "link")); err != nil {
+		t.Skipf("symlinks unavailable: %v", err)
+	}
+	policy := NewFilePolicy(workspace, nil, []string{"secrets/**"})
+	sysFs := newPolicyFs(&hostFs{}, policy)
+
+	_, err := sysFs.ReadFile(filepath.Join(workspace, "link", "token"))
+	assert.Error(t, err)
+}
+
+func TestApplyFilePolicy_Tools(t *testing.T) {
+	workspace := t.TempDir()
+	writeFindFixture(t, workspace, map[string]string{
+		".env":      "TOKEN=1",
+		"notes.txt": "TOKEN=2",
+	})
+	policy := NewFilePolicy(workspace, []string{"notes.txt"}, []string{"*.env"})
+
+	read := NewReadFileTool(workspace, false, MaxReadFileSize)
+	assert.True(t, ApplyFilePolicy(read, policy))
+	result := read.Execute(context.Background(), map[string]any{"path": filepath.Join(workspace, ".env")})
+	require.True(t, result.IsError)
+	assert.Contains(t, result.ForLLM, "file policy")
+
+	write := NewWriteFileTool(workspace, false)
+	ApplyFilePolicy(write, policy)
+	result = write.Execute(context.Background(), map[string]any{
+		"path":      filepath.Join(workspace, "notes.txt"),
+		"content":   "changed",
+		"overwrite": true,
+	})
+	require.True(t, result.IsError)
+	assertFileContent(t, filepath.Join(workspace, "notes.txt"), "TOKEN=2")
+
+	grep := NewGrepTool(workspace, true)
+	ApplyFilePolicy(grep, policy)
+	result = grep.Execute(context.Background(), map[string]any{"pattern": "TOKEN"})
+	require.False(t, result.IsError, result.ForLLM)
+	assert.Contains(t, result.ForLLM, "notes.txt")
+	assert.False(t, strings.Contains(result.ForLLM, ".env"), result.ForLLM)
+
+	assert.False(t, ApplyFilePolicy(struct{}{}, policy))
+}
@@ -0,0 +1,327 @@
+package fstools
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	// ScratchDirName is the workspace directory that holds scratch areas.
+	ScratchDirName = "scratch"
+
+	DefaultScratchMaxAge  = 24 * time.Hour
+	DefaultScratchMaxSize = 512 * 1024 * 1024
+
+	// scratchMarkerName marks a directory the scratch tool created. Only
+	// marked directories are listed, deleted or cleaned up, so files a user
+	// already kept in a scratch/ folder are never touched.
+	scratchMarkerName = ".picoclaw-scratch"
+)
+
+var scratchNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,63}$`)
+
+// ScratchTool hands out named temporary directories under
+// <workspace>/scratch and removes them again under an age and total size
+// policy. Cleanup runs on create, list and cleanup calls and when the agent
+// starts, so the area stays bounded without a background job. Entries the
+// tool did not create are left alone. All operations
+// go through os.Root, so a symlink planted in the scratch area cannot
+// redirect a deletion outside the workspace.
+type ScratchTool struct {
+	workspace string
+	maxAge    time.Duration
+	maxSize   int64
+	now       func() time.Time
+}
+
+// NewScratchTool creates a ScratchTool for workspace. Non-positive limits
+// fall back to DefaultScratchMaxAge and DefaultScratchMaxSize.
+func NewScratchTool(workspace string, maxAge time.Duration, maxSize int64) *ScratchTool {
+	if maxAge <= 0 {
+		maxAge = DefaultScratchMaxAge
+	}
+	if maxSize <= 0 {
+		maxSize = DefaultScratchMaxSize
+	}
+	return &ScratchTool{workspace: workspace, maxAge: maxAge, maxSize: maxSize, now: time.Now}
+}
+
+func (t *ScratchTool) Name() string {
+	return "scratch"
+}
+
+func (t *ScratchTool) Description() string {
+	return fmt.Sprintf(
+		"Manage temporary working directories under %s/ in the workspace. Use action=create to get a directory for intermediate files (downloads, build output, extracted archives) instead of writing them next to the user's files. Scratch directories unused for %s are deleted automatically, and the oldest are deleted when the area grows past %d MB.",
+		ScratchDirName, t.maxAge, t.maxSize/(1024*1024),
+	)
+}
+
+func (t *ScratchTool) Parameters() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"action": map[string]any{
+				"type":        "string",
+				"enum":        []string{"create", "list", "delete", "cleanup"},
+				"description": "create a directory, list existing ones, delete one, or apply the cleanup policy now",
+			},
+			"name": map[string]any{
+				"type": "string",
+				"description": "Directory name for create and delete: letters, digits, '.', '_' and '-'. " +
+					"create picks a unique name when omitted",
+			},
+		},
+		"required": []string{"action"},
+	}
+}
+
+// scratchEntry summarizes one top-level entry of the scratch area.
+type scratchEntry struct {
+	name     string
+	size     int64
+	files    int
+	modified time.Time
+}
+
+func (t *ScratchTool) Execute(ctx context.Context, args map[string]any) *ToolResult {
+	action, _ := args["action"].(string)
+	name, _ := args["name"].(string)
+	name = strings.TrimSpace(name)
+	if name != "" && !scratchNamePattern.MatchString(name) {
+		return ErrorResult(fmt.Sprintf(
+			"invalid name %q: use up to 64 letters, digits, '.', '_' or '-', starting with a letter or digit", name,
+		))
+	}
+
+	switch action {
+	case "create":
+		return t.create(name)
+	case "list":
+		removed, err := t.Cleanup()
+		if err != nil {
+			return ErrorResult(err.Error())
+		}
+		return t.list(removed)
+	case "delete":
+		if name == "" {
+			return ErrorResult("name is required for delete")
+		}
+		return t.delete(name)
+	case "cleanup":
+		removed, err := t.Cleanup()
+		if err != nil {
+			return ErrorResult(err.Error())
+		}
+		if len(removed) == 0 {
+			return SilentResult("Scratch cleanup: nothing to remove")
+		}
+		return SilentResult(fmt.Sprintf("Scratch cleanup removed: %s", strings.Join(removed, ", ")))
+	case "":
+		return ErrorResult("action is required")
+	}
+	return ErrorResult(fmt.Sprintf("unknown action %q: use create, list, delete or cleanup", action))
+}
+
+func (t *ScratchTool) create(name string) *ToolResult {
+	if name == "" {
+		suffix := make([]byte, 3)
+		_, _ = rand.Read(suffix)
+		name = fmt.Sprintf("tmp-%s-%s", t.now().Format("20060102-150405"), hex.EncodeToString(suffix))
+	}
+	rel := path.Join(ScratchDirName, name)
+
+	root, err := os.OpenRoot(t.workspace)
+	if err != nil {
+		return ErrorResult(fmt.Sprintf("failed to open workspace: %v", err))
+	}
+	defer root.Close()
+
+	existed := false
+	if info, err := root.Lstat(rel); err == nil {
+		if !info.IsDir() {
+			return ErrorResult(fmt.Sprintf("%s exists and is not a directory", rel))
+		}
+		if !scratchOwned(root, name) {
+			return ErrorResult(fmt.Sprintf(
+				"%s exists but was not created by the scratch tool; choose another name", rel))
+		}
+		existed = true
+	}
+	if err := root.MkdirAll(rel, 0o755); err != nil {
+		return ErrorResult(fmt.Sprintf("failed to create %s: %v", rel, err))
+	}
+	if !existed {
+		if err := root.WriteFile(path.Join(rel, scratchMarkerName), nil, 0o644); err != nil {
+			return ErrorResult(fmt.Sprintf("failed to mark %s: %v", rel, err))
+		}
+	}
+	// Refresh the timestamp so reusing a directory counts as activity.
+	now := t.now()
+	_ = root.Chtimes(rel, now, now)
+
+	removed, err := t.cleanup(root, name)
+	if err != nil {
+		return ErrorResult(err.Error())
+	}
+
+	verb := "Created"
+	if existed {
+		verb = "Reusing"
+	}
+	msg := fmt.Sprintf("%s scratch directory %s (%s)", verb, rel, filepath.Join(t.workspace, rel))
+	if len(removed) > 0 {
+		msg += fmt.Sprintf("\nExpired scratch directories removed: %s", strings.Join(removed, ", "))
+	}
+	return SilentResult(msg)
+}
+
+func (t *ScratchTool) list(removed []string) *ToolResult {
+	root, err := os.OpenRoot(t.workspace)
+	if err != nil {
+		return ErrorResult(fmt.Sprintf("failed to open workspace: %v", err))
+	}
+	defer root.Close()
+
+	entries, err := scanScratch(root)
+	if err != nil {
+		return ErrorResult(err.Error())
+	}
+	var sb strings.Builder
+	if len(entries) == 0 {
+		sb.WriteString("No scratch directories")
+	} else {
+		var total int64
+		for _, e := range entries {
+			total += e.size
+		}
+		fmt.Fprintf(&sb, "%d scratch entries, %s total (limit %s, expire after %s):",
+			len(entries), formatFileSize(total), formatFileSize(t.maxSize), t.maxAge)
+		for _, e := range entries {
+			fmt.Fprintf(&sb, "\n%s/%s  %s, %d files, last modified %s",
+				ScratchDirName, e.name, formatFileSize(e.size), e.files, e.modified.Format(time.RFC3339))
+		}
+	}
+	if len(removed) > 0 {
+		fmt.Fprintf(&sb, "\nExpired scratch directories removed: %s", strings.Join(removed, ", "))
+	}
+	return SilentResult(sb.String())
+}
+
+func (t *ScratchTool) delete(name string) *ToolResult {
+	root, err := os.OpenRoot(t.workspace)
+	if err != nil {
+		return ErrorResult(fmt.Sprintf("failed to open workspace: %v", err))
+	}
+	defer root.Close()
+
+	rel := path.Join(ScratchDirName, name)
+	if _, err := root.Lstat(rel); errors.Is(err, fs.ErrNotExist) {
+		return ErrorResult(fmt.Sprintf("%s does not exist", rel))
+	}
+	if !scratchOwned(root, name) {
+		return ErrorResult(fmt.Sprintf("%s was not created by the scratch tool; use delete_file instead", rel))
+	}
+	if err := root.RemoveAll(rel); err != nil {
+		return ErrorResult(fmt.Sprintf("failed to delete %s: %v", rel, err))
+	}
+	return SilentResult(fmt.Sprintf("Deleted %s", rel))
+}
+
+// Cleanup applies the age and size policy to the scratch area and returns the
+// names of the removed entries.
+func (t *ScratchTool) Cleanup() ([]string, error) {
+	root, err := os.OpenRoot(t.workspace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open workspace: %w", err)
+	}
+	defer root.Close()
+	return t.cleanup(root, "")
+}
+
+// cleanup removes entries idle for longer than maxAge, then the least recently
+// modified ones until the area fits in maxSize. keep is never removed.
+func (t *ScratchTool) cleanup(root *os.Root, keep string) ([]string, error) {
+	entries, err := scanScratch(root)
+	if err != nil {
+		return nil, err
+	}
+	cutoff := t.now().Add(-t.maxAge)
+	var total int64
+	for _, e := range entries {
+		total += e.size
+	}
+
+	// Oldest first, so the size pass removes the least recently used entries.
+	slices.SortFunc(entries, func(a, b scratchEntry) int { return a.modified.Compare(b.modified) })
+	var removed []string
+	for _, e := range entries {
+		if e.name == keep || (!e.modified.Before(cutoff) && total <= t.maxSize) {
+			continue
+		}
+		if err := root.RemoveAll(path.Join(ScratchDirName, e.name)); err != nil {
+			return removed, fmt.Errorf("failed to remove scratch entry %s: %w", e.name, err)
+		}
+		total -= e.size
+		removed = append(removed, e.name)
+	}
+	return removed, nil
+}
+
+// scratchOwned reports whether the scratch entry name carries the marker the
+// tool writes into the directories it creates.
+func scratchOwned(root *os.Root, name string) bool {
+	info, err := root.Lstat(path.Join(ScratchDirName, name, scratchMarkerName))
+	return err == nil && info.Mode().IsRegular()
+}
+
+// scanScratch measures every top-level entry of the scratch area that the
+// tool created. An entry's modification time is the newest among everything
+// inside it.
+func scanScratch(root *os.Root) ([]scratchEntry, error) {
+	dirEntries, err := fs.ReadDir(root.FS(), ScratchDirName)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read scratch directory: %w", err)
+	}
+	entries := make([]scratchEntry, 0, len(dirEntries))
+	for _, de := range dirEntries {
+		if !de.IsDir() || !scratchOwned(root, de.Name()) {
+			continue
+		}
+		entry := scratchEntry{name: de.Name()}
+		_ = fs.WalkDir(root.FS(), path.Join(ScratchDirName, de.Name()), func(_ string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return nil
+			}
+			info, err := d.Info()
+			if err != nil {
+				return nil
+			}
+			if info.ModTime().After(entry.modified) {
+				entry.modified = info.ModTime()
+			}
+			if info.Mode().IsRegular() && d.Name() != scratchMarkerName {
+				entry.size += info.Size()
+				entry.files++
+			}
+			return nil
+		})
+		entries = append(entries, entry)
+	}
+	slices.SortFunc(entries, func(a, b scratchEntry) int { return strings.Compare(a.name, b.name) })
+	return entries, nil
+}
@@ -0,0 +1,152 @@
+package fstools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeScratchFile writes a file into a scratch directory marked as created
+// by the tool.
+func writeScratchFile(t *testing.T, workspace, rel string, size int, modTime time.Time) {
+	t.Helper()
+	full := filepath.Join(workspace, ScratchDirName, rel)
+	require.NoError(t, os.MkdirAll(filepath.Dir(full), 0o755))
+	require.NoError(t, os.WriteFile(full, make([]byte, size), 0o644))
+	top, _, _ := strings.Cut(filepath.ToSlash(rel), "/")
+	marker := filepath.Join(workspace, ScratchDirName, top, scratchMarkerName)
+	require.NoError(t, os.WriteFile(marker, nil, 0o644))
+	for _, p := range []string{full, marker, filepath.Dir(full)} {
+		require.NoError(t, os.Chtimes(p, modTime, modTime))
+	}
+}
+
+func TestScratchTool_CreateAndReuse(t *testing.T) {
+	workspace := t.TempDir()
+	tool := NewScratchTool(workspace, 0, 0)
+
+	result := tool.Execute(context.Background(), map[string]any{"action": "create", "name": "build"})
+	require.False(t, result.IsError, result.ForLLM)
+	assert.Contains(t, result.ForLLM, "Created scratch directory scratch/build")
+	assert.DirExists(t, filepath.Join(workspace, "scratch", "build"))
+
+	result = tool.Execute(context.Background(), map[string]any{"action": "create", "name": "build"})
+	require.False(t, result.IsError, result.ForLLM)
+	assert.Contains(t, result.ForLLM, "Reusing scratch directory scratch/build")
+
+	result = tool.Execute(context.Background(), map[string]any{"action": "create"})
+	require.False(t, result.IsError, result.ForLLM)
+	assert.Contains(t, result.ForLLM, "scratch/tmp-")
+}
+
+func TestScratchTool_RejectsUnsafeNames(t *testing.T) {
+	tool := NewScratchTool(t.TempDir(), 0, 0)
+	for _, name := range []string{"../escape", "a/b", ".hidden", strings.Repeat("x", 65)} {
+		result := tool.Execute(context.Background(), map[string]any{"action": "create", "name": name})
+		assert.True(t, result.IsError, name)
+		assert.Contains(t, result.ForLLM, "invalid name", name)
+	}
+}
+
+func TestScratchTool_CleanupRemovesExpired(t *testing.T) {
+	workspace := t.TempDir()
+	now := time.Now()
+	writeScratchFile(t, workspace, "old/out.txt", 10, now.Add(-48*time.Hour))
+	writeScratchFile(t, workspace, "fresh/out.txt", 10, now.Add(-time.Hour))
+
+	tool := NewScratchTool(workspace, 24*time.Hour, 0)
+	result := tool.Execute(context.Background(), map[string]any{"action": "cleanup"})
+	require.False(t, result.IsError, result.ForLLM)
+	assert.Contains(t, result.ForLLM, "removed: old")
+	assert.NoDirExists(t, filepath.Join(workspace, "scratch", "old"))
+	assert.DirExists(t, filepath.Join(workspace, "scratch", "fresh"))
+}
+
+func TestScratchTool_CleanupEnforcesSizeOldestFirst(t *testing.T) {
+	workspace := t.TempDir()
+	now := time.Now()
+	writeScratchFile(t, workspace, "a/data", 400, now.Add(-3*time.Hour))
+	writeScratchFile(t, workspace, "b/data", 400, now.Add(-2*time.Hour))
+	writeScratchFile(t, workspace, "c/data", 400, now.Add(-time.Hour))
+
+	tool := NewScratchTool(workspace, 24*time.Hour, 1000)
+	removed, err := tool.Cleanup()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a"}, removed)
+	assert.DirExists(t, filepath.Join(workspace, "scratch", "b"))
+}
+
+func TestScratchTool_CreateKeepsNewDirectory(t *testing.T) {
+	workspace := t.TempDir()
+	writeScratchFile(t, workspace, "keep/data", 400, time.Now().Add(-48*time.Hour))
+
+	tool := NewScratchTool(workspace, 24*time.Hour, 100)
+	result := tool.Execute(context.Background(), map[string]any{"action": "create", "name": "keep"})
+	require.False(t, result.IsError, result.ForLLM)
+	assert.FileExists(t, filepath.Join(workspace, "scratch", "keep", "data"))
+}
+
+func TestScratchTool_ListAndDelete(t *testing.T) {
+	workspace := t.TempDir()
+	writeScratchFile(t, workspace, "job/a.txt", 2048, time.Now())
+	tool := NewScratchTool(workspace, 0, 0)
+
+	result := tool.Execute(context.Background(), map[string]any{"action": "list"})
+	require.False(t, result.IsError, result.ForLLM)
+	assert.Contains(t, result.ForLLM, "scratch/job  2.0 KB, 1 files")
+
+	result = tool.Execute(context.Background(), map[string]any{"action": "delete", "name": "job"})
+	require.False(t, result.IsError, result.ForLLM)
+	assert.NoDirExists(t, filepath.Join(workspace, "scratch", "job"))
+
+	result = tool.Execute(context.Background(), map[string]any{"action": "delete", "name": "job"})
+	assert.True(t, result.IsError)
+}
+
+func TestScratchTool_LeavesUserDirectoriesAlone(t *testing.T) {
+	workspace := t.TempDir()
+	old := time.Now().Add(-48 * time.Hour)
+	user := filepath.Join(workspace, ScratchDirName, "notes")
+	require.NoError(t, os.MkdirAll(user, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(user, "todo.txt"), make([]byte, 400), 0o644))
+	require.NoError(t, os.Chtimes(filepath.Join(user, "todo.txt"), old, old))
+	require.NoError(t, os.Chtimes(user, old, old))
+
+	tool := NewScratchTool(workspace, 24*time.Hour, 100)
+	removed, err := tool.Cleanup()
+	require.NoError(t, err)
+	assert.Empty(t, removed)
+	assert.FileExists(t, filepath.Join(user, "todo.txt"))
+
+	result := tool.Execute(context.Background(), map[string]any{"action": "delete", "name": "notes"})
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.ForLLM, "not created by the scratch tool")
+	result = tool.Execute(context.Background(), map[string]any{"action": "create", "name": "notes"})
+	assert.True(t, result.IsError)
+	result = tool.Execute(context.Background(), map[string]any{"action": "list"})
+	assert.Contains(t, result.ForLLM, "No scratch directories")
+	assert.FileExists(t, filepath.Join(user, "todo.txt"))
+}
+
+func TestScratchTool_DeleteDoesNotFollowSymlink(t *testing.T) {
+	workspace := t.TempDir()
+	outside := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(outside, "keep.txt"), []byte("x"), 0o644))
+	require.NoError(t, os.MkdirAll(filepath.Join(workspace, "scratch"), 0o755))
+	if err := os.Symlink(outside, filepath.Join(workspace, "scratch", "link")); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+
+	// The marker is planted at the link target; it still must not be followed.
+	require.NoError(t, os.WriteFile(filepath.Join(outside, scratchMarkerName), nil, 0o644))
+
+	tool := NewScratchTool(workspace, 0, 0)
+	tool.Execute(context.Background(), map[string]any{"action": "delete", "name": "link"})
+	assert.FileExists(t, filepath.Join(outside, "keep.txt"))
+}
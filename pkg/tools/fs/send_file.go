@@ -23,6 +23,7 @@ type SendFileTool struct {
 	maxFileSize int
 	mediaStore  media.MediaStore
 	allowPaths  []*regexp.Regexp
+	policy      *FilePolicy
 
 	defaultChannel string
 	defaultChatID  string
@@ -109,6 +110,9 @@ func (t *SendFileTool) Execute(ctx context.Context, args map[string]any) *ToolRe
 	if err != nil {
 		return ErrorResult(fmt.Sprintf("invalid path: %v", err))
 	}
+	if err := t.policy.checkAccess(resolved, false); err != nil {
+		return ErrorResult(err.Error())
+	}
 
 	info, err := os.Stat(resolved)
 	if err != nil {
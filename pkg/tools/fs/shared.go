@@ -36,6 +36,10 @@ func DiffResult(path string, before, after []byte) *ToolResult {
 	return toolshared.DiffResult(path, before, after)
 }
 
+func DiffPreviewResult(path string, before, after []byte) *ToolResult {
+	return toolshared.DiffPreviewResult(path, before, after)
+}
+
 func MediaResult(forLLM string, mediaRefs []string) *ToolResult {
 	return toolshared.MediaResult(forLLM, mediaRefs)
 }
@@ -0,0 +1,551 @@
+package fstools
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultMaxSnapshots is the number of snapshots kept when the configured
+	// limit is not positive.
+	DefaultMaxSnapshots = 5
+
+	// maxSnapshotFileSize leaves larger files out of snapshots; restore does
+	// not touch them either.
+	maxSnapshotFileSize = 10 * 1024 * 1024
+	// maxSnapshotFiles aborts a snapshot of a workspace too large to copy.
+	maxSnapshotFiles = 20000
+	// maxSnapshotDiffLines caps the changes listed by the diff action.
+	maxSnapshotDiffLines = 100
+
+	snapshotDir        = "state/snapshots"
+	snapshotObjectsDir = snapshotDir + "/objects"
+)
+
+// snapshotSkippedDirs are never captured, at any depth.
+var snapshotSkippedDirs = map[string]bool{".git": true, "node_modules": true, ".venv": true, "__pycache__": true}
+
+// snapshotSkippedTopDirs hold agent state rather than user files.
+var snapshotSkippedTopDirs = map[string]bool{"state": true, "sessions": true, ScratchDirName: true}
+
+// SnapshotTool records the state of the workspace before a risky multi-step
+// task and rolls it back on request. A snapshot is a manifest of content
+// hashes under <workspace>/state/snapshots; file contents are stored once per
+// hash, so a new snapshot only copies the files that changed since the
+// previous ones. Restoring first takes a snapshot of the current state, so a
+// restore can itself be undone.
+type SnapshotTool struct {
+	workspace string
+	keep      int
+	policy    *FilePolicy
+	mu        sync.Mutex
+	now       func() time.Time
+}
+
+type snapshotManifest struct {
+	ID      string                  `json:"id"`
+	Label   string                  `json:"label,omitempty"`
+	Created time.Time               `json:"created"`
+	Files   map[string]snapshotFile `json:"files"`
+	// Skipped lists files too large to capture. Restore leaves them alone.
+	Skipped []string `json:"skipped,omitempty"`
+}
+
+type snapshotFile struct {
+	Hash string      `json:"hash"`
+	Size int64       `json:"size"`
+	Mode fs.FileMode `json:"mode"`
+}
+
+// NewSnapshotTool creates a SnapshotTool for workspace that keeps up to keep
+// snapshots.
+func NewSnapshotTool(workspace string, keep int) *SnapshotTool {
+	if keep <= 0 {
+		keep = DefaultMaxSnapshots
+	}
+	return &SnapshotTool{workspace: workspace, keep: keep, now: time.Now}
+}
+
+func (t *SnapshotTool) Name() string {
+	return "snapshot"
+}
+
+func (t *SnapshotTool) Description() string {
+	return fmt.Sprintf(
+		"Take a snapshot of the workspace files before a risky multi-step task, and roll back to it if the task goes wrong. "+
+			"action=create records the current state, diff shows what changed since a snapshot, restore rolls back "+
+			"(recreating changed and deleted files and removing new ones), list and delete manage snapshots. "+
+			"The newest %d snapshots are kept. .git, node_modules, state, sessions and scratch directories and files over %d MB are not included.",
+		t.keep, maxSnapshotFileSize/(1024*1024),
+	)
+}
+
+func (t *SnapshotTool) Parameters() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"action": map[string]any{
+				"type":        "string",
+				"enum":        []string{"create", "list", "diff", "restore", "delete"},
+				"description": "Snapshot operation to perform",
+			},
+			"id": map[string]any{
+				"type":        "string",
+				"description": "Snapshot ID for diff, restore and delete. Defaults to the newest snapshot for diff and restore.",
+			},
+			"label": map[string]any{
+				"type":        "string",
+				"description": "create: short description of what the snapshot protects",
+			},
+		},
+		"required": []string{"action"},
+	}
+}
+
+func (t *SnapshotTool) Execute(ctx context.Context, args map[string]any) *ToolResult {
+	action, _ := args["action"].(string)
+	id, _ := args["id"].(string)
+	label, _ := args["label"].(string)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	root, err := os.OpenRoot(t.workspace)
+	if err != nil {
+		return ErrorResult(fmt.Sprintf("failed to open workspace: %v", err))
+	}
+	defer root.Close()
+
+	switch action {
+	case "create":
+		manifest, stored, err := t.create(ctx, root, label)
+		if err != nil {
+			return ErrorResult(err.Error())
+		}
+		t.prune(root)
+		return SilentResult(fmt.Sprintf("Snapshot %s created: %d files, %d new file copies stored",
+			manifest.ID, len(manifest.Files), stored))
+	case "list":
+		return t.list(root)
+	case "diff":
+		manifest, err := t.load(root, id)
+		if err != nil {
+			return ErrorResult(err.Error())
+		}
+		return t.diff(ctx, root, manifest)
+	case "restore":
+		manifest, err := t.load(root, id)
+		if err != nil {
+			return ErrorResult(err.Error())
+		}
+		return t.restore(ctx, root, manifest)
+	case "delete":
+		if id == "" {
+			return ErrorResult("id is required for delete")
+		}
+		if _, err := t.load(root, id); err != nil {
+			return ErrorResult(err.Error())
+		}
+		if err := root.Remove(snapshotManifestPath(id)); err != nil {
+			return ErrorResult(fmt.Sprintf("failed to delete snapshot: %v", err))
+		}
+		t.collectObjects(root)
+		return SilentResult(fmt.Sprintf("Deleted snapshot %s", id))
+	case "":
+		return ErrorResult("action is required")
+	}
+	return ErrorResult(fmt.Sprintf("unknown action %q: use create, list, diff, restore or delete", action))
+}
+
+func snapshotManifestPath(id string) string {
+	return path.Join(snapshotDir, id+".json")
+}
+
+// scannedFile is a workspace file seen while capturing or comparing.
+type scannedFile struct {
+	snapshotFile
+	data []byte
+}
+
+// scan hashes every file a snapshot covers and passes it, with its contents,
+// to visit. It returns the files left out for being too large.
+func (t *SnapshotTool) scan(
+	ctx context.Context,
+	root *os.Root,
+	visit func(rel string, file scannedFile) error,
+) ([]string, error) {
+	var skipped []string
+	count := 0
+	err := fs.WalkDir(root.FS(), ".", func(rel string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if rel == "." {
+				return err
+			}
+			return nil
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if rel == "." {
+			return nil
+		}
+		if d.IsDir() {
+			if snapshotSkippedDirs[d.Name()] || (!strings.Contains(rel, "/") && snapshotSkippedTopDirs[rel]) ||
+				t.policy.checkAccess(t.absPath(rel), false) != nil {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() || t.policy.checkAccess(t.absPath(rel), false) != nil {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		if info.Size() > maxSnapshotFileSize {
+			skipped = append(skipped, rel)
+			return nil
+		}
+		count++
+		if count > maxSnapshotFiles {
+			return fmt.Errorf("workspace has more than %d files; snapshot aborted", maxSnapshotFiles)
+		}
+		data, err := root.ReadFile(rel)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", rel, err)
+		}
+		sum := sha256.Sum256(data)
+		return visit(rel, scannedFile{
+			snapshotFile: snapshotFile{Hash: hex.EncodeToString(sum[:]), Size: info.Size(), Mode: info.Mode().Perm()},
+			data:         data,
+		})
+	})
+	return skipped, err
+}
+
+func (t *SnapshotTool) absPath(rel string) string {
+	return filepath.Join(t.workspace, filepath.FromSlash(rel))
+}
+
+// create captures the workspace and returns the manifest and the number of
+// file contents that were not already stored.
+func (t *SnapshotTool) create(ctx context.Context, root *os.Root, label string) (*snapshotManifest, int, error) {
+	if err := root.MkdirAll(snapshotObjectsDir, 0o700); err != nil {
+		return nil, 0, fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+	suffix := make([]byte, 2)
+	_, _ = rand.Read(suffix)
+	created := t.now()
+	manifest := &snapshotManifest{
+		ID:      created.UTC().Format("20060102T150405Z") + "-" + hex.EncodeToString(suffix),
+		Label:   label,
+		Created: created,
+		Files:   make(map[string]snapshotFile),
+	}
+
+	stored := 0
+	skipped, err := t.scan(ctx, root, func(rel string, file scannedFile) error {
+		manifest.Files[rel] = file.snapshotFile
+		object := path.Join(snapshotObjectsDir, file.Hash)
+		if _, err := root.Lstat(object); err == nil {
+			return nil
+		}
+		if err := root.WriteFile(object, file.data, 0o600); err != nil {
+			return fmt.Errorf("failed to store %s: %w", rel, err)
+		}
+		stored++
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	manifest.Skipped = skipped
+
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+	if err := root.WriteFile(snapshotManifestPath(manifest.ID), data, 0o600); err != nil {
+		return nil, 0, fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	return manifest, stored, nil
+}
+
+// manifests returns every stored snapshot, newest first.
+func (t *SnapshotTool) manifests(root *os.Root) ([]*snapshotManifest, error) {
+	entries, err := fs.ReadDir(root.FS(), snapshotDir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshots: %w", err)
+	}
+	var manifests []*snapshotManifest
+	for _, entry := range entries {
+		id, ok := strings.CutSuffix(entry.Name(), ".json")
+		if !ok || entry.IsDir() {
+			continue
+		}
+		data, err := root.ReadFile(snapshotManifestPath(id))
+		if err != nil {
+			continue
+		}
+		var manifest snapshotManifest
+		if json.Unmarshal(data, &manifest) == nil && manifest.ID == id {
+			manifests = append(manifests, &manifest)
+		}
+	}
+	slices.SortFunc(manifests, func(a, b *snapshotManifest) int { return b.Created.Compare(a.Created) })
+	return manifests, nil
+}
+
+// load returns the snapshot with id, or the newest one when id is empty.
+func (t *SnapshotTool) load(root *os.Root, id string) (*snapshotManifest, error) {
+	manifests, err := t.manifests(root)
+	if err != nil {
+		return nil, err
+	}
+	if len(manifests) == 0 {
+		return nil, fmt.Errorf("no snapshots exist; create one first")
+	}
+	if id == "" {
+		return manifests[0], nil
+	}
+	for _, manifest := range manifests {
+		if manifest.ID == id {
+			return manifest, nil
+		}
+	}
+	return nil, fmt.Errorf("snapshot %q not found; use action=list to see the available IDs", id)
+}
+
+func (t *SnapshotTool) list(root *os.Root) *ToolResult {
+	manifests, err := t.manifests(root)
+	if err != nil {
+		return ErrorResult(err.Error())
+	}
+	if len(manifests) == 0 {
+		return SilentResult("No snapshots")
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%d snapshot(s), newest first:", len(manifests))
+	for _, manifest := range manifests {
+		fmt.Fprintf(&sb, "\n%s  %s  %d files", manifest.ID, manifest.Created.Format(time.RFC3339), len(manifest.Files))
+		if manifest.Label != "" {
+			fmt.Fprintf(&sb, "  %s", manifest.Label)
+		}
+	}
+	return SilentResult(sb.String())
+}
+
+// snapshotChanges compares the workspace with a snapshot.
+type snapshotChanges struct {
+	added, modified, deleted []string
+	current                  map[string]snapshotFile
+}
+
+func (t *SnapshotTool) compare(
+	ctx context.Context,
+	root *os.Root,
+	manifest *snapshotManifest,
+) (*snapshotChanges, error) {
+	changes := &snapshotChanges{current: make(map[string]snapshotFile)}
+	skipped, err := t.scan(ctx, root, func(rel string, file scannedFile) error {
+		changes.current[rel] = file.snapshotFile
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	ignored := make(map[string]bool)
+	for _, rel := range append(skipped, manifest.Skipped...) {
+		ignored[rel] = true
+	}
+	for rel, file := range changes.current {
+		want, ok := manifest.Files[rel]
+		switch {
+		case !ok && !ignored[rel]:
+			changes.added = append(changes.added, rel)
+		case ok && want.Hash != file.Hash:
+			changes.modified = append(changes.modified, rel)
+		}
+	}
+	for rel := range manifest.Files {
+		if _, ok := changes.current[rel]; !ok && !ignored[rel] {
+			changes.deleted = append(changes.deleted, rel)
+		}
+	}
+	slices.Sort(changes.added)
+	slices.Sort(changes.modified)
+	slices.Sort(changes.deleted)
+	return changes, nil
+}
+
+func (t *SnapshotTool) diff(ctx context.Context, root *os.Root, manifest *snapshotManifest) *ToolResult {
+	changes, err := t.compare(ctx, root, manifest)
+	if err != nil {
+		return ErrorResult(err.Error())
+	}
+	total := len(changes.added) + len(changes.modified) + len(changes.deleted)
+	if total == 0 {
+		return SilentResult(fmt.Sprintf("No changes since snapshot %s", manifest.ID))
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Changes since snapshot %s: %d added, %d modified, %d deleted",
+		manifest.ID, len(changes.added), len(changes.modified), len(changes.deleted))
+	lines := 0
+	for _, group := range []struct {
+		tag   string
+		paths []string
+	}{{"A", changes.added}, {"M", changes.modified}, {"D", changes.deleted}} {
+		for _, rel := range group.paths {
+			if lines == maxSnapshotDiffLines {
+				fmt.Fprintf(&sb, "\n... %d more", total-lines)
+				return SilentResult(sb.String())
+			}
+			fmt.Fprintf(&sb, "\n%s %s", group.tag, rel)
+			lines++
+		}
+	}
+	return SilentResult(sb.String())
+}
+
+func (t *SnapshotTool) restore(ctx context.Context, root *os.Root, manifest *snapshotManifest) *ToolResult {
+	changes, err := t.compare(ctx, root, manifest)
+	if err != nil {
+		return ErrorResult(err.Error())
+	}
+	if len(changes.added)+len(changes.modified)+len(changes.deleted) == 0 {
+		return SilentResult(fmt.Sprintf("Workspace already matches snapshot %s; nothing to restore", manifest.ID))
+	}
+
+	backup, _, err := t.create(ctx, root, "before restoring "+manifest.ID)
+	if err != nil {
+		return ErrorResult(fmt.Sprintf("failed to snapshot the current state before restoring: %v", err))
+	}
+
+	var protected []string
+	restored := 0
+	for _, rel := range append(changes.modified, changes.deleted...) {
+		if t.policy.checkWrite(t.absPath(rel), false) != nil {
+			protected = append(protected, rel)
+			continue
+		}
+		if err := t.restoreFile(root, rel, manifest.Files[rel]); err != nil {
+			return ErrorResult(fmt.Sprintf(
+				"%v (restore partially applied; snapshot %s holds the state from before the restore)", err, backup.ID,
+			))
+		}
+		restored++
+	}
+	removed := 0
+	for _, rel := range changes.added {
+		if t.policy.checkWrite(t.absPath(rel), false) != nil {
+			protected = append(protected, rel)
+			continue
+		}
+		unlock, err := lockPath(t.absPath(rel))
+		if err != nil {
+			return ErrorResult(err.Error())
+		}
+		err = root.Remove(rel)
+		unlock()
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return ErrorResult(fmt.Sprintf(
+				"failed to remove %s: %v (restore partially applied; snapshot %s holds the state from before the restore)",
+				rel, err, backup.ID,
+			))
+		}
+		removed++
+	}
+	t.prune(root)
+
+	msg := fmt.Sprintf("Restored snapshot %s: %d files restored, %d new files removed. "+
+		"The previous state was saved as snapshot %s.", manifest.ID, restored, removed, backup.ID)
+	if len(protected) > 0 {
+		msg += fmt.Sprintf("\nLeft unchanged because the file policy protects them: %s", strings.Join(protected, ", "))
+	}
+	return SilentResult(msg)
+}
+
+// restoreFile writes the snapshot version of rel through a temporary file, so
+// a failure never leaves it half written.
+func (t *SnapshotTool) restoreFile(root *os.Root, rel string, file snapshotFile) error {
+	data, err := root.ReadFile(path.Join(snapshotObjectsDir, file.Hash))
+	if err != nil {
+		return fmt.Errorf("failed to read the saved copy of %s: %w", rel, err)
+	}
+	if sum := sha256.Sum256(data); hex.EncodeToString(sum[:]) != file.Hash {
+		return fmt.Errorf("the saved copy of %s is corrupted", rel)
+	}
+
+	unlock, err := lockPath(t.absPath(rel))
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	if dir := path.Dir(rel); dir != "." {
+		if err := root.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("failed to create %s: %w", dir, err)
+		}
+	}
+	tmp := fmt.Sprintf("%s.snapshot-%d", rel, time.Now().UnixNano())
+	if err := root.WriteFile(tmp, data, file.Mode); err != nil {
+		_ = root.Remove(tmp)
+		return fmt.Errorf("failed to restore %s: %w", rel, err)
+	}
+	if err := root.Rename(tmp, rel); err != nil {
+		_ = root.Remove(tmp)
+		return fmt.Errorf("failed to restore %s: %w", rel, err)
+	}
+	return nil
+}
+
+// prune deletes the oldest snapshots beyond the limit and the stored file
+// contents no remaining snapshot refers to.
+func (t *SnapshotTool) prune(root *os.Root) {
+	manifests, err := t.manifests(root)
+	if err != nil || len(manifests) <= t.keep {
+		return
+	}
+	for _, manifest := range manifests[t.keep:] {
+		_ = root.Remove(snapshotManifestPath(manifest.ID))
+	}
+	t.collectObjects(root)
+}
+
+func (t *SnapshotTool) collectObjects(root *os.Root) {
+	manifests, err := t.manifests(root)
+	if err != nil {
+		return
+	}
+	referenced := make(map[string]bool)
+	for _, manifest := range manifests {
+		for _, file := range manifest.Files {
+			referenced[file.Hash] = true
+		}
+	}
+	entries, err := fs.ReadDir(root.FS(), snapshotObjectsDir)
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		if !referenced[entry.Name()] {
+			_ = root.Remove(path.Join(snapshotObjectsDir, entry.Name()))
+		}
+	}
+}
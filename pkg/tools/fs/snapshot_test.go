@@ -0,0 +1,150 @@
+package fstools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func runSnapshot(t *testing.T, tool *SnapshotTool, args map[string]any) *ToolResult {
+	t.Helper()
+	result := tool.Execute(context.Background(), args)
+	require.False(t, result.IsError, result.ForLLM)
+	return result
+}
+
+func snapshotID(t *testing.T, result *ToolResult) string {
+	t.Helper()
+	id, _, ok := strings.Cut(strings.TrimPrefix(result.ForLLM, "Snapshot "), " ")
+	require.True(t, ok, result.ForLLM)
+	return id
+}
+
+func TestSnapshotTool_RestoreRollsBackChanges(t *testing.T) {
+	workspace := t.TempDir()
+	write := func(rel, content string) {
+		t.Helper()
+		path := filepath.Join(workspace, rel)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+	write("main.go", "package main\n")
+	write("docs/readme.md", "v1\n")
+	write("state/memory.json", "{}")
+	tool := NewSnapshotTool(workspace, 0)
+
+	id := snapshotID(t, runSnapshot(t, tool, map[string]any{"action": "create", "label": "before refactor"}))
+
+	write("main.go", "package broken\n")
+	require.NoError(t, os.Remove(filepath.Join(workspace, "docs", "readme.md")))
+	write("new.txt", "scratch work")
+	write("state/memory.json", `{"changed":true}`)
+
+	result := runSnapshot(t, tool, map[string]any{"action": "diff"})
+	assert.Contains(t, result.ForLLM, "1 added, 1 modified, 1 deleted")
+	assert.Contains(t, result.ForLLM, "A new.txt")
+	assert.Contains(t, result.ForLLM, "M main.go")
+	assert.Contains(t, result.ForLLM, "D docs/readme.md")
+
+	result = runSnapshot(t, tool, map[string]any{"action": "restore", "id": id})
+	assert.Contains(t, result.ForLLM, "2 files restored, 1 new files removed")
+
+	data, err := os.ReadFile(filepath.Join(workspace, "main.go"))
+	require.NoError(t, err)
+	assert.Equal(t, "package main\n", string(data))
+	data, err = os.ReadFile(filepath.Join(workspace, "docs", "readme.md"))
+	require.NoError(t, err)
+	assert.Equal(t, "v1\n", string(data))
+	assert.NoFileExists(t, filepath.Join(workspace, "new.txt"))
+	data, err = os.ReadFile(filepath.Join(workspace, "state", "memory.json"))
+	require.NoError(t, err)
+	assert.Equal(t, `{"changed":true}`, string(data), "agent state is not part of a snapshot")
+
+	result = runSnapshot(t, tool, map[string]any{"action": "diff", "id": id})
+	assert.Contains(t, result.ForLLM, "No changes")
+
+	// The restore saved the state it replaced, so it can be undone.
+	result = runSnapshot(t, tool, map[string]any{"action": "list"})
+	assert.Contains(t, result.ForLLM, "2 snapshot(s)")
+	assert.Contains(t, result.ForLLM, "before restoring "+id)
+}
+
+func TestSnapshotTool_StoresUnchangedFilesOnce(t *testing.T) {
+	workspace := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(workspace, "a.txt"), []byte("a"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(workspace, "b.txt"), []byte("b"), 0o644))
+	tool := NewSnapshotTool(workspace, 0)
+
+	result := runSnapshot(t, tool, map[string]any{"action": "create"})
+	assert.Contains(t, result.ForLLM, "2 files, 2 new file copies stored")
+
+	require.NoError(t, os.WriteFile(filepath.Join(workspace, "b.txt"), []byte("b2"), 0o644))
+	result = runSnapshot(t, tool, map[string]any{"action": "create"})
+	assert.Contains(t, result.ForLLM, "2 files, 1 new file copies stored")
+}
+
+func TestSnapshotTool_PrunesOldestSnapshots(t *testing.T) {
+	workspace := t.TempDir()
+	file := filepath.Join(workspace, "a.txt")
+	tool := NewSnapshotTool(workspace, 2)
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	tool.now = func() time.Time { return now }
+
+	var ids []string
+	for i := range 3 {
+		require.NoError(t, os.WriteFile(file, []byte(strings.Repeat("x", i+1)), 0o644))
+		ids = append(ids, snapshotID(t, runSnapshot(t, tool, map[string]any{"action": "create"})))
+		now = now.Add(time.Minute)
+	}
+
+	result := runSnapshot(t, tool, map[string]any{"action": "list"})
+	assert.Contains(t, result.ForLLM, "2 snapshot(s)")
+	assert.NotContains(t, result.ForLLM, ids[0])
+	objects, err := os.ReadDir(filepath.Join(workspace, "state", "snapshots", "objects"))
+	require.NoError(t, err)
+	assert.Len(t, objects, 2, "contents only the pruned snapshot used are removed")
+
+	runSnapshot(t, tool, map[string]any{"action": "delete", "id": ids[1]})
+	result = tool.Execute(context.Background(), map[string]any{"action": "restore", "id": ids[1]})
+	require.True(t, result.IsError)
+	assert.Contains(t, result.ForLLM, "not found")
+}
+
+func TestSnapshotTool_RestoreSkipsProtectedFiles(t *testing.T) {
+	workspace := t.TempDir()
+	config := filepath.Join(workspace, "config.yaml")
+	require.NoError(t, os.WriteFile(config, []byte("v1"), 0o644))
+	tool := NewSnapshotTool(workspace, 0)
+	ApplyFilePolicy(tool, NewFilePolicy(workspace, []string{"config.yaml"}, nil))
+
+	id := snapshotID(t, runSnapshot(t, tool, map[string]any{"action": "create"}))
+	require.NoError(t, os.WriteFile(config, []byte("v2"), 0o644))
+
+	result := runSnapshot(t, tool, map[string]any{"action": "restore", "id": id})
+	assert.Contains(t, result.ForLLM, "file policy protects them: config.yaml")
+	data, err := os.ReadFile(config)
+	require.NoError(t, err)
+	assert.Equal(t, "v2", string(data))
+}
+
+func TestSnapshotTool_Errors(t *testing.T) {
+	tool := NewSnapshotTool(t.TempDir(), 0)
+
+	result := tool.Execute(context.Background(), map[string]any{"action": "restore"})
+	require.True(t, result.IsError)
+	assert.Contains(t, result.ForLLM, "no snapshots exist")
+
+	result = tool.Execute(context.Background(), map[string]any{"action": "delete"})
+	require.True(t, result.IsError)
+	assert.Contains(t, result.ForLLM, "id is required")
+
+	result = tool.Execute(context.Background(), map[string]any{"action": "rollback"})
+	require.True(t, result.IsError)
+	assert.Contains(t, result.ForLLM, "unknown action")
+}
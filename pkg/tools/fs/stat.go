@@ -0,0 +1,160 @@
+package fstools
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// maxStatLineCountSize bounds the files whose lines file_stat will count.
+const maxStatLineCountSize = 16 * 1024 * 1024
+
+// StatTool reports metadata about a path so the model can decide how to read
+// it before loading any content.
+type StatTool struct {
+	fs fileSystem
+}
+
+// NewStatTool creates a new StatTool with optional directory restriction.
+func NewStatTool(workspace string, restrict bool, allowPaths ...[]*regexp.Regexp) *StatTool {
+	var patterns []*regexp.Regexp
+	if len(allowPaths) > 0 {
+		patterns = allowPaths[0]
+	}
+	return &StatTool{fs: buildFs(workspace, restrict, patterns)}
+}
+
+func (t *StatTool) Name() string {
+	return "file_stat"
+}
+
+func (t *StatTool) Description() string {
+	return "Get metadata for a file or directory without reading it: type (file, directory, symlink), size, permissions, modification time, the line count of text files, and the entry count of directories. Use it to decide whether to read a file in full or in ranges."
+}
+
+func (t *StatTool) Parameters() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"path": map[string]any{
+				"type":        "string",
+				"description": "Path to inspect",
+			},
+		},
+		"required": []string{"path"},
+	}
+}
+
+func (t *StatTool) Execute(ctx context.Context, args map[string]any) *ToolResult {
+	path, ok := args["path"].(string)
+	if !ok || path == "" {
+		return ErrorResult("path is required")
+	}
+
+	linkInfo, err := t.fs.Lstat(path)
+	if err != nil {
+		return ErrorResult(err.Error())
+	}
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "path: %s\n", path)
+
+	info := linkInfo
+	kind := fileKind(linkInfo)
+	if linkInfo.Mode()&fs.ModeSymlink != 0 {
+		target, statErr := statPath(t.fs, path)
+		if statErr != nil {
+			fmt.Fprintf(&out, "type: symlink (target unavailable: %v)\n", statErr)
+			return NewToolResult(out.String())
+		}
+		info = target
+		kind = "symlink -> " + fileKind(target)
+	}
+
+	fmt.Fprintf(&out, "type: %s\n", kind)
+	fmt.Fprintf(&out, "size: %d bytes (%s)\n", info.Size(), formatFileSize(info.Size()))
+	fmt.Fprintf(&out, "mode: %s\n", info.Mode().Perm())
+	fmt.Fprintf(&out, "modified: %s\n", info.ModTime().Format(time.RFC3339))
+
+	switch {
+	case info.IsDir():
+		if entries, err := t.fs.ReadDir(path); err == nil {
+			fmt.Fprintf(&out, "entries: %d\n", len(entries))
+		}
+	case info.Mode().IsRegular():
+		out.WriteString(t.describeContent(path, info.Size()))
+	}
+	return NewToolResult(out.String())
+}
+
+func fileKind(info fs.FileInfo) string {
+	switch {
+	case info.Mode()&fs.ModeSymlink != 0:
+		return "symlink"
+	case info.IsDir():
+		return "directory"
+	case info.Mode().IsRegular():
+		return "file"
+	default:
+		return "special"
+	}
+}
+
+// describeContent reports whether a regular file is text or binary and, for
+// text files up to maxStatLineCountSize, how many lines it has.
+func (t *StatTool) describeContent(path string, size int64) string {
+	if size == 0 {
+		return "content: text\nlines: 0\n"
+	}
+	file, err := t.fs.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+
+	reader := bufio.NewReaderSize(file, 32*1024)
+	sample, _ := reader.Peek(512)
+	if isBinaryReadFileData(sample) {
+		return "content: binary\n"
+	}
+	if size > maxStatLineCountSize {
+		return fmt.Sprintf("content: text\nlines: not counted (file larger than %s)\n",
+			formatFileSize(maxStatLineCountSize))
+	}
+
+	lines, err := countLines(reader)
+	if err != nil {
+		return "content: text\n"
+	}
+	return fmt.Sprintf("content: text\nlines: %d\n", lines)
+}
+
+// countLines counts newline-terminated lines plus a final unterminated one.
+func countLines(r io.Reader) (int, error) {
+	buf := make([]byte, 32*1024)
+	count := 0
+	var last byte
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			count += bytes.Count(buf[:n], []byte{'\n'})
+			last = buf[n-1]
+		}
+		if errors.Is(err, io.EOF) {
+			if last != 0 && last != '\n' {
+				count++
+			}
+			return count, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package fstools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatTool_Restricted_TextFile(t *testing.T) {
+	workspace := t.TempDir()
+	writeFindFixture(t, workspace, map[string]string{"app.log": "one\ntwo\nthree"})
+
+	tool := NewStatTool(workspace, true)
+	result := tool.Execute(context.Background(), map[string]any{"path": "app.log"})
+
+	assert.False(t, result.IsError, "Expected success, got: %s", result.ForLLM)
+	assert.Contains(t, result.ForLLM, "type: file\n")
+	assert.Contains(t, result.ForLLM, "size: 13 bytes (13 B)\n")
+	assert.Contains(t, result.ForLLM, "modified: ")
+	assert.Contains(t, result.ForLLM, "content: text\nlines: 3\n")
+}
+
+func TestStatTool_BinaryFileAndDirectory(t *testing.T) {
+	workspace := t.TempDir()
+	writeFindFixture(t, workspace, map[string]string{
+		"data/blob.bin": "\x00\x01\x02\x03",
+		"data/a.txt":    "a\n",
+	})
+
+	tool := NewStatTool(workspace, true)
+	result := tool.Execute(context.Background(), map[string]any{"path": "data/blob.bin"})
+	assert.Contains(t, result.ForLLM, "content: binary\n")
+	assert.NotContains(t, result.ForLLM, "lines:")
+
+	result = tool.Execute(context.Background(), map[string]any{"path": "data"})
+	assert.False(t, result.IsError)
+	assert.Contains(t, result.ForLLM, "type: directory\n")
+	assert.Contains(t, result.ForLLM, "entries: 2\n")
+}
+
+func TestStatTool_Symlink(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("symlinks require elevated privileges on Windows")
+	}
+	workspace := t.TempDir()
+	writeFindFixture(t, workspace, map[string]string{"target.txt": "a\nb\n"})
+	require.NoError(t, os.Symlink("target.txt", filepath.Join(workspace, "link.txt")))
+
+	tool := NewStatTool(workspace, true)
+	result := tool.Execute(context.Background(), map[string]any{"path": "link.txt"})
+
+	assert.False(t, result.IsError, "Expected success, got: %s", result.ForLLM)
+	assert.Contains(t, result.ForLLM, "type: symlink -> file\n")
+	assert.Contains(t, result.ForLLM, "lines: 2\n")
+}
+
+func TestStatTool_NotFoundAndOutsideWorkspace(t *testing.T) {
+	workspace := t.TempDir()
+	tool := NewStatTool(workspace, true)
+
+	result := tool.Execute(context.Background(), map[string]any{"path": "missing.txt"})
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.ForLLM, "not found")
+
+	outside := filepath.Join(t.TempDir(), "secret.txt")
+	require.NoError(t, os.WriteFile(outside, []byte("secret"), 0o644))
+	result = tool.Execute(context.Background(), map[string]any{"path": outside})
+	assert.True(t, result.IsError)
+}
+
+func TestCountLines(t *testing.T) {
+	for input, want := range map[string]int{
+		"":           0,
+		"a":          1,
+		"a\n":        1,
+		"a\nb":       2,
+		"a\n\nb\n":   3,
+		"\n\n\n":     3,
+		"x\r\ny\r\n": 2,
+	} {
+		got, err := countLines(strings.NewReader(input))
+		require.NoError(t, err)
+		assert.Equal(t, want, got, "countLines(%q)", input)
+	}
+}
@@ -0,0 +1,251 @@
+package fstools
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+)
+
+const (
+	defaultTailLines = 20
+	tailChunkSize    = 8 * 1024
+)
+
+// TailFileTool returns the first or last lines or bytes of a file. Tail reads
+// seek from the end, so the cost does not grow with the size of a log file.
+type TailFileTool struct {
+	fs      fileSystem
+	maxSize int64
+}
+
+// NewTailFileTool creates a new TailFileTool with optional directory restriction.
+func NewTailFileTool(
+	workspace string,
+	restrict bool,
+	maxReadFileSize int,
+	allowPaths ...[]*regexp.Regexp,
+) *TailFileTool {
+	var patterns []*regexp.Regexp
+	if len(allowPaths) > 0 {
+		patterns = allowPaths[0]
+	}
+
+	maxSize := int64(maxReadFileSize)
+	if maxSize <= 0 {
+		maxSize = MaxReadFileSize
+	}
+
+	return &TailFileTool{
+		fs:      buildFs(workspace, restrict, patterns),
+		maxSize: maxSize,
+	}
+}
+
+func (t *TailFileTool) Name() string {
+	return "tail_file"
+}
+
+func (t *TailFileTool) Description() string {
+	return "Return the last (mode=tail, default) or first (mode=head) lines of a text file, or a number of bytes with `bytes`. Tail reads seek from the end of the file, which makes this the right tool for inspecting large or growing log files."
+}
+
+func (t *TailFileTool) Parameters() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"path": map[string]any{
+				"type":        "string",
+				"description": "Path to the file to read",
+			},
+			"mode": map[string]any{
+				"type":        "string",
+				"enum":        []string{"tail", "head"},
+				"description": "Read from the end (tail) or the start (head) of the file",
+				"default":     "tail",
+			},
+			"lines": map[string]any{
+				"type":        "integer",
+				"description": "Number of lines to return",
+				"default":     defaultTailLines,
+			},
+			"bytes": map[string]any{
+				"type":        "integer",
+				"description": "Return this many bytes instead of counting lines",
+			},
+		},
+		"required": []string{"path"},
+	}
+}
+
+func (t *TailFileTool) Execute(ctx context.Context, args map[string]any) *ToolResult {
+	path, ok := args["path"].(string)
+	if !ok || path == "" {
+		return ErrorResult("path is required")
+	}
+	mode, _ := args["mode"].(string)
+	if mode == "" {
+		mode = "tail"
+	}
+	if mode != "tail" && mode != "head" {
+		return ErrorResult(fmt.Sprintf("unknown mode %q; use tail or head", mode))
+	}
+	lines, err := getInt64Arg(args, "lines", defaultTailLines)
+	if err != nil {
+		return ErrorResult(err.Error())
+	}
+	if lines <= 0 {
+		return ErrorResult("lines must be > 0")
+	}
+	byteCount, err := getInt64Arg(args, "bytes", 0)
+	if err != nil {
+		return ErrorResult(err.Error())
+	}
+	if byteCount < 0 {
+		return ErrorResult("bytes must be >= 0")
+	}
+
+	file, err := t.fs.Open(path)
+	if err != nil {
+		return ErrorResult(err.Error())
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return ErrorResult(fmt.Sprintf("failed to stat file: %v", err))
+	}
+	if info.IsDir() {
+		return ErrorResult(fmt.Sprintf("failed to open file: path is a directory: %s", path))
+	}
+	size := info.Size()
+
+	var data []byte
+	var truncated bool
+	switch {
+	case byteCount > 0:
+		data, truncated, err = t.readBytes(file, mode, size, byteCount)
+	case mode == "head":
+		data, truncated, err = headLines(file, int(lines), t.maxSize)
+	default:
+		seeker, ok := file.(io.ReadSeeker)
+		if !ok {
+			return ErrorResult("file is not seekable; use read_file instead")
+		}
+		data, truncated, err = tailLines(seeker, size, int(lines), t.maxSize)
+	}
+	if err != nil {
+		return ErrorResult(fmt.Sprintf("failed to read file: %v", err))
+	}
+	if isBinaryReadFileData(data) {
+		return ErrorResult("file appears to be binary; use read_file for byte-based inspection")
+	}
+
+	edge := "last"
+	if mode == "head" {
+		edge = "first"
+	}
+	what := fmt.Sprintf("%s %d bytes", edge, len(data))
+	if byteCount == 0 {
+		n := bytes.Count(data, []byte{'\n'})
+		if len(data) > 0 && data[len(data)-1] != '\n' {
+			n++
+		}
+		what = fmt.Sprintf("%s %d lines", edge, n)
+	}
+	header := fmt.Sprintf("[file: %s | %s | total size: %d bytes]", filepath.Base(path), what, size)
+	if truncated {
+		header += fmt.Sprintf("\n[TRUNCATED - output limited to %d bytes.]", t.maxSize)
+	}
+	return NewToolResult(header + "\n\n" + string(data))
+}
+
+func (t *TailFileTool) readBytes(file io.Reader, mode string, size, count int64) ([]byte, bool, error) {
+	truncated := count > t.maxSize
+	count = min(count, t.maxSize)
+	if mode == "tail" {
+		seeker, ok := file.(io.Seeker)
+		if !ok {
+			return nil, false, errors.New("file is not seekable")
+		}
+		if _, err := seeker.Seek(max(size-count, 0), io.SeekStart); err != nil {
+			return nil, false, err
+		}
+	}
+	data, err := io.ReadAll(io.LimitReader(file, count))
+	return data, truncated, err
+}
+
+// headLines reads the first n lines, stopping early at maxBytes.
+func headLines(r io.Reader, n int, maxBytes int64) ([]byte, bool, error) {
+	reader := bufio.NewReader(io.LimitReader(r, maxBytes+1))
+	var out []byte
+	for range n {
+		line, err := reader.ReadBytes('\n')
+		out = append(out, line...)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, false, err
+		}
+	}
+	if int64(len(out)) > maxBytes {
+		return out[:maxBytes], true, nil
+	}
+	return out, false, nil
+}
+
+// tailLines reads backwards from the end of the file in chunks until it has
+// seen n line breaks before the final line, the start of the file, or
+// maxBytes of data.
+func tailLines(r io.ReadSeeker, size int64, n int, maxBytes int64) ([]byte, bool, error) {
+	var data []byte
+	pos := size
+	for pos > 0 {
+		chunk := min(int64(tailChunkSize), pos)
+		pos -= chunk
+		buf := make([]byte, chunk)
+		if _, err := r.Seek(pos, io.SeekStart); err != nil {
+			return nil, false, err
+		}
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, false, err
+		}
+		data = append(buf, data...)
+
+		if start, ok := lastLinesStart(data, n); ok {
+			data = data[start:]
+			break
+		}
+		if int64(len(data)) > maxBytes {
+			break
+		}
+	}
+	if int64(len(data)) > maxBytes {
+		return data[int64(len(data))-maxBytes:], true, nil
+	}
+	return data, false, nil
+}
+
+// lastLinesStart returns the offset in data where its last n lines begin,
+// ignoring a trailing newline at the very end. It reports false when data
+// holds fewer than n complete lines.
+func lastLinesStart(data []byte, n int) (int, bool) {
+	end := len(data)
+	if end > 0 && data[end-1] == '\n' {
+		end--
+	}
+	for i := 0; i < n; i++ {
+		idx := bytes.LastIndexByte(data[:end], '\n')
+		if idx < 0 {
+			return 0, false
+		}
+		end = idx
+	}
+	return end + 1, true
+}
@@ -0,0 +1,94 @@
+package fstools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func numberedLines(n int) string {
+	var b strings.Builder
+	for i := 1; i <= n; i++ {
+		fmt.Fprintf(&b, "line %d\n", i)
+	}
+	return b.String()
+}
+
+func TestTailFileTool_TailLinesAcrossChunks(t *testing.T) {
+	workspace := t.TempDir()
+	// Large enough that the tail has to be assembled from several chunks.
+	writeFindFixture(t, workspace, map[string]string{"app.log": numberedLines(5000)})
+
+	tool := NewTailFileTool(workspace, true, MaxReadFileSize)
+	result := tool.Execute(context.Background(), map[string]any{"path": "app.log", "lines": float64(3)})
+
+	assert.False(t, result.IsError, "Expected success, got: %s", result.ForLLM)
+	assert.Contains(t, result.ForLLM, "| last 3 lines |")
+	assert.True(t, strings.HasSuffix(result.ForLLM, "\n\nline 4998\nline 4999\nline 5000\n"), result.ForLLM)
+}
+
+func TestTailFileTool_TailWithoutTrailingNewlineAndShortFile(t *testing.T) {
+	workspace := t.TempDir()
+	writeFindFixture(t, workspace, map[string]string{"a.txt": "one\ntwo\nthree", "b.txt": "only\n"})
+
+	tool := NewTailFileTool(workspace, true, MaxReadFileSize)
+	result := tool.Execute(context.Background(), map[string]any{"path": "a.txt", "lines": float64(2)})
+	assert.True(t, strings.HasSuffix(result.ForLLM, "\n\ntwo\nthree"), result.ForLLM)
+
+	result = tool.Execute(context.Background(), map[string]any{"path": "b.txt", "lines": float64(10)})
+	assert.True(t, strings.HasSuffix(result.ForLLM, "\n\nonly\n"), result.ForLLM)
+	assert.Contains(t, result.ForLLM, "| last 1 lines |")
+}
+
+func TestTailFileTool_HeadLinesAndBytes(t *testing.T) {
+	workspace := t.TempDir()
+	writeFindFixture(t, workspace, map[string]string{"app.log": numberedLines(100)})
+
+	tool := NewTailFileTool(workspace, true, MaxReadFileSize)
+	result := tool.Execute(context.Background(), map[string]any{"path": "app.log", "mode": "head", "lines": float64(2)})
+	assert.True(t, strings.HasSuffix(result.ForLLM, "\n\nline 1\nline 2\n"), result.ForLLM)
+	assert.Contains(t, result.ForLLM, "| first 2 lines |")
+
+	result = tool.Execute(context.Background(), map[string]any{"path": "app.log", "bytes": float64(9)})
+	assert.True(t, strings.HasSuffix(result.ForLLM, "\n\nline 100\n"), result.ForLLM)
+
+	result = tool.Execute(context.Background(), map[string]any{"path": "app.log", "mode": "head", "bytes": float64(4)})
+	assert.True(t, strings.HasSuffix(result.ForLLM, "\n\nline"), result.ForLLM)
+}
+
+func TestTailFileTool_OutputBudget(t *testing.T) {
+	workspace := t.TempDir()
+	writeFindFixture(t, workspace, map[string]string{
+		"big.log": strings.Repeat("x", 500) + "\n" + strings.Repeat("y", 500),
+	})
+
+	tool := NewTailFileTool(workspace, true, 100)
+	result := tool.Execute(context.Background(), map[string]any{"path": "big.log", "lines": float64(1)})
+
+	assert.False(t, result.IsError)
+	assert.Contains(t, result.ForLLM, "[TRUNCATED - output limited to 100 bytes.]")
+	assert.True(t, strings.HasSuffix(result.ForLLM, "\n\n"+strings.Repeat("y", 100)), result.ForLLM)
+}
+
+func TestTailFileTool_Errors(t *testing.T) {
+	workspace := t.TempDir()
+	writeFindFixture(t, workspace, map[string]string{"blob.bin": "\x00\x01\x02", "dir/a": ""})
+	tool := NewTailFileTool(workspace, true, MaxReadFileSize)
+
+	result := tool.Execute(context.Background(), map[string]any{"path": "blob.bin"})
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.ForLLM, "binary")
+
+	result = tool.Execute(context.Background(), map[string]any{"path": "dir"})
+	assert.True(t, result.IsError)
+
+	result = tool.Execute(context.Background(), map[string]any{"path": "missing.log"})
+	assert.True(t, result.IsError)
+
+	result = tool.Execute(context.Background(), map[string]any{"path": "dir/a", "mode": "middle"})
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.ForLLM, "unknown mode")
+}
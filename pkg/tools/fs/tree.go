@@ -0,0 +1,156 @@
+package fstools
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	defaultTreeDepth      = 3
+	defaultTreeMaxEntries = 200
+)
+
+// defaultTreeIgnore lists directory names that are almost never useful to
+// the model and can dwarf the rest of a project.
+var defaultTreeIgnore = []string{".git", "node_modules", ".venv", "__pycache__"}
+
+type treeWalker struct {
+	fs         fileSystem
+	maxDepth   int
+	maxEntries int
+	ignore     []string
+	entries    int
+	truncated  bool
+	out        strings.Builder
+}
+
+// listTree renders the directory at root as an indented tree, descending at
+// most maxDepth levels and printing at most maxEntries entries.
+func (t *ListDirTool) listTree(ctx context.Context, root string, args map[string]any) *ToolResult {
+	maxDepth, err := getInt64Arg(args, "max_depth", defaultTreeDepth)
+	if err != nil {
+		return ErrorResult(err.Error())
+	}
+	if maxDepth < 1 {
+		return ErrorResult("max_depth must be >= 1")
+	}
+	maxEntries, err := getInt64Arg(args, "max_entries", defaultTreeMaxEntries)
+	if err != nil {
+		return ErrorResult(err.Error())
+	}
+	if maxEntries < 1 {
+		return ErrorResult("max_entries must be >= 1")
+	}
+	ignore := defaultTreeIgnore
+	if raw, ok := args["ignore"].([]any); ok {
+		ignore = make([]string, 0, len(raw))
+		for _, item := range raw {
+			pattern, ok := item.(string)
+			if !ok {
+				return ErrorResult("ignore must be a list of strings")
+			}
+			if _, err := path.Match(pattern, ""); err != nil {
+				return ErrorResult(fmt.Sprintf("invalid ignore pattern %q: %v", pattern, err))
+			}
+			ignore = append(ignore, pattern)
+		}
+	}
+
+	w := &treeWalker{
+		fs:         t.fs,
+		maxDepth:   int(maxDepth),
+		maxEntries: int(maxEntries),
+		ignore:     ignore,
+	}
+	if err := w.walk(ctx, root, 0); err != nil {
+		return ErrorResult(fmt.Sprintf("failed to read directory: %v", err))
+	}
+
+	header := fmt.Sprintf("[tree: %s | depth: %d | entries: %d]", root, maxDepth, w.entries)
+	if w.truncated {
+		header += fmt.Sprintf(
+			"\n[TRUNCATED - reached max_entries=%d. List a subdirectory or raise max_entries to see more.]",
+			maxEntries,
+		)
+	}
+	return NewToolResult(header + "\n" + w.out.String())
+}
+
+func (w *treeWalker) walk(ctx context.Context, dir string, depth int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	entries, err := w.fs.ReadDir(dir)
+	if err != nil {
+		if depth == 0 {
+			return err
+		}
+		fmt.Fprintf(&w.out, "%s[unreadable: %v]\n", strings.Repeat("  ", depth), err)
+		return nil
+	}
+
+	indent := strings.Repeat("  ", depth)
+	for _, entry := range entries {
+		if w.ignored(entry.Name()) {
+			continue
+		}
+		if w.entries >= w.maxEntries {
+			w.truncated = true
+			return nil
+		}
+		w.entries++
+
+		if !entry.IsDir() {
+			size := ""
+			if info, err := entry.Info(); err == nil {
+				size = " (" + formatFileSize(info.Size()) + ")"
+			}
+			fmt.Fprintf(&w.out, "%s%s%s\n", indent, entry.Name(), size)
+			continue
+		}
+
+		child := filepath.Join(dir, entry.Name())
+		if depth+1 >= w.maxDepth {
+			summary := ""
+			if children, err := w.fs.ReadDir(child); err == nil && len(children) > 0 {
+				summary = fmt.Sprintf(" (%d entries not shown)", len(children))
+			}
+			fmt.Fprintf(&w.out, "%s%s/%s\n", indent, entry.Name(), summary)
+			continue
+		}
+		fmt.Fprintf(&w.out, "%s%s/\n", indent, entry.Name())
+		if err := w.walk(ctx, child, depth+1); err != nil {
+			return err
+		}
+		if w.truncated {
+			return nil
+		}
+	}
+	return nil
+}
+
+func (w *treeWalker) ignored(name string) bool {
+	for _, pattern := range w.ignore {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// formatFileSize renders a byte count with a binary unit suffix.
+func formatFileSize(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for v := n / unit; v >= unit && exp < 3; v /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGT"[exp])
+}
@@ -0,0 +1,84 @@
+package fstools
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListDirTool_Recursive_RendersTree(t *testing.T) {
+	workspace := t.TempDir()
+	writeFindFixture(t, workspace, map[string]string{
+		"README.md":               strings.Repeat("x", 2048),
+		"src/main.go":             "package main",
+		"src/util/str.go":         "package util",
+		"node_modules/lib/x.js":   "x",
+		".git/HEAD":               "ref",
+		"src/util/deep/more/z.go": "package more",
+	})
+
+	tool := NewListDirTool(workspace, true)
+	result := tool.Execute(context.Background(), map[string]any{"path": ".", "recursive": true})
+
+	assert.False(t, result.IsError, "Expected success, got: %s", result.ForLLM)
+	want := strings.Join([]string{
+		"README.md (2.0 KB)",
+		"src/",
+		"  main.go (12 B)",
+		"  util/",
+		"    deep/ (1 entries not shown)",
+		"    str.go (12 B)",
+	}, "\n")
+	assert.Contains(t, result.ForLLM, want)
+	assert.NotContains(t, result.ForLLM, "node_modules")
+	assert.NotContains(t, result.ForLLM, ".git")
+}
+
+func TestListDirTool_Recursive_MaxEntries(t *testing.T) {
+	workspace := t.TempDir()
+	writeFindFixture(t, workspace, map[string]string{"a": "", "b": "", "c": "", "d": ""})
+
+	tool := NewListDirTool(workspace, true)
+	result := tool.Execute(context.Background(), map[string]any{
+		"path":        ".",
+		"recursive":   true,
+		"max_entries": float64(2),
+	})
+
+	assert.False(t, result.IsError)
+	assert.Contains(t, result.ForLLM, "entries: 2]")
+	assert.Contains(t, result.ForLLM, "reached max_entries=2")
+	assert.NotContains(t, result.ForLLM, "\nc (")
+}
+
+func TestListDirTool_Recursive_CustomIgnore(t *testing.T) {
+	workspace := t.TempDir()
+	writeFindFixture(t, workspace, map[string]string{
+		"keep.txt":      "",
+		"debug.log":     "",
+		".git/HEAD":     "",
+		"build/out.bin": "",
+	})
+
+	tool := NewListDirTool(workspace, true)
+	result := tool.Execute(context.Background(), map[string]any{
+		"path":      ".",
+		"recursive": true,
+		"ignore":    []any{"*.log", "build"},
+	})
+
+	assert.False(t, result.IsError)
+	assert.Contains(t, result.ForLLM, "keep.txt")
+	assert.Contains(t, result.ForLLM, ".git/", "a custom ignore list replaces the defaults")
+	assert.NotContains(t, result.ForLLM, "debug.log")
+	assert.NotContains(t, result.ForLLM, "build")
+}
+
+func TestFormatFileSize(t *testing.T) {
+	assert.Equal(t, "0 B", formatFileSize(0))
+	assert.Equal(t, "1023 B", formatFileSize(1023))
+	assert.Equal(t, "1.5 KB", formatFileSize(1536))
+	assert.Equal(t, "5.0 MB", formatFileSize(5*1024*1024))
+}
@@ -0,0 +1,141 @@
+package fstools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// UndoFileTool restores the version of a file saved in FileBackups before the
+// most recent change made by a filesystem tool. Each call steps one version
+// back; restoring does not itself create a backup.
+type UndoFileTool struct {
+	fs           fileSystem
+	backups      *FileBackups
+	hostRelative bool
+}
+
+// NewUndoFileTool creates an UndoFileTool that restores versions recorded in
+// backups, writing through the same restrictions as the other write tools.
+func NewUndoFileTool(
+	workspace string,
+	restrict bool,
+	backups *FileBackups,
+	allowPaths ...[]*regexp.Regexp,
+) *UndoFileTool {
+	var patterns []*regexp.Regexp
+	if len(allowPaths) > 0 {
+		patterns = allowPaths[0]
+	}
+	sysFs := buildFs(workspace, restrict, patterns)
+	return &UndoFileTool{fs: sysFs, backups: backups, hostRelative: isHostRelative(sysFs)}
+}
+
+func (t *UndoFileTool) Name() string {
+	return "undo_file"
+}
+
+func (t *UndoFileTool) Description() string {
+	return fmt.Sprintf(
+		"Undo the last change made to a file by write_file, edit_file, append_file, multi_edit, apply_patch, copy_file, archive or delete_file, restoring its previous content. Call repeatedly to step further back; up to %d versions are kept per file. Set list=true to see the saved versions without restoring anything.",
+		t.backups.keep,
+	)
+}
+
+func (t *UndoFileTool) Parameters() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"path": map[string]any{
+				"type":        "string",
+				"description": "Path of the file to restore",
+			},
+			"list": map[string]any{
+				"type":        "boolean",
+				"description": "List the saved versions, newest first, instead of restoring",
+			},
+		},
+		"required": []string{"path"},
+	}
+}
+
+func (t *UndoFileTool) Execute(ctx context.Context, args map[string]any) *ToolResult {
+	path, ok := args["path"].(string)
+	if !ok || path == "" {
+		return ErrorResult("path is required")
+	}
+	list, _ := args["list"].(bool)
+
+	// Check access first so backups of protected files are neither listed
+	// nor restored.
+	if _, err := t.fs.Lstat(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return ErrorResult(err.Error())
+	}
+	abs := absoluteToolPath(t.backups.workspace, path, t.hostRelative)
+
+	if list {
+		versions, err := t.backups.List(abs)
+		if err != nil {
+			return ErrorResult(fmt.Sprintf("failed to list backups: %v", err))
+		}
+		if len(versions) == 0 {
+			return SilentResult(fmt.Sprintf("No backups recorded for %s", path))
+		}
+		var sb strings.Builder
+		fmt.Fprintf(&sb, "%d backup(s) of %s, newest first:\n", len(versions), path)
+		for i, version := range versions {
+			state := fmt.Sprintf("%d bytes", version.Size)
+			if version.Absent {
+				state = "file did not exist"
+			}
+			fmt.Fprintf(&sb, "%d. %s (%s)\n", i+1, version.Time.Format(time.RFC3339), state)
+		}
+		return SilentResult(strings.TrimRight(sb.String(), "\n"))
+	}
+
+	unlock, err := t.fs.Lock(path)
+	if err != nil {
+		return ErrorResult(err.Error())
+	}
+	defer unlock()
+
+	versions, err := t.backups.List(abs)
+	if err != nil {
+		return ErrorResult(fmt.Sprintf("failed to read backups: %v", err))
+	}
+	if len(versions) == 0 {
+		return ErrorResult(fmt.Sprintf("no backups recorded for %s; nothing to undo", path))
+	}
+	// Restore before dropping the version so a failed write keeps it.
+	if versions[0].Absent {
+		if err := t.fs.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return ErrorResult(err.Error())
+		}
+	} else {
+		data, err := t.backups.read(abs, versions[0])
+		if err != nil {
+			return ErrorResult(fmt.Sprintf("failed to read backup: %v", err))
+		}
+		if err := t.fs.WriteFile(path, data); err != nil {
+			return ErrorResult(err.Error())
+		}
+	}
+	if err := t.backups.drop(abs, versions[0]); err != nil {
+		return ErrorResult(fmt.Sprintf("restored %s but failed to remove the used backup: %v", path, err))
+	}
+
+	remaining := len(versions) - 1
+	if versions[0].Absent {
+		return SilentResult(fmt.Sprintf(
+			"Removed %s: it did not exist before the last change (%d older backup(s) left)", path, remaining,
+		))
+	}
+	return SilentResult(fmt.Sprintf(
+		"Restored %s to the version from %s (%d older backup(s) left)",
+		path, versions[0].Time.Format(time.RFC3339), remaining,
+	))
+}
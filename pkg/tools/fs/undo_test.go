@@ -0,0 +1,110 @@
+package fstools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newUndoFixture(t *testing.T, restrict bool, keep int) (string, *FileBackups, *UndoFileTool) {
+	t.Helper()
+	workspace := t.TempDir()
+	backups := NewFileBackups(workspace, keep)
+	return workspace, backups, NewUndoFileTool(workspace, restrict, backups)
+}
+
+func TestUndoFileTool_RestoresEditsInOrder(t *testing.T) {
+	workspace, backups, undo := newUndoFixture(t, true, 0)
+	path := filepath.Join(workspace, "notes.txt")
+	require.NoError(t, os.WriteFile(path, []byte("v1"), 0o644))
+
+	write := NewWriteFileTool(workspace, true)
+	require.True(t, ApplyFileBackups(write, backups))
+	edit := NewEditFileTool(workspace, true)
+	ApplyFileBackups(edit, backups)
+
+	ctx := context.Background()
+	result := write.Execute(ctx, map[string]any{"path": "notes.txt", "content": "v2", "overwrite": true})
+	require.False(t, result.IsError, result.ForLLM)
+	result = edit.Execute(ctx, map[string]any{"path": "notes.txt", "old_text": "v2", "new_text": "v3"})
+	require.False(t, result.IsError, result.ForLLM)
+	assertFileContent(t, path, "v3")
+
+	result = undo.Execute(ctx, map[string]any{"path": "notes.txt", "list": true})
+	require.False(t, result.IsError, result.ForLLM)
+	assert.Contains(t, result.ForLLM, "2 backup(s)")
+
+	result = undo.Execute(ctx, map[string]any{"path": "notes.txt"})
+	require.False(t, result.IsError, result.ForLLM)
+	assert.Contains(t, result.ForLLM, "1 older backup(s) left")
+	assertFileContent(t, path, "v2")
+
+	result = undo.Execute(ctx, map[string]any{"path": path})
+	require.False(t, result.IsError, result.ForLLM)
+	assertFileContent(t, path, "v1")
+
+	result = undo.Execute(ctx, map[string]any{"path": "notes.txt"})
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.ForLLM, "nothing to undo")
+}
+
+func TestUndoFileTool_RemovesCreatedFileAndRestoresDeleted(t *testing.T) {
+	workspace, backups, undo := newUndoFixture(t, false, 0)
+	created := filepath.Join(workspace, "new.txt")
+	deleted := filepath.Join(workspace, "old.txt")
+	require.NoError(t, os.WriteFile(deleted, []byte("keep me"), 0o644))
+
+	write := NewWriteFileTool(workspace, false)
+	ApplyFileBackups(write, backups)
+	remove := NewDeleteFileTool(workspace, false)
+	ApplyFileBackups(remove, backups)
+
+	ctx := context.Background()
+	require.False(t, write.Execute(ctx, map[string]any{"path": created, "content": "hello"}).IsError)
+	require.False(t, remove.Execute(ctx, map[string]any{"path": deleted}).IsError)
+
+	result := undo.Execute(ctx, map[string]any{"path": created})
+	require.False(t, result.IsError, result.ForLLM)
+	assert.Contains(t, result.ForLLM, "did not exist")
+	assert.NoFileExists(t, created)
+
+	result = undo.Execute(ctx, map[string]any{"path": deleted})
+	require.False(t, result.IsError, result.ForLLM)
+	assertFileContent(t, deleted, "keep me")
+}
+
+func TestFileBackups_PrunesAndSkipsUnchanged(t *testing.T) {
+	workspace, backups, _ := newUndoFixture(t, true, 2)
+	write := NewWriteFileTool(workspace, true)
+	ApplyFileBackups(write, backups)
+
+	ctx := context.Background()
+	for _, content := range []string{"a", "b", "c", "d", "d"} {
+		result := write.Execute(ctx, map[string]any{"path": "f.txt", "content": content, "overwrite": true})
+		require.False(t, result.IsError, result.ForLLM)
+	}
+
+	versions, err := backups.List(filepath.Join(workspace, "f.txt"))
+	require.NoError(t, err)
+	require.Len(t, versions, 2)
+	data, err := backups.read(filepath.Join(workspace, "f.txt"), versions[0])
+	require.NoError(t, err)
+	assert.Equal(t, "c", string(data))
+	assert.False(t, versions[1].Absent)
+}
+
+func TestUndoFileTool_RespectsFilePolicy(t *testing.T) {
+	workspace, backups, undo := newUndoFixture(t, true, 0)
+	write := NewWriteFileTool(workspace, true)
+	ApplyFileBackups(write, backups)
+	require.False(t, write.Execute(context.Background(), map[string]any{"path": "app.env", "content": "x"}).IsError)
+
+	ApplyFilePolicy(undo, NewFilePolicy(workspace, nil, []string{"*.env"}))
+	result := undo.Execute(context.Background(), map[string]any{"path": "app.env", "list": true})
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.ForLLM, "file policy")
+}
@@ -0,0 +1,364 @@
+package fstools
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// maxWriteSessionSize caps the content one session can accumulate.
+	maxWriteSessionSize = 64 * 1024 * 1024
+	// maxWriteSessions caps the sessions open at the same time per agent.
+	maxWriteSessions = 8
+	// staleWriteSessionAge is how long a session may go without a chunk
+	// before it expires, and when an orphaned part file, left behind by a
+	// restart, is removed.
+	staleWriteSessionAge = 24 * time.Hour
+
+	writeSessionSuffix = ".part"
+)
+
+var writeSessionNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,63}$`)
+
+// WriteSessionTool builds a large file from chunks sent over several calls.
+// Chunks are appended to a part file under <workspace>/state/write_sessions,
+// so each call costs only the size of its chunk, and the target is written
+// once on finalize through the same fileSystem as write_file. Sessions live in
+// memory, do not survive a restart and expire after staleWriteSessionAge
+// without a chunk.
+type WriteSessionTool struct {
+	fs       fileSystem
+	dir      string
+	mu       sync.Mutex
+	sessions map[string]*writeSession
+}
+
+type writeSession struct {
+	path      string
+	partPath  string
+	appendTo  bool
+	overwrite bool
+	size      int64
+	chunks    int
+	createdAt time.Time
+	updatedAt time.Time
+}
+
+// NewWriteSessionTool creates a new WriteSessionTool with optional directory restriction.
+func NewWriteSessionTool(workspace string, restrict bool, allowPaths ...[]*regexp.Regexp) *WriteSessionTool {
+	var patterns []*regexp.Regexp
+	if len(allowPaths) > 0 {
+		patterns = allowPaths[0]
+	}
+	return &WriteSessionTool{
+		fs:       buildFs(workspace, restrict, patterns),
+		dir:      filepath.Join(workspace, "state", "write_sessions"),
+		sessions: make(map[string]*writeSession),
+	}
+}
+
+func (t *WriteSessionTool) Name() string {
+	return "write_session"
+}
+
+func (t *WriteSessionTool) Description() string {
+	return "Write a large file in chunks across several calls: action=open with a session name and path, " +
+		"action=append once per chunk in order, then action=finalize to write the file. " +
+		"Use this instead of repeated append_file calls when generating long content. " +
+		"action=abort discards a session, action=list shows open sessions."
+}
+
+func (t *WriteSessionTool) Parameters() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"action": map[string]any{
+				"type":        "string",
+				"enum":        []string{"open", "append", "finalize", "abort", "list"},
+				"description": "Session step to perform",
+			},
+			"session": map[string]any{
+				"type":        "string",
+				"description": "Session name: letters, digits, '.', '_' and '-'",
+			},
+			"path": map[string]any{
+				"type":        "string",
+				"description": "open: file to write on finalize",
+			},
+			"content": map[string]any{
+				"type":        "string",
+				"description": "append: the next chunk. Standard JSON escaping applies: \\n for newline.",
+			},
+			"append": map[string]any{
+				"type":        "boolean",
+				"description": "open: add the chunks to the end of the existing file instead of replacing it",
+				"default":     false,
+			},
+			"overwrite": map[string]any{
+				"type":        "boolean",
+				"description": "open: allow replacing an existing file",
+				"default":     false,
+			},
+		},
+		"required": []string{"action"},
+	}
+}
+
+func (t *WriteSessionTool) Execute(ctx context.Context, args map[string]any) *ToolResult {
+	action, _ := args["action"].(string)
+	if action == "list" {
+		return t.list()
+	}
+
+	name, _ := args["session"].(string)
+	if name == "" {
+		return ErrorResult("session is required")
+	}
+	if !writeSessionNamePattern.MatchString(name) {
+		return ErrorResult(fmt.Sprintf(
+			"invalid session %q: use up to 64 letters, digits, '.', '_' or '-', starting with a letter or digit", name,
+		))
+	}
+
+	switch action {
+	case "open":
+		return t.open(name, args)
+	case "append":
+		content, ok := args["content"].(string)
+		if !ok {
+			return ErrorResult("content is required")
+		}
+		return t.appendChunk(name, content)
+	case "finalize":
+		return t.finalize(name)
+	case "abort":
+		return t.abort(name)
+	case "":
+		return ErrorResult("action is required")
+	}
+	return ErrorResult(fmt.Sprintf("unknown action %q: use open, append, finalize, abort or list", action))
+}
+
+func (t *WriteSessionTool) open(name string, args map[string]any) *ToolResult {
+	path, _ := args["path"].(string)
+	if path == "" {
+		return ErrorResult("path is required to open a session")
+	}
+	appendTo, _ := args["append"].(bool)
+	overwrite, _ := args["overwrite"].(bool)
+
+	// Check access now rather than failing after all chunks were sent.
+	info, err := t.fs.Lstat(path)
+	switch {
+	case err != nil && !errors.Is(err, fs.ErrNotExist):
+		return ErrorResult(err.Error())
+	case err == nil && info.IsDir():
+		return ErrorResult(fmt.Sprintf("%s is a directory", path))
+	case err == nil && !appendTo && !overwrite:
+		return ErrorResult(fmt.Sprintf(
+			"file: %s already exists. Set append=true to add to it or overwrite=true to replace it.", path,
+		))
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.expireSessions()
+	if _, ok := t.sessions[name]; ok {
+		return ErrorResult(fmt.Sprintf("session %q is already open; finalize or abort it first", name))
+	}
+	if len(t.sessions) >= maxWriteSessions {
+		return ErrorResult(fmt.Sprintf("too many open sessions (max %d); finalize or abort one first", maxWriteSessions))
+	}
+
+	if err := os.MkdirAll(t.dir, 0o700); err != nil {
+		return ErrorResult(fmt.Sprintf("failed to create session directory: %v", err))
+	}
+	t.removeStaleParts()
+
+	suffix := make([]byte, 4)
+	_, _ = rand.Read(suffix)
+	partPath := filepath.Join(t.dir, name+"-"+hex.EncodeToString(suffix)+writeSessionSuffix)
+	f, err := os.OpenFile(partPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return ErrorResult(fmt.Sprintf("failed to create session file: %v", err))
+	}
+	_ = f.Close()
+
+	now := time.Now()
+	t.sessions[name] = &writeSession{
+		path:      path,
+		partPath:  partPath,
+		appendTo:  appendTo,
+		overwrite: overwrite,
+		createdAt: now,
+		updatedAt: now,
+	}
+	verb := "write"
+	if appendTo {
+		verb = "append to"
+	}
+	return SilentResult(fmt.Sprintf("Opened session %q to %s %s. Send chunks with action=append, then action=finalize.",
+		name, verb, path))
+}
+
+func (t *WriteSessionTool) appendChunk(name, content string) *ToolResult {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.expireSessions()
+	s, ok := t.sessions[name]
+	if !ok {
+		return ErrorResult(fmt.Sprintf("no open session %q; open it first", name))
+	}
+	if s.size+int64(len(content)) > maxWriteSessionSize {
+		return ErrorResult(fmt.Sprintf(
+			"session %q would exceed %d bytes; finalize it and continue in a new session with append=true",
+			name, maxWriteSessionSize,
+		))
+	}
+
+	f, err := os.OpenFile(s.partPath, os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return ErrorResult(fmt.Sprintf("failed to open session file: %v", err))
+	}
+	_, err = f.WriteString(content)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return ErrorResult(fmt.Sprintf("failed to write chunk: %v", err))
+	}
+	s.size += int64(len(content))
+	s.chunks++
+	s.updatedAt = time.Now()
+	return SilentResult(fmt.Sprintf("Session %q: chunk %d added (%d bytes total)", name, s.chunks, s.size))
+}
+
+func (t *WriteSessionTool) finalize(name string) *ToolResult {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.expireSessions()
+	s, ok := t.sessions[name]
+	if !ok {
+		return ErrorResult(fmt.Sprintf("no open session %q; open it first", name))
+	}
+
+	data, err := os.ReadFile(s.partPath)
+	if err != nil {
+		return ErrorResult(fmt.Sprintf("failed to read session file: %v", err))
+	}
+
+	unlock, err := t.fs.Lock(s.path)
+	if err != nil {
+		return ErrorResult(err.Error())
+	}
+	defer unlock()
+	// The target may have appeared since open; only replace it if allowed.
+	if !s.appendTo && !s.overwrite {
+		_, err := t.fs.Lstat(s.path)
+		if err == nil {
+			return ErrorResult(fmt.Sprintf(
+				"file: %s was created after session %q was opened; abort the session or remove the file "+
+					"(session %q is still open)", s.path, name, name,
+			))
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return ErrorResult(fmt.Sprintf("%v (session %q is still open)", err, name))
+		}
+	}
+	if s.appendTo {
+		existing, err := t.fs.ReadFile(s.path)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return ErrorResult(err.Error())
+		}
+		data = append(existing, data...)
+	}
+	// Keep the session on failure so the agent can fix the cause and retry.
+	if err := t.fs.WriteFile(s.path, data); err != nil {
+		return ErrorResult(fmt.Sprintf("%v (session %q is still open)", err, name))
+	}
+
+	delete(t.sessions, name)
+	_ = os.Remove(s.partPath)
+	return SilentResult(fmt.Sprintf("File written: %s (%d bytes from %d chunks)", s.path, s.size, s.chunks))
+}
+
+func (t *WriteSessionTool) abort(name string) *ToolResult {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.expireSessions()
+	s, ok := t.sessions[name]
+	if !ok {
+		return ErrorResult(fmt.Sprintf("no open session %q", name))
+	}
+	delete(t.sessions, name)
+	_ = os.Remove(s.partPath)
+	return SilentResult(fmt.Sprintf("Aborted session %q; %s was not changed", name, s.path))
+}
+
+func (t *WriteSessionTool) list() *ToolResult {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.expireSessions()
+	if len(t.sessions) == 0 {
+		return SilentResult("No open write sessions")
+	}
+	names := make([]string, 0, len(t.sessions))
+	for name := range t.sessions {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%d open write session(s):", len(names))
+	for _, name := range names {
+		s := t.sessions[name]
+		fmt.Fprintf(&sb, "\n%s -> %s: %d chunks, %d bytes, opened %s",
+			name, s.path, s.chunks, s.size, s.createdAt.Format(time.RFC3339))
+	}
+	return SilentResult(sb.String())
+}
+
+// expireSessions drops sessions that received no chunk for
+// staleWriteSessionAge, freeing their slot and part file. The caller holds
+// t.mu.
+func (t *WriteSessionTool) expireSessions() {
+	cutoff := time.Now().Add(-staleWriteSessionAge)
+	for name, s := range t.sessions {
+		if s.updatedAt.Before(cutoff) {
+			delete(t.sessions, name)
+			_ = os.Remove(s.partPath)
+		}
+	}
+}
+
+// removeStaleParts deletes part files that no open session owns and that
+// have not been written for staleWriteSessionAge. The caller holds t.mu.
+func (t *WriteSessionTool) removeStaleParts() {
+	entries, err := os.ReadDir(t.dir)
+	if err != nil {
+		return
+	}
+	owned := make(map[string]bool, len(t.sessions))
+	for _, s := range t.sessions {
+		owned[filepath.Base(s.partPath)] = true
+	}
+	cutoff := time.Now().Add(-staleWriteSessionAge)
+	for _, entry := range entries {
+		if owned[entry.Name()] || !strings.HasSuffix(entry.Name(), writeSessionSuffix) {
+			continue
+		}
+		if info, err := entry.Info(); err == nil && info.ModTime().Before(cutoff) {
+			_ = os.Remove(filepath.Join(t.dir, entry.Name()))
+		}
+	}
+}
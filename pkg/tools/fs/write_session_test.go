@@ -0,0 +1,164 @@
+package fstools
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func runWriteSession(t *testing.T, tool *WriteSessionTool, args map[string]any) *ToolResult {
+	t.Helper()
+	result := tool.Execute(context.Background(), args)
+	require.False(t, result.IsError, result.ForLLM)
+	return result
+}
+
+func TestWriteSessionTool_ChunksWrittenOnFinalize(t *testing.T) {
+	workspace := t.TempDir()
+	tool := NewWriteSessionTool(workspace, true)
+	target := filepath.Join(workspace, "out", "report.md")
+
+	runWriteSession(t, tool, map[string]any{"action": "open", "session": "report", "path": "out/report.md"})
+	runWriteSession(t, tool, map[string]any{"action": "append", "session": "report", "content": "# Title\n"})
+	runWriteSession(t, tool, map[string]any{"action": "append", "session": "report", "content": "body\n"})
+	assert.NoFileExists(t, target)
+
+	result := runWriteSession(t, tool, map[string]any{"action": "finalize", "session": "report"})
+	assert.Contains(t, result.ForLLM, "13 bytes from 2 chunks")
+
+	data, err := os.ReadFile(target)
+	require.NoError(t, err)
+	assert.Equal(t, "# Title\nbody\n", string(data))
+
+	parts, err := os.ReadDir(filepath.Join(workspace, "state", "write_sessions"))
+	require.NoError(t, err)
+	assert.Empty(t, parts)
+
+	result = tool.Execute(context.Background(), map[string]any{"action": "append", "session": "report", "content": "x"})
+	assert.True(t, result.IsError)
+}
+
+func TestWriteSessionTool_AppendToExistingFile(t *testing.T) {
+	workspace := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(workspace, "log.txt"), []byte("old\n"), 0o644))
+	tool := NewWriteSessionTool(workspace, true)
+
+	result := tool.Execute(context.Background(), map[string]any{"action": "open", "session": "s", "path": "log.txt"})
+	require.True(t, result.IsError)
+	assert.Contains(t, result.ForLLM, "already exists")
+
+	runWriteSession(t, tool, map[string]any{"action": "open", "session": "s", "path": "log.txt", "append": true})
+	runWriteSession(t, tool, map[string]any{"action": "append", "session": "s", "content": "new\n"})
+	runWriteSession(t, tool, map[string]any{"action": "finalize", "session": "s"})
+
+	data, err := os.ReadFile(filepath.Join(workspace, "log.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "old\nnew\n", string(data))
+}
+
+func TestWriteSessionTool_AbortLeavesTargetUntouched(t *testing.T) {
+	workspace := t.TempDir()
+	tool := NewWriteSessionTool(workspace, true)
+
+	runWriteSession(t, tool, map[string]any{"action": "open", "session": "s", "path": "a.txt"})
+	runWriteSession(t, tool, map[string]any{"action": "append", "session": "s", "content": "data"})
+	result := runWriteSession(t, tool, map[string]any{"action": "list"})
+	assert.Contains(t, result.ForLLM, "s -> a.txt: 1 chunks, 4 bytes")
+
+	runWriteSession(t, tool, map[string]any{"action": "abort", "session": "s"})
+	assert.NoFileExists(t, filepath.Join(workspace, "a.txt"))
+	result = runWriteSession(t, tool, map[string]any{"action": "list"})
+	assert.Equal(t, "No open write sessions", result.ForLLM)
+}
+
+func TestWriteSessionTool_Errors(t *testing.T) {
+	workspace := t.TempDir()
+	tool := NewWriteSessionTool(workspace, true)
+	runWriteSession(t, tool, map[string]any{"action": "open", "session": "dup", "path": "a.txt"})
+
+	tests := []struct {
+		name string
+		args map[string]any
+		want string
+	}{
+		{"invalid name", map[string]any{"action": "open", "session": "../x", "path": "a.txt"}, "invalid session"},
+		{"duplicate", map[string]any{"action": "open", "session": "dup", "path": "b.txt"}, "already open"},
+		{"outside workspace", map[string]any{"action": "open", "session": "o", "path": "/etc/passwd"}, "escapes"},
+		{"missing path", map[string]any{"action": "open", "session": "p"}, "path is required"},
+		{"unknown session", map[string]any{"action": "finalize", "session": "nope"}, "no open session"},
+		{"bad action", map[string]any{"action": "flush", "session": "dup"}, "unknown action"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := tool.Execute(context.Background(), tt.args)
+			require.True(t, result.IsError)
+			assert.Contains(t, result.ForLLM, tt.want)
+		})
+	}
+}
+
+func TestWriteSessionTool_FinalizeFailureKeepsSession(t *testing.T) {
+	workspace := t.TempDir()
+	tool := NewWriteSessionTool(workspace, true)
+	ApplyFilePolicy(tool, NewFilePolicy(workspace, []string{"locked.txt"}, nil))
+
+	runWriteSession(t, tool, map[string]any{"action": "open", "session": "s", "path": "locked.txt"})
+	runWriteSession(t, tool, map[string]any{"action": "append", "session": "s", "content": "x"})
+	result := tool.Execute(context.Background(), map[string]any{"action": "finalize", "session": "s"})
+	require.True(t, result.IsError)
+	assert.Contains(t, result.ForLLM, "still open")
+
+	result = runWriteSession(t, tool, map[string]any{"action": "list"})
+	assert.Contains(t, result.ForLLM, "s -> locked.txt")
+}
+
+func TestWriteSessionTool_IdleSessionsExpire(t *testing.T) {
+	workspace := t.TempDir()
+	tool := NewWriteSessionTool(workspace, true)
+
+	for i := range maxWriteSessions {
+		name := fmt.Sprintf("s%d", i)
+		runWriteSession(t, tool, map[string]any{"action": "open", "session": name, "path": name + ".txt"})
+	}
+	result := tool.Execute(context.Background(), map[string]any{"action": "open", "session": "next", "path": "n.txt"})
+	require.True(t, result.IsError)
+	assert.Contains(t, result.ForLLM, "too many open sessions")
+
+	idle := tool.sessions["s0"]
+	idle.updatedAt = time.Now().Add(-staleWriteSessionAge - time.Minute)
+
+	runWriteSession(t, tool, map[string]any{"action": "open", "session": "next", "path": "n.txt"})
+	assert.NoFileExists(t, idle.partPath)
+	result = tool.Execute(context.Background(), map[string]any{"action": "append", "session": "s0", "content": "x"})
+	require.True(t, result.IsError)
+	assert.Contains(t, result.ForLLM, "no open session")
+}
+
+func TestWriteSessionTool_FinalizeRechecksExistingTarget(t *testing.T) {
+	workspace := t.TempDir()
+	tool := NewWriteSessionTool(workspace, true)
+	target := filepath.Join(workspace, "a.txt")
+
+	runWriteSession(t, tool, map[string]any{"action": "open", "session": "s", "path": "a.txt"})
+	runWriteSession(t, tool, map[string]any{"action": "append", "session": "s", "content": "new"})
+	require.NoError(t, os.WriteFile(target, []byte("user"), 0o644))
+
+	result := tool.Execute(context.Background(), map[string]any{"action": "finalize", "session": "s"})
+	require.True(t, result.IsError)
+	assert.Contains(t, result.ForLLM, "was created after session")
+	data, err := os.ReadFile(target)
+	require.NoError(t, err)
+	assert.Equal(t, "user", string(data))
+
+	require.NoError(t, os.Remove(target))
+	runWriteSession(t, tool, map[string]any{"action": "finalize", "session": "s"})
+	data, err = os.ReadFile(target)
+	require.NoError(t, err)
+	assert.Equal(t, "new", string(data))
+}
@@ -2,20 +2,38 @@ package tools
 
 import (
 	"regexp"
+	"time"
 
 	"github.com/sipeed/picoclaw/pkg/media"
 	fstools "github.com/sipeed/picoclaw/pkg/tools/fs"
 )
 
 type (
-	ReadFileTool      = fstools.ReadFileTool
-	ReadFileLinesTool = fstools.ReadFileLinesTool
-	WriteFileTool     = fstools.WriteFileTool
-	ListDirTool       = fstools.ListDirTool
-	EditFileTool      = fstools.EditFileTool
-	AppendFileTool    = fstools.AppendFileTool
-	LoadImageTool     = fstools.LoadImageTool
-	SendFileTool      = fstools.SendFileTool
+	ReadFileTool       = fstools.ReadFileTool
+	ReadFileLinesTool  = fstools.ReadFileLinesTool
+	WriteFileTool      = fstools.WriteFileTool
+	ListDirTool        = fstools.ListDirTool
+	FindFilesTool      = fstools.FindFilesTool
+	GrepTool           = fstools.GrepTool
+	StatTool           = fstools.StatTool
+	TailFileTool       = fstools.TailFileTool
+	DataQueryTool      = fstools.DataQueryTool
+	EditFileTool       = fstools.EditFileTool
+	MultiEditTool      = fstools.MultiEditTool
+	ApplyPatchTool     = fstools.ApplyPatchTool
+	AppendFileTool     = fstools.AppendFileTool
+	WriteSessionTool   = fstools.WriteSessionTool
+	DeleteFileTool     = fstools.DeleteFileTool
+	CopyFileTool       = fstools.CopyFileTool
+	ArchiveTool        = fstools.ArchiveTool
+	SetPermissionsTool = fstools.SetPermissionsTool
+	UndoFileTool       = fstools.UndoFileTool
+	ScratchTool        = fstools.ScratchTool
+	SnapshotTool       = fstools.SnapshotTool
+	LoadImageTool      = fstools.LoadImageTool
+	SendFileTool       = fstools.SendFileTool
+	FilePolicy         = fstools.FilePolicy
+	FileBackups        = fstools.FileBackups
 )
 
 const MaxReadFileSize = fstools.MaxReadFileSize
@@ -79,6 +97,14 @@ func NewAppendFileTool(
 	return fstools.NewAppendFileTool(workspace, restrict, allowPaths...)
 }
 
+func NewDeleteFileTool(
+	workspace string,
+	restrict bool,
+	allowPaths ...[]*regexp.Regexp,
+) *DeleteFileTool {
+	return fstools.NewDeleteFileTool(workspace, restrict, allowPaths...)
+}
+
 func NewLoadImageTool(
 	workspace string,
 	restrict bool,
@@ -98,3 +124,122 @@ func NewSendFileTool(
 ) *SendFileTool {
 	return fstools.NewSendFileTool(workspace, restrict, maxFileSize, store, allowPaths...)
 }
+
+func NewCopyFileTool(
+	workspace string,
+	restrict bool,
+	allowPaths ...[]*regexp.Regexp,
+) *CopyFileTool {
+	return fstools.NewCopyFileTool(workspace, restrict, allowPaths...)
+}
+
+func NewFindFilesTool(
+	workspace string,
+	restrict bool,
+	allowPaths ...[]*regexp.Regexp,
+) *FindFilesTool {
+	return fstools.NewFindFilesTool(workspace, restrict, allowPaths...)
+}
+
+func NewGrepTool(
+	workspace string,
+	restrict bool,
+	allowPaths ...[]*regexp.Regexp,
+) *GrepTool {
+	return fstools.NewGrepTool(workspace, restrict, allowPaths...)
+}
+
+func NewStatTool(
+	workspace string,
+	restrict bool,
+	allowPaths ...[]*regexp.Regexp,
+) *StatTool {
+	return fstools.NewStatTool(workspace, restrict, allowPaths...)
+}
+
+func NewTailFileTool(
+	workspace string,
+	restrict bool,
+	maxReadFileSize int,
+	allowPaths ...[]*regexp.Regexp,
+) *TailFileTool {
+	return fstools.NewTailFileTool(workspace, restrict, maxReadFileSize, allowPaths...)
+}
+
+func NewMultiEditTool(
+	workspace string,
+	restrict bool,
+	allowPaths ...[]*regexp.Regexp,
+) *MultiEditTool {
+	return fstools.NewMultiEditTool(workspace, restrict, allowPaths...)
+}
+
+func NewApplyPatchTool(
+	workspace string,
+	restrict bool,
+	allowPaths ...[]*regexp.Regexp,
+) *ApplyPatchTool {
+	return fstools.NewApplyPatchTool(workspace, restrict, allowPaths...)
+}
+
+func NewArchiveTool(
+	workspace string,
+	restrict bool,
+	allowPaths ...[]*regexp.Regexp,
+) *ArchiveTool {
+	return fstools.NewArchiveTool(workspace, restrict, allowPaths...)
+}
+
+func NewFilePolicy(workspace string, readOnly, deny []string) *FilePolicy {
+	return fstools.NewFilePolicy(workspace, readOnly, deny)
+}
+
+func ApplyFilePolicy(tool Tool, policy *FilePolicy) bool {
+	return fstools.ApplyFilePolicy(tool, policy)
+}
+
+func NewFileBackups(workspace string, keep int) *FileBackups {
+	return fstools.NewFileBackups(workspace, keep)
+}
+
+func ApplyFileBackups(tool Tool, backups *FileBackups) bool {
+	return fstools.ApplyFileBackups(tool, backups)
+}
+
+func ApplyWriteObserver(tool Tool, workspace string, observe func(path string)) bool {
+	return fstools.ApplyWriteObserver(tool, workspace, observe)
+}
+
+func NewUndoFileTool(
+	workspace string,
+	restrict bool,
+	backups *FileBackups,
+	allowPaths ...[]*regexp.Regexp,
+) *UndoFileTool {
+	return fstools.NewUndoFileTool(workspace, restrict, backups, allowPaths...)
+}
+
+func NewDataQueryTool(
+	workspace string,
+	restrict bool,
+	maxReadFileSize int,
+	allowPaths ...[]*regexp.Regexp,
+) *DataQueryTool {
+	return fstools.NewDataQueryTool(workspace, restrict, maxReadFileSize, allowPaths...)
+}
+
+func NewScratchTool(workspace string, maxAge time.Duration, maxSize int64) *ScratchTool {
+	return fstools.NewScratchTool(workspace, maxAge, maxSize)
+}
+
+func NewSnapshotTool(workspace string, maxSnapshots int) *SnapshotTool {
+	return fstools.NewSnapshotTool(workspace, maxSnapshots)
+}
+
+func NewSetPermissionsTool(workspace string, restrict bool, allowPaths ...[]*regexp.Regexp) *SetPermissionsTool {
+	return fstools.NewSetPermissionsTool(workspace, restrict, allowPaths...)
+}
+
+func NewWriteSessionTool(workspace string, restrict bool, allowPaths ...[]*regexp.Regexp) *WriteSessionTool {
+	return fstools.NewWriteSessionTool(workspace, restrict, allowPaths...)
+}
@@ -0,0 +1,243 @@
+package integrationtools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// MCPServerStatus describes a configured MCP server for the mcp_admin tool.
+type MCPServerStatus struct {
+	Name      string
+	Connected bool
+	ToolCount int
+	// ToolStats holds call statistics for the server's tools that were called.
+	ToolStats []MCPToolStats
+}
+
+// MCPToolStats summarizes calls made to one MCP tool.
+type MCPToolStats struct {
+	Tool          string
+	Calls         int64
+	Errors        int64
+	AvgLatency    time.Duration
+	MaxLatency    time.Duration
+	BytesSent     int64
+	BytesReceived int64
+}
+
+// MCPTrafficEntry is one JSON-RPC message recorded for a server that has
+// debug_traffic enabled.
+type MCPTrafficEntry struct {
+	Time      time.Time
+	Direction string
+	Message   string
+}
+
+// defaultMCPDebugLimit is how many traffic entries or stderr lines the
+// traffic and stderr actions show unless limit is given.
+const defaultMCPDebugLimit = 20
+
+// MCPServerController starts and stops configured MCP servers at runtime,
+// manages their resource subscriptions and exposes their debug buffers.
+type MCPServerController interface {
+	StartMCPServer(ctx context.Context, name string) (int, error)
+	StopMCPServer(name string) error
+	MCPServerStatuses() []MCPServerStatus
+	SubscribeMCPResource(ctx context.Context, server, uri string) error
+	UnsubscribeMCPResource(ctx context.Context, server, uri string) error
+	MCPServerTraffic(name string) ([]MCPTrafficEntry, error)
+	MCPServerStderr(name string) ([]string, error)
+}
+
+// MCPAdminTool lets the agent list, start and stop the MCP servers defined in
+// config without restarting picoclaw. Only servers already present in
+// tools.mcp.servers can be started, so the agent cannot launch arbitrary
+// commands through it.
+type MCPAdminTool struct {
+	controller MCPServerController
+}
+
+func NewMCPAdminTool(controller MCPServerController) *MCPAdminTool {
+	return &MCPAdminTool{controller: controller}
+}
+
+func (t *MCPAdminTool) Name() string {
+	return "mcp_admin"
+}
+
+func (t *MCPAdminTool) Description() string {
+	return "List configured MCP servers, start/stop one at runtime, show per-tool call statistics, " +
+		"subscribe/unsubscribe to updates for a server resource, or show a running server's recent " +
+		"JSON-RPC traffic (needs debug_traffic) or stderr output to troubleshoot it. " +
+		"Starting a server registers its tools; stopping it removes them. " +
+		"Each update to a subscribed resource arrives as a system message."
+}
+
+func (t *MCPAdminTool) Parameters() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"action": map[string]any{
+				"type":        "string",
+				"enum":        []string{"list", "start", "stop", "stats", "subscribe", "unsubscribe", "traffic", "stderr"},
+				"description": "Operation to perform",
+			},
+			"server": map[string]any{
+				"type":        "string",
+				"description": "Configured MCP server name (required for start, stop, subscribe, unsubscribe, traffic and stderr; optional filter for stats)",
+			},
+			"uri": map[string]any{
+				"type":        "string",
+				"description": "Resource URI (required for subscribe and unsubscribe)",
+			},
+			"limit": map[string]any{
+				"type":        "integer",
+				"description": "Number of most recent messages or lines to show for traffic and stderr (default 20)",
+			},
+		},
+		"required": []string{"action"},
+	}
+}
+
+func (t *MCPAdminTool) Execute(ctx context.Context, args map[string]any) *ToolResult {
+	if t.controller == nil {
+		return ErrorResult("MCP server management is not available")
+	}
+
+	action, _ := args["action"].(string)
+	server, _ := args["server"].(string)
+	server = strings.TrimSpace(server)
+
+	switch action {
+	case "list":
+		return t.list()
+	case "stats":
+		return t.stats(server)
+	case "start", "stop":
+		if server == "" {
+			return ErrorResult(fmt.Sprintf("server is required for action %q", action))
+		}
+	case "subscribe", "unsubscribe":
+		return t.subscription(ctx, action, server, args)
+	case "traffic", "stderr":
+		return t.debugOutput(action, server, args)
+	default:
+		return ErrorResult(fmt.Sprintf(
+			"unknown action %q (expected list, start, stop, stats, subscribe, unsubscribe, traffic or stderr)",
+			action))
+	}
+
+	if action == "start" {
+		toolCount, err := t.controller.StartMCPServer(ctx, server)
+		if err != nil {
+			return ErrorResult(fmt.Sprintf("failed to start MCP server %q: %v", server, err)).WithError(err)
+		}
+		return NewToolResult(fmt.Sprintf("MCP server %q started with %d tool(s) registered.", server, toolCount))
+	}
+
+	if err := t.controller.StopMCPServer(server); err != nil {
+		return ErrorResult(fmt.Sprintf("failed to stop MCP server %q: %v", server, err)).WithError(err)
+	}
+	return NewToolResult(fmt.Sprintf("MCP server %q stopped and its tools were removed.", server))
+}
+
+func (t *MCPAdminTool) subscription(ctx context.Context, action, server string, args map[string]any) *ToolResult {
+	uri, _ := args["uri"].(string)
+	uri = strings.TrimSpace(uri)
+	if server == "" || uri == "" {
+		return ErrorResult(fmt.Sprintf("server and uri are required for action %q", action))
+	}
+
+	if action == "subscribe" {
+		if err := t.controller.SubscribeMCPResource(ctx, server, uri); err != nil {
+			return ErrorResult(fmt.Sprintf("failed to subscribe to %s: %v", uri, err)).WithError(err)
+		}
+		return NewToolResult(fmt.Sprintf(
+			"Subscribed to %s on MCP server %q; updates will arrive as system messages.", uri, server))
+	}
+	if err := t.controller.UnsubscribeMCPResource(ctx, server, uri); err != nil {
+		return ErrorResult(fmt.Sprintf("failed to unsubscribe from %s: %v", uri, err)).WithError(err)
+	}
+	return NewToolResult(fmt.Sprintf("Unsubscribed from %s on MCP server %q.", uri, server))
+}
+
+// debugOutput shows the tail of a running server's traffic or stderr buffer.
+func (t *MCPAdminTool) debugOutput(action, server string, args map[string]any) *ToolResult {
+	if server == "" {
+		return ErrorResult(fmt.Sprintf("server is required for action %q", action))
+	}
+	limit, err := getInt64Arg(args, "limit", defaultMCPDebugLimit)
+	if err != nil {
+		return ErrorResult(err.Error())
+	}
+	if limit <= 0 {
+		return ErrorResult("limit must be positive")
+	}
+
+	var lines []string
+	if action == "traffic" {
+		entries, err := t.controller.MCPServerTraffic(server)
+		if err != nil {
+			return ErrorResult(fmt.Sprintf("failed to read traffic of MCP server %q: %v", server, err)).WithError(err)
+		}
+		for _, entry := range entries {
+			lines = append(lines, fmt.Sprintf("%s %-4s %s",
+				entry.Time.Format("15:04:05.000"), entry.Direction, entry.Message))
+		}
+	} else {
+		lines, err = t.controller.MCPServerStderr(server)
+		if err != nil {
+			return ErrorResult(fmt.Sprintf("failed to read stderr of MCP server %q: %v", server, err)).WithError(err)
+		}
+	}
+
+	what := map[string]string{"traffic": "JSON-RPC messages", "stderr": "stderr lines"}[action]
+	if len(lines) == 0 {
+		return NewToolResult(fmt.Sprintf("No %s recorded for MCP server %q.", what, server))
+	}
+	shown := lines[max(len(lines)-int(limit), 0):]
+	return NewToolResult(fmt.Sprintf("Last %d of %d %s for MCP server %q:\n%s",
+		len(shown), len(lines), what, server, strings.Join(shown, "\n")))
+}
+
+func (t *MCPAdminTool) list() *ToolResult {
+	statuses := t.controller.MCPServerStatuses()
+	if len(statuses) == 0 {
+		return NewToolResult("No MCP servers are configured.")
+	}
+
+	var sb strings.Builder
+	sb.WriteString("Configured MCP servers:\n")
+	for _, status := range statuses {
+		if status.Connected {
+			fmt.Fprintf(&sb, "- %s: running (%d tools)\n", status.Name, status.ToolCount)
+		} else {
+			fmt.Fprintf(&sb, "- %s: stopped\n", status.Name)
+		}
+	}
+	return NewToolResult(strings.TrimRight(sb.String(), "\n"))
+}
+
+func (t *MCPAdminTool) stats(server string) *ToolResult {
+	var sb strings.Builder
+	for _, status := range t.controller.MCPServerStatuses() {
+		if server != "" && status.Name != server {
+			continue
+		}
+		for _, stats := range status.ToolStats {
+			errorRate := float64(stats.Errors) / float64(max(stats.Calls, 1)) * 100
+			fmt.Fprintf(&sb,
+				"- %s/%s: %d calls, %d errors (%.0f%%), avg %s, max %s, sent %d B, received %d B\n",
+				status.Name, stats.Tool, stats.Calls, stats.Errors, errorRate,
+				stats.AvgLatency.Round(time.Millisecond), stats.MaxLatency.Round(time.Millisecond),
+				stats.BytesSent, stats.BytesReceived,
+			)
+		}
+	}
+	if sb.Len() == 0 {
+		return NewToolResult("No MCP tool calls have been recorded yet.")
+	}
+	return NewToolResult("MCP tool call statistics:\n" + strings.TrimRight(sb.String(), "\n"))
+}
@@ -0,0 +1,200 @@
+package integrationtools
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+type fakeMCPServerController struct {
+	started      []string
+	stopped      []string
+	subscribed   []string
+	unsubscribed []string
+	startErr     error
+	statuses     []MCPServerStatus
+	traffic      []MCPTrafficEntry
+	stderr       []string
+}
+
+func (c *fakeMCPServerController) StartMCPServer(ctx context.Context, name string) (int, error) {
+	if c.startErr != nil {
+		return 0, c.startErr
+	}
+	c.started = append(c.started, name)
+	return 3, nil
+}
+
+func (c *fakeMCPServerController) StopMCPServer(name string) error {
+	c.stopped = append(c.stopped, name)
+	return nil
+}
+
+func (c *fakeMCPServerController) MCPServerStatuses() []MCPServerStatus {
+	return c.statuses
+}
+
+func (c *fakeMCPServerController) SubscribeMCPResource(ctx context.Context, server, uri string) error {
+	c.subscribed = append(c.subscribed, server+" "+uri)
+	return nil
+}
+
+func (c *fakeMCPServerController) UnsubscribeMCPResource(ctx context.Context, server, uri string) error {
+	c.unsubscribed = append(c.unsubscribed, server+" "+uri)
+	return nil
+}
+
+func (c *fakeMCPServerController) MCPServerTraffic(name string) ([]MCPTrafficEntry, error) {
+	if c.traffic == nil {
+		return nil, errors.New("traffic recording is disabled")
+	}
+	return c.traffic, nil
+}
+
+func (c *fakeMCPServerController) MCPServerStderr(name string) ([]string, error) {
+	return c.stderr, nil
+}
+
+func TestMCPAdminTool_Execute(t *testing.T) {
+	controller := &fakeMCPServerController{
+		statuses: []MCPServerStatus{
+			{Name: "files", Connected: true, ToolCount: 3},
+			{Name: "github"},
+		},
+	}
+	tool := NewMCPAdminTool(controller)
+
+	result := tool.Execute(context.Background(), map[string]any{"action": "list"})
+	if result.IsError {
+		t.Fatalf("list failed: %s", result.ForLLM)
+	}
+	if !strings.Contains(result.ForLLM, "files: running (3 tools)") ||
+		!strings.Contains(result.ForLLM, "github: stopped") {
+		t.Fatalf("unexpected list output: %q", result.ForLLM)
+	}
+
+	result = tool.Execute(context.Background(), map[string]any{"action": "start", "server": "github"})
+	if result.IsError || !strings.Contains(result.ForLLM, "3 tool(s)") {
+		t.Fatalf("unexpected start result: %q", result.ForLLM)
+	}
+	result = tool.Execute(context.Background(), map[string]any{"action": "stop", "server": " files "})
+	if result.IsError {
+		t.Fatalf("stop failed: %s", result.ForLLM)
+	}
+	if len(controller.started) != 1 || controller.started[0] != "github" {
+		t.Fatalf("started = %v", controller.started)
+	}
+	if len(controller.stopped) != 1 || controller.stopped[0] != "files" {
+		t.Fatalf("stopped = %v", controller.stopped)
+	}
+
+	result = tool.Execute(context.Background(), map[string]any{"action": "stop"})
+	if !result.IsError || !strings.Contains(result.ForLLM, "server is required") {
+		t.Fatalf("expected missing server error, got %q", result.ForLLM)
+	}
+
+	controller.startErr = errors.New("boom")
+	result = tool.Execute(context.Background(), map[string]any{"action": "start", "server": "github"})
+	if !result.IsError || !strings.Contains(result.ForLLM, "boom") {
+		t.Fatalf("expected start error to be surfaced, got %q", result.ForLLM)
+	}
+}
+
+func TestMCPAdminTool_Subscriptions(t *testing.T) {
+	controller := &fakeMCPServerController{}
+	tool := NewMCPAdminTool(controller)
+
+	result := tool.Execute(context.Background(), map[string]any{
+		"action": "subscribe", "server": "files", "uri": "file:///notes.txt",
+	})
+	if result.IsError || !strings.Contains(result.ForLLM, "Subscribed to file:///notes.txt") {
+		t.Fatalf("unexpected subscribe result: %q", result.ForLLM)
+	}
+	result = tool.Execute(context.Background(), map[string]any{
+		"action": "unsubscribe", "server": "files", "uri": " file:///notes.txt ",
+	})
+	if result.IsError {
+		t.Fatalf("unsubscribe failed: %s", result.ForLLM)
+	}
+	if len(controller.subscribed) != 1 || controller.subscribed[0] != "files file:///notes.txt" {
+		t.Fatalf("subscribed = %v", controller.subscribed)
+	}
+	if len(controller.unsubscribed) != 1 || controller.unsubscribed[0] != "files file:///notes.txt" {
+		t.Fatalf("unsubscribed = %v", controller.unsubscribed)
+	}
+
+	result = tool.Execute(context.Background(), map[string]any{"action": "subscribe", "server": "files"})
+	if !result.IsError || !strings.Contains(result.ForLLM, "uri are required") {
+		t.Fatalf("expected missing uri error, got %q", result.ForLLM)
+	}
+}
+
+func TestMCPAdminTool_Stats(t *testing.T) {
+	controller := &fakeMCPServerController{
+		statuses: []MCPServerStatus{
+			{
+				Name:      "files",
+				Connected: true,
+				ToolCount: 2,
+				ToolStats: []MCPToolStats{
+					{Tool: "read_file", Calls: 4, Errors: 1, AvgLatency: 120 * time.Millisecond, BytesReceived: 2048},
+				},
+			},
+			{Name: "github"},
+		},
+	}
+	tool := NewMCPAdminTool(controller)
+
+	result := tool.Execute(context.Background(), map[string]any{"action": "stats"})
+	if result.IsError {
+		t.Fatalf("stats failed: %s", result.ForLLM)
+	}
+	if !strings.Contains(result.ForLLM, "files/read_file: 4 calls, 1 errors (25%), avg 120ms") {
+		t.Fatalf("unexpected stats output: %q", result.ForLLM)
+	}
+
+	result = tool.Execute(context.Background(), map[string]any{"action": "stats", "server": "github"})
+	if !strings.Contains(result.ForLLM, "No MCP tool calls") {
+		t.Fatalf("expected empty stats for github, got %q", result.ForLLM)
+	}
+}
+
+func TestMCPAdminTool_TrafficAndStderr(t *testing.T) {
+	at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	controller := &fakeMCPServerController{
+		traffic: []MCPTrafficEntry{
+			{Time: at, Direction: "send", Message: `{"method":"initialize"}`},
+			{Time: at, Direction: "recv", Message: `{"result":{}}`},
+			{Time: at, Direction: "send", Message: `{"method":"tools/list"}`},
+		},
+		stderr: []string{"starting", "listening on stdio"},
+	}
+	tool := NewMCPAdminTool(controller)
+
+	result := tool.Execute(context.Background(), map[string]any{"action": "traffic", "server": "files", "limit": 2.0})
+	if result.IsError {
+		t.Fatalf("traffic failed: %s", result.ForLLM)
+	}
+	if !strings.Contains(result.ForLLM, "Last 2 of 3 JSON-RPC messages") ||
+		!strings.Contains(result.ForLLM, `03:04:05.000 send {"method":"tools/list"}`) ||
+		strings.Contains(result.ForLLM, "initialize") {
+		t.Fatalf("unexpected traffic output: %q", result.ForLLM)
+	}
+
+	result = tool.Execute(context.Background(), map[string]any{"action": "stderr", "server": "files"})
+	if result.IsError || !strings.Contains(result.ForLLM, "starting\nlistening on stdio") {
+		t.Fatalf("unexpected stderr output: %q", result.ForLLM)
+	}
+
+	controller.traffic = nil
+	result = tool.Execute(context.Background(), map[string]any{"action": "traffic", "server": "files"})
+	if !result.IsError || !strings.Contains(result.ForLLM, "recording is disabled") {
+		t.Fatalf("expected disabled recording error, got %q", result.ForLLM)
+	}
+	result = tool.Execute(context.Background(), map[string]any{"action": "stderr"})
+	if !result.IsError || !strings.Contains(result.ForLLM, "server is required") {
+		t.Fatalf("expected missing server error, got %q", result.ForLLM)
+	}
+}
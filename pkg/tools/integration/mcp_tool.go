@@ -34,6 +34,7 @@ type MCPTool struct {
 	manager            MCPManager
 	serverName         string
 	tool               *mcp.Tool
+	namePrefix         *string
 	mediaStore         media.MediaStore
 	workspace          string
 	maxInlineTextRunes int
@@ -67,6 +68,17 @@ func (t *MCPTool) SetWorkspace(workspace string) {
 	t.workspace = strings.TrimSpace(workspace)
 }
 
+// SetNamePrefix replaces the default "mcp_<server>_" tool name prefix. An
+// empty prefix exposes the tool under its bare (sanitized) MCP name.
+func (t *MCPTool) SetNamePrefix(prefix string) {
+	t.namePrefix = &prefix
+}
+
+// ServerName returns the name of the MCP server that provides the tool.
+func (t *MCPTool) ServerName() string {
+	return t.serverName
+}
+
 func (t *MCPTool) SetMaxInlineTextRunes(limit int) {
 	if limit > 0 {
 		t.maxInlineTextRunes = limit
@@ -135,7 +147,8 @@ func sanitizeIdentifierComponent(s string) string {
 	return result
 }
 
-// Name returns the tool name, prefixed with the server name.
+// Name returns the tool name, prefixed with the server name unless a custom
+// prefix was set with SetNamePrefix.
 // The total length is capped at 64 characters (OpenAI-compatible API limit).
 // A short hash of the original (unsanitized) server and tool names is appended
 // whenever sanitization is lossy or the name is truncated, ensuring that two
@@ -144,11 +157,22 @@ func (t *MCPTool) Name() string {
 	// Prefix with server name to avoid conflicts, and sanitize components
 	sanitizedServer := sanitizeIdentifierComponent(t.serverName)
 	sanitizedTool := sanitizeIdentifierComponent(t.tool.Name)
-	full := fmt.Sprintf("mcp_%s_%s", sanitizedServer, sanitizedTool)
+	prefix := fmt.Sprintf("mcp_%s_", sanitizedServer)
+	losslessPrefix := strings.ToLower(t.serverName) == sanitizedServer
+	if t.namePrefix != nil {
+		custom := strings.Trim(strings.TrimSpace(*t.namePrefix), "_")
+		prefix = ""
+		losslessPrefix = true
+		if custom != "" {
+			sanitizedPrefix := sanitizeIdentifierComponent(custom)
+			prefix = sanitizedPrefix + "_"
+			losslessPrefix = strings.ToLower(custom) == sanitizedPrefix
+		}
+	}
+	full := prefix + sanitizedTool
 
 	// Check if sanitization was lossless (only lowercasing, no char replacement/truncation)
-	lossless := strings.ToLower(t.serverName) == sanitizedServer &&
-		strings.ToLower(t.tool.Name) == sanitizedTool
+	lossless := losslessPrefix && strings.ToLower(t.tool.Name) == sanitizedTool
 
 	const maxTotal = 64
 	if lossless && len(full) <= maxTotal {
@@ -362,6 +386,7 @@ func (t *MCPTool) normalizeResultContent(ctx context.Context, content []mcp.Cont
 	llmParts := make([]string, 0, len(content))
 	rawTextParts := make([]string, 0, len(content))
 	mediaRefs := make([]string, 0, len(content))
+	artifactTags := make([]string, 0, len(content))
 
 	for _, c := range content {
 		switch v := c.(type) {
@@ -375,7 +400,7 @@ func (t *MCPTool) normalizeResultContent(ctx context.Context, content []mcp.Cont
 				llmParts = append(llmParts, safeText)
 			}
 		case *mcp.ImageContent:
-			ref, note := t.storeBinaryContent(
+			ref, note, artifactTag := t.storeBinaryContent(
 				ctx,
 				"image",
 				normalizedMIMEType(v.MIMEType),
@@ -385,11 +410,14 @@ func (t *MCPTool) normalizeResultContent(ctx context.Context, content []mcp.Cont
 			if ref != "" {
 				mediaRefs = append(mediaRefs, ref)
 			}
+			if artifactTag != "" {
+				artifactTags = append(artifactTags, artifactTag)
+			}
 			if note != "" {
 				llmParts = append(llmParts, note)
 			}
 		case *mcp.AudioContent:
-			ref, note := t.storeBinaryContent(
+			ref, note, artifactTag := t.storeBinaryContent(
 				ctx,
 				"audio",
 				normalizedMIMEType(v.MIMEType),
@@ -399,16 +427,22 @@ func (t *MCPTool) normalizeResultContent(ctx context.Context, content []mcp.Cont
 			if ref != "" {
 				mediaRefs = append(mediaRefs, ref)
 			}
+			if artifactTag != "" {
+				artifactTags = append(artifactTags, artifactTag)
+			}
 			if note != "" {
 				llmParts = append(llmParts, note)
 			}
 		case *mcp.ResourceLink:
 			llmParts = append(llmParts, summarizeResourceLink(v))
 		case *mcp.EmbeddedResource:
-			ref, note, rawText := t.storeEmbeddedResource(ctx, v)
+			ref, note, rawText, artifactTag := t.storeEmbeddedResource(ctx, v)
 			if ref != "" {
 				mediaRefs = append(mediaRefs, ref)
 			}
+			if artifactTag != "" {
+				artifactTags = append(artifactTags, artifactTag)
+			}
 			if rawText != "" {
 				rawTextParts = append(rawTextParts, rawText)
 			}
@@ -424,22 +458,50 @@ func (t *MCPTool) normalizeResultContent(ctx context.Context, content []mcp.Cont
 	rawText := strings.Join(compactStrings(rawTextParts), "\n")
 	if artifactResult := t.persistLargeTextArtifact(rawText); artifactResult != nil {
 		artifactResult.Media = mediaRefs
+		artifactResult.ArtifactTags = append(artifactResult.ArtifactTags, artifactTags...)
 		return artifactResult
 	}
 
 	result := &ToolResult{
-		ForLLM: forLLM,
+		// Without a workspace there is nowhere to save an oversized result, so
+		// keep its head and tail inline rather than the whole payload.
+		ForLLM: truncateHeadTail(forLLM, t.inlineTextLimit()),
 		Media:  mediaRefs,
 	}
+	if len(artifactTags) > 0 {
+		result.ArtifactTags = artifactTags
+	}
 	return result
 }
 
+func (t *MCPTool) inlineTextLimit() int {
+	if t.maxInlineTextRunes > 0 {
+		return t.maxInlineTextRunes
+	}
+	return maxMCPInlineTextRunes
+}
+
+// truncateHeadTail shortens text to about limit runes, keeping its beginning
+// and end and noting in between how much was dropped.
+func truncateHeadTail(text string, limit int) string {
+	runes := []rune(text)
+	if limit <= 0 || len(runes) <= limit {
+		return text
+	}
+	head := limit * 3 / 4
+	tail := limit - head
+	return fmt.Sprintf(
+		"%s\n\n[... MCP output truncated: %d of %d chars omitted ...]\n\n%s",
+		strings.TrimRight(string(runes[:head]), " \t\n"),
+		len(runes)-head-tail,
+		len(runes),
+		strings.TrimLeft(string(runes[len(runes)-tail:]), " \t\n"),
+	)
+}
+
 func (t *MCPTool) persistLargeTextArtifact(text string) *ToolResult {
 	text = strings.TrimSpace(text)
-	limit := t.maxInlineTextRunes
-	if limit <= 0 {
-		limit = maxMCPInlineTextRunes
-	}
+	limit := t.inlineTextLimit()
 	size := utf8.RuneCountInString(text)
 	if text == "" || size <= limit || t.workspace == "" {
 		return nil
@@ -496,29 +558,32 @@ func (t *MCPTool) largeTextArtifactFallback(text string, err error) *ToolResult
 	}
 }
 
-func (t *MCPTool) storeEmbeddedResource(ctx context.Context, content *mcp.EmbeddedResource) (string, string, string) {
+func (t *MCPTool) storeEmbeddedResource(
+	ctx context.Context,
+	content *mcp.EmbeddedResource,
+) (string, string, string, string) {
 	if content == nil || content.Resource == nil {
-		return "", "[MCP returned an embedded resource without data.]", ""
+		return "", "[MCP returned an embedded resource without data.]", "", ""
 	}
 
 	resource := content.Resource
 	if len(resource.Blob) > 0 {
-		ref, note := t.storeBinaryContent(
+		ref, note, artifactTag := t.storeBinaryContent(
 			ctx,
 			"resource",
 			normalizedMIMEType(resource.MIMEType),
 			resource.Blob,
 			content.Annotations,
 		)
-		return ref, note, ""
+		return ref, note, "", artifactTag
 	}
 
 	rawText := strings.TrimSpace(resource.Text)
 	if rawText != "" {
-		return "", sanitizeToolLLMContent(resource.Text), rawText
+		return "", sanitizeToolLLMContent(resource.Text), rawText, ""
 	}
 
-	return "", summarizeEmbeddedResource(content), ""
+	return "", summarizeEmbeddedResource(content), "", ""
 }
 
 func (t *MCPTool) storeBinaryContent(
@@ -527,54 +592,60 @@ func (t *MCPTool) storeBinaryContent(
 	mimeType string,
 	data []byte,
 	annotations *mcp.Annotations,
-) (string, string) {
+) (string, string, string) {
 	if len(data) == 0 {
-		return "", fmt.Sprintf("[MCP returned %s content (%s) but it was empty.]", kind, mimeType)
+		return "", fmt.Sprintf("[MCP returned %s content (%s) but it was empty.]", kind, mimeType), ""
 	}
 	if !annotationsAllowUser(annotations) {
 		return "", fmt.Sprintf(
 			"[MCP returned %s content (%s) for non-user audience; omitted from model context.]",
 			kind,
 			mimeType,
-		)
+		), ""
 	}
 	if t.mediaStore == nil {
+		if note, artifactTag := t.persistBinaryArtifact(kind, mimeType, data); artifactTag != "" {
+			return "", note, artifactTag
+		}
 		return "", fmt.Sprintf(
 			"[MCP returned %s content (%s); omitted from model context because media delivery is unavailable.]",
 			kind,
 			mimeType,
-		)
+		), ""
 	}
 
 	channel := ToolChannel(ctx)
 	chatID := ToolChatID(ctx)
 	if channel == "" || chatID == "" {
+		if note, artifactTag := t.persistBinaryArtifact(kind, mimeType, data); artifactTag != "" {
+			return "", note, artifactTag
+		}
 		return "", fmt.Sprintf(
 			"[MCP returned %s content (%s); omitted from model context because no target chat was available.]",
 			kind,
 			mimeType,
-		)
+		), ""
 	}
 
 	dir := media.TempDir()
 	if err := os.MkdirAll(dir, 0o700); err != nil {
-		return "", fmt.Sprintf("[MCP returned %s content (%s) but it could not be stored.]", kind, mimeType)
+		return "", fmt.Sprintf("[MCP returned %s content (%s) but it could not be stored.]", kind, mimeType), ""
 	}
 
 	ext := extensionForMIMEType(mimeType)
 	tmpFile, err := os.CreateTemp(dir, "mcp-*"+ext)
 	if err != nil {
-		return "", fmt.Sprintf("[MCP returned %s content (%s) but it could not be stored.]", kind, mimeType)
+		return "", fmt.Sprintf("[MCP returned %s content (%s) but it could not be stored.]", kind, mimeType), ""
 	}
 	tmpPath := tmpFile.Name()
 	if _, err = tmpFile.Write(data); err != nil {
 		_ = tmpFile.Close()
 		_ = os.Remove(tmpPath)
-		return "", fmt.Sprintf("[MCP returned %s content (%s) but it could not be stored.]", kind, mimeType)
+		return "", fmt.Sprintf("[MCP returned %s content (%s) but it could not be stored.]", kind, mimeType), ""
 	}
 	if err = tmpFile.Close(); err != nil {
 		_ = os.Remove(tmpPath)
-		return "", fmt.Sprintf("[MCP returned %s content (%s) but it could not be stored.]", kind, mimeType)
+		return "", fmt.Sprintf("[MCP returned %s content (%s) but it could not be stored.]", kind, mimeType), ""
 	}
 
 	scope := fmt.Sprintf(
@@ -606,14 +677,71 @@ func (t *MCPTool) storeBinaryContent(
 			"[MCP returned %s content (%s) but it could not be registered as media.]",
 			kind,
 			mimeType,
-		)
+		), ""
 	}
 
 	return ref, fmt.Sprintf(
 		"[MCP returned %s content (%s); omitted from model context and stored as a local media artifact.]",
 		kind,
 		mimeType,
+	), ""
+}
+
+// persistBinaryArtifact saves binary MCP content under the workspace when it
+// cannot be delivered as media, so the agent can still reference the file
+// (e.g. a screenshot) by path. It returns an empty tag when no workspace is
+// configured or the file could not be written.
+func (t *MCPTool) persistBinaryArtifact(kind, mimeType string, data []byte) (string, string) {
+	if t.workspace == "" {
+		return "", ""
+	}
+
+	dir := filepath.Join(t.workspace, ".artifacts", "mcp")
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		t.logBinaryArtifactFailure(kind, mimeType, err)
+		return "", ""
+	}
+
+	pattern := fmt.Sprintf(
+		"%s_%s_*%s",
+		sanitizeIdentifierComponent(t.serverName),
+		sanitizeIdentifierComponent(t.tool.Name),
+		extensionForMIMEType(mimeType),
 	)
+	tmpFile, err := os.CreateTemp(dir, pattern)
+	if err != nil {
+		t.logBinaryArtifactFailure(kind, mimeType, err)
+		return "", ""
+	}
+	path := tmpFile.Name()
+	if _, err = tmpFile.Write(data); err != nil {
+		_ = tmpFile.Close()
+		_ = os.Remove(path)
+		t.logBinaryArtifactFailure(kind, mimeType, err)
+		return "", ""
+	}
+	if err = tmpFile.Close(); err != nil {
+		_ = os.Remove(path)
+		t.logBinaryArtifactFailure(kind, mimeType, err)
+		return "", ""
+	}
+
+	return fmt.Sprintf(
+		"[MCP returned %s content (%s, %d bytes); omitted from model context and saved as a local artifact.]",
+		kind,
+		mimeType,
+		len(data),
+	), "[file:" + path + "]"
+}
+
+func (t *MCPTool) logBinaryArtifactFailure(kind, mimeType string, err error) {
+	logger.WarnCF("tool", "Failed to persist MCP binary artifact", map[string]any{
+		"server":    t.serverName,
+		"tool":      t.tool.Name,
+		"kind":      kind,
+		"mime_type": mimeType,
+		"error":     err.Error(),
+	})
 }
 
 func summarizeResourceLink(content *mcp.ResourceLink) string {
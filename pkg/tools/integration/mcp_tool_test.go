@@ -107,6 +107,42 @@ func TestMCPTool_Name(t *testing.T) {
 	}
 }
 
+func TestMCPTool_NameWithCustomPrefix(t *testing.T) {
+	tests := []struct {
+		name     string
+		prefix   string
+		toolName string
+		expected string
+	}{
+		{name: "custom prefix", prefix: "gh", toolName: "create_issue", expected: "gh_create_issue"},
+		{name: "trailing underscore", prefix: "gh_", toolName: "create_issue", expected: "gh_create_issue"},
+		{name: "no prefix", prefix: "", toolName: "create_issue", expected: "create_issue"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mcpTool := NewMCPTool(&MockMCPManager{}, "github", &mcp.Tool{Name: tt.toolName})
+			mcpTool.SetNamePrefix(tt.prefix)
+			if got := mcpTool.Name(); got != tt.expected {
+				t.Errorf("Name() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMCPTool_NameWithoutPrefixStillCapsLength(t *testing.T) {
+	mcpTool := NewMCPTool(&MockMCPManager{}, "github", &mcp.Tool{Name: strings.Repeat("a", 80)})
+	mcpTool.SetNamePrefix("")
+
+	name := mcpTool.Name()
+	if len(name) > 64 {
+		t.Fatalf("len(Name()) = %d, want <= 64", len(name))
+	}
+	if !strings.HasPrefix(name, "aaaa") {
+		t.Fatalf("Name() = %q, want bare tool name without server prefix", name)
+	}
+}
+
 func TestMCPTool_PromptMetadata(t *testing.T) {
 	manager := &MockMCPManager{}
 	tool := NewMCPTool(manager, "GitHub Server", &mcp.Tool{Name: "create_issue"})
@@ -705,6 +741,59 @@ func TestMCPTool_Execute_RespectsUserAudienceForBinaryContent(t *testing.T) {
 	}
 }
 
+func TestMCPTool_Execute_ImageContentSavedToWorkspaceWithoutMediaStore(t *testing.T) {
+	workspace := t.TempDir()
+	manager := &MockMCPManager{
+		callToolFunc: func(ctx context.Context, serverName, toolName string, arguments map[string]any) (*mcp.CallToolResult, error) {
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{
+					&mcp.TextContent{Text: "chart rendered"},
+					&mcp.ImageContent{
+						Data:     []byte("fake-chart-bytes"),
+						MIMEType: "image/png",
+					},
+				},
+			}, nil
+		},
+	}
+
+	mcpTool := NewMCPTool(manager, "charts", &mcp.Tool{Name: "render_chart"})
+	mcpTool.SetWorkspace(workspace)
+
+	result := mcpTool.Execute(context.Background(), nil)
+
+	if result.IsError {
+		t.Fatalf("expected success, got %q", result.ForLLM)
+	}
+	if len(result.Media) != 0 {
+		t.Fatalf("expected no media refs without a media store, got %d", len(result.Media))
+	}
+	if !strings.Contains(result.ForLLM, "chart rendered") {
+		t.Fatalf("expected text content to be kept, got %q", result.ForLLM)
+	}
+	if !strings.Contains(result.ForLLM, "saved as a local artifact") {
+		t.Fatalf("expected local artifact note, got %q", result.ForLLM)
+	}
+	if len(result.ArtifactTags) != 1 {
+		t.Fatalf("expected 1 artifact tag, got %+v", result.ArtifactTags)
+	}
+
+	path := strings.TrimSuffix(strings.TrimPrefix(result.ArtifactTags[0], "[file:"), "]")
+	if filepath.Dir(path) != filepath.Join(workspace, ".artifacts", "mcp") {
+		t.Fatalf("expected artifact under workspace, got %q", path)
+	}
+	if filepath.Ext(path) != ".png" {
+		t.Fatalf("expected png artifact, got %q", path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("expected artifact file to be readable: %v", err)
+	}
+	if string(data) != "fake-chart-bytes" {
+		t.Fatalf("expected artifact bytes to match input, got %q", string(data))
+	}
+}
+
 func TestMCPTool_Execute_LargeBase64TextIsOmittedFromContext(t *testing.T) {
 	manager := &MockMCPManager{
 		callToolFunc: func(ctx context.Context, serverName, toolName string, arguments map[string]any) (*mcp.CallToolResult, error) {
@@ -840,6 +929,37 @@ func TestMCPTool_Execute_CustomInlineTextThreshold(t *testing.T) {
 	}
 }
 
+func TestMCPTool_Execute_LargeTextWithoutWorkspaceKeepsHeadAndTail(t *testing.T) {
+	text := "BEGIN " + strings.Repeat("middle ", 200) + "END"
+	manager := &MockMCPManager{
+		callToolFunc: func(ctx context.Context, serverName, toolName string, arguments map[string]any) (*mcp.CallToolResult, error) {
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{
+					&mcp.TextContent{Text: text},
+				},
+			}, nil
+		},
+	}
+
+	mcpTool := NewMCPTool(manager, "test_server", &mcp.Tool{Name: "dump_payload"})
+	mcpTool.SetMaxInlineTextRunes(100)
+
+	result := mcpTool.Execute(context.Background(), nil)
+
+	if !strings.HasPrefix(result.ForLLM, "BEGIN") || !strings.HasSuffix(result.ForLLM, "END") {
+		t.Fatalf("expected head and tail to be kept, got %q", result.ForLLM)
+	}
+	if !strings.Contains(result.ForLLM, "MCP output truncated") {
+		t.Fatalf("expected truncation note, got %q", result.ForLLM)
+	}
+	if len(result.ForLLM) >= len(text) {
+		t.Fatalf("expected ForLLM to be shorter than the source text (%d >= %d)", len(result.ForLLM), len(text))
+	}
+	if len(result.ArtifactTags) != 0 {
+		t.Fatalf("expected no artifacts without a workspace, got %v", result.ArtifactTags)
+	}
+}
+
 func TestMCPTool_Execute_LargeTextArtifactFailureStillOmitsContext(t *testing.T) {
 	workspaceRoot := t.TempDir()
 	workspaceFile := filepath.Join(workspaceRoot, "not-a-directory")
@@ -15,6 +15,11 @@ type (
 	ReactionCallback         = integrationtools.ReactionCallback
 	MCPManager               = integrationtools.MCPManager
 	MCPTool                  = integrationtools.MCPTool
+	MCPAdminTool             = integrationtools.MCPAdminTool
+	MCPServerController      = integrationtools.MCPServerController
+	MCPServerStatus          = integrationtools.MCPServerStatus
+	MCPToolStats             = integrationtools.MCPToolStats
+	MCPTrafficEntry          = integrationtools.MCPTrafficEntry
 	FindSkillsTool           = integrationtools.FindSkillsTool
 	InstallSkillTool         = integrationtools.InstallSkillTool
 	MessageTool              = integrationtools.MessageTool
@@ -42,6 +47,10 @@ func NewMCPTool(manager MCPManager, serverName string, tool *mcp.Tool) *MCPTool
 	return integrationtools.NewMCPTool(manager, serverName, tool)
 }
 
+func NewMCPAdminTool(controller MCPServerController) *MCPAdminTool {
+	return integrationtools.NewMCPAdminTool(controller)
+}
+
 func NewFindSkillsTool(registryMgr *skills.RegistryManager, cache *skills.SearchCache) *FindSkillsTool {
 	return integrationtools.NewFindSkillsTool(registryMgr, cache)
 }
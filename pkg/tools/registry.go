@@ -23,7 +23,7 @@ type ToolEntry struct {
 type ToolRegistry struct {
 	tools      map[string]*ToolEntry
 	mu         sync.RWMutex
-	version    atomic.Uint64 // incremented on Register/RegisterHidden for cache invalidation
+	version    atomic.Uint64 // incremented on Register/RegisterHidden/Unregister for cache invalidation
 	mediaStore media.MediaStore
 	allowlist  map[string]struct{}
 }
@@ -117,6 +117,19 @@ func (r *ToolRegistry) RegisterHidden(tool Tool) {
 	logger.DebugCF("tools", "Registered hidden tool", map[string]any{"name": name})
 }
 
+// Unregister removes a tool by name. It reports whether the tool was present.
+func (r *ToolRegistry) Unregister(name string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.tools[name]; !exists {
+		return false
+	}
+	delete(r.tools, name)
+	r.version.Add(1)
+	logger.DebugCF("tools", "Unregistered tool", map[string]any{"name": name})
+	return true
+}
+
 // SetMediaStore injects a MediaStore into all registered tools that can
 // consume it, and remembers it for future registrations.
 func (r *ToolRegistry) SetMediaStore(store media.MediaStore) {
@@ -192,6 +205,18 @@ func (r *ToolRegistry) HasRegistered(name string) bool {
 	return ok
 }
 
+// GetRegistered returns a registered tool by name, including hidden tools
+// whose TTL is currently zero.
+func (r *ToolRegistry) GetRegistered(name string) (Tool, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	entry, ok := r.tools[name]
+	if !ok {
+		return nil, false
+	}
+	return entry.Tool, true
+}
+
 // HiddenToolSnapshot holds a consistent snapshot of hidden tools and the
 // registry version at which it was taken. Used by BM25SearchTool cache.
 type HiddenToolSnapshot struct {
@@ -193,6 +193,29 @@ func TestToolRegistry_RegisterOverwrite(t *testing.T) {
 	}
 }
 
+func TestToolRegistry_Unregister(t *testing.T) {
+	r := NewToolRegistry()
+	r.Register(newMockTool("visible", "visible"))
+	r.RegisterHidden(newMockTool("hidden", "hidden"))
+	before := r.Version()
+
+	if !r.Unregister("hidden") {
+		t.Fatal("expected hidden tool to be unregistered")
+	}
+	if r.HasRegistered("hidden") {
+		t.Fatal("hidden tool should be gone after Unregister")
+	}
+	if r.Version() == before {
+		t.Fatal("expected Unregister to bump the registry version")
+	}
+	if r.Unregister("hidden") {
+		t.Fatal("expected second Unregister to report missing tool")
+	}
+	if _, ok := r.Get("visible"); !ok {
+		t.Fatal("unrelated tools should stay registered")
+	}
+}
+
 func TestToolRegistry_Execute_Success(t *testing.T) {
 	r := NewToolRegistry()
 	r.Register(&mockRegistryTool{
@@ -15,6 +15,7 @@ const (
 	noNewlineAtEOFMarker       = `\ No newline at end of file`
 	diffPreviewSkippedMessage  = "[diff preview skipped: file too large for inline preview]"
 	diffPreviewTruncatedNote   = "[diff preview truncated; call read_file for the full edited contents]"
+	dryRunTruncatedNote        = "[diff preview truncated]"
 	maxDiffInputBytes          = 64 * 1024
 	maxDiffInputLines          = 2000
 	maxUserDiffPreviewBytes    = 16 * 1024
@@ -57,6 +58,28 @@ func DiffResult(path string, before, after []byte) *ToolResult {
 	}
 }
 
+// DiffPreviewResult reports the unified diff a write would produce when the
+// file has not been changed. Unlike DiffResult the diff is also given to the
+// LLM, since reviewing it is the point of a preview.
+func DiffPreviewResult(path string, before, after []byte) *ToolResult {
+	summary := fmt.Sprintf("Preview of changes to %s (nothing was written)", path)
+	if exceedsDiffPreviewLimits(before, after) {
+		return UserResult(summary + "\n" + diffPreviewSkippedMessage)
+	}
+
+	diff, err := buildUnifiedDiff(path, before, after)
+	if err != nil {
+		return UserResult(fmt.Sprintf("%s\n[diff unavailable: %v]", summary, err))
+	}
+
+	preview, truncated := truncateDiffPreview(diff, maxUserDiffPreviewBytes)
+	content := fmt.Sprintf("%s\n```diff\n%s\n```", summary, preview)
+	if truncated {
+		content += "\n" + dryRunTruncatedNote
+	}
+	return UserResult(content)
+}
+
 func buildUnifiedDiff(path string, before, after []byte) (string, error) {
 	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
 		A:        splitDiffLinesPreservingEOF(before),
@@ -45,6 +45,27 @@ func TestDiffResult_UserVisibleUnifiedDiff(t *testing.T) {
 	}
 }
 
+func TestDiffPreviewResult_IncludesDiffForLLM(t *testing.T) {
+	result := DiffPreviewResult("notes.txt", []byte("one\ntwo\n"), []byte("one\n2\n"))
+
+	if result.Silent || result.IsError {
+		t.Fatalf("expected a visible, successful preview, got %+v", result)
+	}
+	if result.ForLLM != result.ForUser {
+		t.Fatal("expected the preview diff to be shared with the model")
+	}
+	for _, want := range []string{
+		"Preview of changes to notes.txt (nothing was written)",
+		"```diff",
+		"-two",
+		"+2",
+	} {
+		if !strings.Contains(result.ForLLM, want) {
+			t.Fatalf("DiffPreviewResult output missing %q:\n%s", want, result.ForLLM)
+		}
+	}
+}
+
 func TestBuildUnifiedDiff_NoContentChange(t *testing.T) {
 	diff, err := buildUnifiedDiff("test.txt", []byte("same\n"), []byte("same\n"))
 	if err != nil {
@@ -45,7 +45,10 @@ type ExecTool struct {
 	allowedPathPatterns []*regexp.Regexp
 	restrictToWorkspace bool
 	allowRemote         bool
-	sessionManager      *SessionManager
+	// envAllowlist is nil when children inherit the whole environment.
+	envAllowlist   []string
+	profile        *execProfile
+	sessionManager *SessionManager
 }
 
 var (
@@ -198,6 +201,25 @@ func NewExecToolWithConfig(
 		timeout = time.Duration(cfg.Tools.Exec.TimeoutSeconds) * time.Second
 	}
 
+	var envAllowlist []string
+	if cfg != nil && cfg.Tools.Exec.FilterEnv {
+		envAllowlist = append([]string{}, cfg.Tools.Exec.EnvAllowlist...)
+	}
+
+	var profile *execProfile
+	if cfg != nil {
+		active, err := cfg.Tools.Exec.ActiveProfile()
+		if err != nil {
+			return nil, err
+		}
+		if active != nil {
+			profile = newExecProfile(strings.TrimSpace(cfg.Tools.Exec.Profile), active)
+			if active.TimeoutSeconds > 0 {
+				timeout = time.Duration(active.TimeoutSeconds) * time.Second
+			}
+		}
+	}
+
 	return &ExecTool{
 		workingDir:          workingDir,
 		timeout:             timeout,
@@ -207,6 +229,8 @@ func NewExecToolWithConfig(
 		allowedPathPatterns: allowedPathPatterns,
 		restrictToWorkspace: restrict,
 		allowRemote:         allowRemote,
+		envAllowlist:        envAllowlist,
+		profile:             profile,
 		sessionManager:      getSessionManager(),
 	}, nil
 }
@@ -215,8 +239,13 @@ func (t *ExecTool) Name() string {
 	return "exec"
 }
 
+const execDescription = `Execute shell commands. Use background=true for long-running commands (returns sessionId). Use pty=true for interactive commands (can combine with background=true). Use poll/read/write/send-keys/kill with sessionId to manage background sessions. Sessions auto-cleanup 30 minutes after process exits; use kill to terminate early. Output buffer limit: 1MB.`
+
 func (t *ExecTool) Description() string {
-	return `Execute shell commands. Use background=true for long-running commands (returns sessionId). Use pty=true for interactive commands (can combine with background=true). Use poll/read/write/send-keys/kill with sessionId to manage background sessions. Sessions auto-cleanup 30 minutes after process exits; use kill to terminate early. Output buffer limit: 1MB.`
+	if t.profile == nil {
+		return execDescription
+	}
+	return execDescription + " " + t.profile.summary()
 }
 
 //nolint:dupl // Tool parameter schemas intentionally use similar JSON-schema map literals.
@@ -403,6 +432,7 @@ func (t *ExecTool) runSync(ctx context.Context, command, cwd string) *ToolResult
 	if cwd != "" {
 		cmd.Dir = cwd
 	}
+	t.applyEnv(cmd)
 
 	prepareCommandForTermination(cmd)
 
@@ -525,6 +555,7 @@ func (t *ExecTool) runBackground(ctx context.Context, command, cwd string, ptyEn
 	if cwd != "" {
 		cmd.Dir = cwd
 	}
+	t.applyEnv(cmd)
 
 	prepareCommandForTermination(cmd)
 
@@ -1162,6 +1193,13 @@ func (t *ExecTool) guardCommand(command, cwd string) string {
 		}
 	}
 
+	// Custom allow rules do not widen the profile either.
+	if t.profile != nil {
+		if reason := t.profile.check(cmd); reason != "" {
+			return reason
+		}
+	}
+
 	if len(t.allowPatterns) > 0 {
 		if !t.commandMatchesAllowPattern(lower) {
 			return "Command blocked by safety guard (not in allowlist)"
@@ -1406,6 +1444,14 @@ func localPathExists(cwd, token string) bool {
 	return err == nil && info != nil
 }
 
+// applyEnv restricts the environment cmd inherits when filtering is
+// configured, so commands do not see secrets they were not granted.
+func (t *ExecTool) applyEnv(cmd *exec.Cmd) {
+	if t.envAllowlist != nil {
+		cmd.Env = filterExecEnv(os.Environ(), t.envAllowlist)
+	}
+}
+
 func (t *ExecTool) SetTimeout(timeout time.Duration) {
 	t.timeout = timeout
 }
@@ -0,0 +1,56 @@
+package tools
+
+import (
+	"runtime"
+	"strings"
+)
+
+// baseExecEnv lists the variables commands need to run at all. They are passed
+// to exec children even when the environment is filtered.
+var baseExecEnv = []string{
+	"PATH", "HOME", "USER", "LOGNAME", "SHELL", "LANG", "LANGUAGE", "LC_*", "TERM", "TZ", "TMPDIR", "PWD",
+}
+
+// baseWindowsExecEnv adds what Windows programs and PowerShell expect.
+var baseWindowsExecEnv = []string{
+	"SYSTEMROOT", "SYSTEMDRIVE", "WINDIR", "COMSPEC", "PATHEXT", "TEMP", "TMP",
+	"USERPROFILE", "USERNAME", "APPDATA", "LOCALAPPDATA", "PROGRAMDATA", "PROGRAMFILES", "PROGRAMFILES(X86)",
+	"COMPUTERNAME", "PSMODULEPATH", "NUMBER_OF_PROCESSORS", "PROCESSOR_ARCHITECTURE",
+}
+
+// filterExecEnv returns the entries of environ whose names are in the base set
+// or match allowlist. Allowlist entries are exact names or a prefix ending in
+// "*", such as "OPENAI_*".
+func filterExecEnv(environ []string, allowlist []string) []string {
+	patterns := append(append([]string{}, baseExecEnv...), allowlist...)
+	if runtime.GOOS == "windows" {
+		patterns = append(patterns, baseWindowsExecEnv...)
+	}
+	filtered := make([]string, 0, len(patterns))
+	for _, item := range environ {
+		name, _, ok := strings.Cut(item, "=")
+		if !ok || name == "" {
+			continue
+		}
+		for _, pattern := range patterns {
+			if envNameMatches(pattern, name) {
+				filtered = append(filtered, item)
+				break
+			}
+		}
+	}
+	return filtered
+}
+
+// envNameMatches compares case-insensitively on Windows, where environment
+// variable names are not case sensitive.
+func envNameMatches(pattern, name string) bool {
+	if runtime.GOOS == "windows" {
+		pattern = strings.ToUpper(pattern)
+		name = strings.ToUpper(name)
+	}
+	if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+		return strings.HasPrefix(name, prefix)
+	}
+	return pattern == name
+}
@@ -0,0 +1,291 @@
+package tools
+
+import (
+	"fmt"
+	"runtime"
+	"slices"
+	"strings"
+
+	"github.com/sipeed/picoclaw/pkg/config"
+)
+
+// safeProfileAssignments are the variables a command may set for itself under
+// a profile with an allowlist. Others, such as PATH, LD_PRELOAD or
+// GIT_EXTERNAL_DIFF, can make an allowed binary run another program.
+var safeProfileAssignments = []string{"LANG", "LANGUAGE", "LC_*", "TZ", "NO_COLOR"}
+
+// execProfile is the tools.exec profile the exec tool enforces, on top of the
+// deny patterns and workspace checks of the safety guard.
+type execProfile struct {
+	name           string
+	allowed        [][]string
+	deniedFlags    []string
+	allowRedirects bool
+}
+
+func newExecProfile(name string, profile *config.ExecProfile) *execProfile {
+	p := &execProfile{
+		name:           name,
+		deniedFlags:    profile.DeniedFlags,
+		allowRedirects: profile.AllowRedirects,
+	}
+	for _, entry := range profile.AllowedCommands {
+		if words := strings.Fields(entry); len(words) > 0 {
+			p.allowed = append(p.allowed, words)
+		}
+	}
+	return p
+}
+
+// check returns why command is blocked by the profile, or "" when it may run.
+func (p *execProfile) check(command string) string {
+	parsed := parseShellCommand(command)
+	blocked := func(reason string) string {
+		return fmt.Sprintf("Command blocked by exec profile %q (%s)", p.name, reason)
+	}
+	// Substitutions run commands the allowlist cannot see.
+	if len(p.allowed) > 0 && parsed.substitution {
+		return blocked("command substitution is not allowed")
+	}
+	if len(p.allowed) > 0 {
+		for _, name := range parsed.assignments {
+			if !slices.ContainsFunc(safeProfileAssignments, func(pattern string) bool {
+				return envNameMatches(pattern, name)
+			}) {
+				return blocked(fmt.Sprintf("setting %s for a command is not allowed", name))
+			}
+		}
+	}
+	if !p.allowRedirects && parsed.fileRedirect {
+		return blocked("redirecting output to files is not allowed")
+	}
+	for _, words := range parsed.commands {
+		if len(p.allowed) > 0 && !p.allows(words) {
+			return blocked(fmt.Sprintf("%s is not an allowed command", words[0]))
+		}
+		for _, entry := range p.deniedFlags {
+			// Leading words scope the flag to commands starting with them.
+			fields := strings.Fields(entry)
+			if len(fields) == 0 {
+				continue
+			}
+			scope, flag := fields[:len(fields)-1], fields[len(fields)-1]
+			if len(words) < len(scope) || !slices.Equal(words[:len(scope)], scope) {
+				continue
+			}
+			for _, word := range words[max(len(scope), 1):] {
+				if flagMatches(word, flag) {
+					return blocked(fmt.Sprintf("%s is not allowed", entry))
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// allows reports whether words starts with one of the allowed commands. The
+// binary must be given by name, so a script named like an allowed binary
+// cannot be run through a path.
+func (p *execProfile) allows(words []string) bool {
+	for _, allowed := range p.allowed {
+		if len(words) < len(allowed) {
+			continue
+		}
+		match := true
+		for i, word := range allowed {
+			got := words[i]
+			if i == 0 && runtime.GOOS == "windows" {
+				got = strings.TrimSuffix(strings.ToLower(got), ".exe")
+				word = strings.ToLower(word)
+			}
+			if got != word {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
+
+// summary describes the profile for the tool description.
+func (p *execProfile) summary() string {
+	if len(p.allowed) == 0 {
+		return fmt.Sprintf("Exec profile %q is active.", p.name)
+	}
+	names := make([]string, 0, len(p.allowed))
+	for _, words := range p.allowed {
+		names = append(names, strings.Join(words, " "))
+	}
+	return fmt.Sprintf("Exec profile %q is active: only these commands may run: %s.", p.name, strings.Join(names, ", "))
+}
+
+// parsedShellCommand is a shell command line split into simple commands.
+type parsedShellCommand struct {
+	// commands holds the words of each simple command, without leading
+	// variable assignments and redirections.
+	commands [][]string
+	// assignments holds the names of variables set before a command name.
+	assignments []string
+	// substitution is set for $(...), backticks and process substitution.
+	substitution bool
+	// fileRedirect is set for > and >> into anything but /dev/null.
+	fileRedirect bool
+}
+
+// parseShellCommand splits command on the POSIX shell operators ; & | ( ) and
+// newlines, honouring quotes and, outside Windows, backslash escapes. It is an
+// approximation of shell grammar meant for allowlisting: anything it does not
+// understand ends up as a word and fails the allowlist check rather than
+// slipping through.
+func parseShellCommand(command string) parsedShellCommand {
+	var (
+		parsed       parsedShellCommand
+		words        []string
+		word         strings.Builder
+		inWord       bool
+		redirectNext bool
+		fileTarget   bool
+	)
+	endWord := func() {
+		if !inWord {
+			return
+		}
+		w := word.String()
+		word.Reset()
+		inWord = false
+		if redirectNext {
+			redirectNext = false
+			if fileTarget && w != "/dev/null" {
+				parsed.fileRedirect = true
+			}
+			return
+		}
+		// Skip variable assignments before the command name.
+		if len(words) == 0 && isShellAssignment(w) {
+			name, _, _ := strings.Cut(w, "=")
+			parsed.assignments = append(parsed.assignments, name)
+			return
+		}
+		words = append(words, w)
+	}
+	endCommand := func() {
+		endWord()
+		if len(words) > 0 {
+			parsed.commands = append(parsed.commands, words)
+		}
+		words = nil
+	}
+
+	runes := []rune(command)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == '\\' && i+1 < len(runes) && runtime.GOOS != "windows":
+			i++
+			word.WriteRune(runes[i])
+			inWord = true
+		case r == '\'':
+			inWord = true
+			for i++; i < len(runes) && runes[i] != '\''; i++ {
+				word.WriteRune(runes[i])
+			}
+		case r == '"':
+			inWord = true
+			for i++; i < len(runes) && runes[i] != '"'; i++ {
+				switch {
+				case runes[i] == '\\' && i+1 < len(runes):
+					i++
+					word.WriteRune(runes[i])
+				case runes[i] == '`' || (runes[i] == '$' && i+1 < len(runes) && runes[i+1] == '('):
+					parsed.substitution = true
+					word.WriteRune(runes[i])
+				default:
+					word.WriteRune(runes[i])
+				}
+			}
+		case r == '`' || (r == '$' && i+1 < len(runes) && runes[i+1] == '('):
+			parsed.substitution = true
+			endCommand()
+			if r == '$' {
+				i++
+			}
+		case (r == '<' || r == '>') && i+1 < len(runes) && runes[i+1] == '(':
+			parsed.substitution = true
+			endCommand()
+			i++
+		case r == '>' || r == '<':
+			// A file descriptor number before the operator is not a word.
+			if inWord && isAllDigits(word.String()) {
+				word.Reset()
+				inWord = false
+			}
+			endWord()
+			if i+1 < len(runes) && (runes[i+1] == '>' || runes[i+1] == '<') {
+				i++
+			}
+			if i+1 < len(runes) && runes[i+1] == '&' {
+				// Duplicating a descriptor, as in 2>&1, writes no file.
+				i++
+				for i+1 < len(runes) && (runes[i+1] == '-' || (runes[i+1] >= '0' && runes[i+1] <= '9')) {
+					i++
+				}
+				continue
+			}
+			redirectNext = true
+			fileTarget = r == '>'
+		case r == ';' || r == '&' || r == '|' || r == '(' || r == ')' || r == '\n':
+			endCommand()
+		case r == ' ' || r == '\t' || r == '\r':
+			endWord()
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+	endCommand()
+	return parsed
+}
+
+// flagMatches reports whether word passes flag, also as "--flag=value" and,
+// for a single-letter flag, inside a group of short options such as "-uo". A
+// flag ending in "*" matches every flag with that prefix.
+func flagMatches(word, flag string) bool {
+	if prefix, ok := strings.CutSuffix(flag, "*"); ok {
+		return prefix != "" && strings.HasPrefix(word, prefix)
+	}
+	if word == flag || strings.HasPrefix(word, flag+"=") {
+		return true
+	}
+	if len(flag) == 2 && flag[0] == '-' && flag[1] != '-' {
+		return len(word) > 1 && word[0] == '-' && word[1] != '-' && strings.IndexByte(word[1:], flag[1]) >= 0
+	}
+	return false
+}
+
+func isShellAssignment(word string) bool {
+	name, _, ok := strings.Cut(word, "=")
+	if !ok || name == "" {
+		return false
+	}
+	for i, r := range name {
+		if r != '_' && (r < 'A' || r > 'Z') && (r < 'a' || r > 'z') && (i == 0 || r < '0' || r > '9') {
+			return false
+		}
+	}
+	return true
+}
+
+func isAllDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -1987,3 +1987,181 @@ func TestShellTool_CustomAllowDoesNotBecomeStrictAllowlist(t *testing.T) {
 		t.Fatalf("custom allow patterns should not become a strict allowlist, got: %q", got)
 	}
 }
+
+func TestFilterExecEnv(t *testing.T) {
+	environ := []string{
+		"PATH=/usr/bin", "HOME=/home/u", "LC_ALL=C", "OPENAI_API_KEY=sk-1", "OPENAI_BASE=x",
+		"GITHUB_TOKEN=gh", "AWS_SECRET_ACCESS_KEY=aws", "=C:=C:\\",
+	}
+	got := filterExecEnv(environ, []string{"GITHUB_TOKEN", "OPENAI_*"})
+	want := []string{"PATH=/usr/bin", "HOME=/home/u", "LC_ALL=C", "OPENAI_API_KEY=sk-1", "OPENAI_BASE=x", "GITHUB_TOKEN=gh"}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("filterExecEnv() = %v, want %v", got, want)
+	}
+}
+
+func TestShellTool_FilterEnv(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	t.Setenv("PICOCLAW_TEST_SECRET", "secret-value")
+	t.Setenv("PICOCLAW_TEST_GRANTED", "granted-value")
+	cfg := &config.Config{
+		Tools: config.ToolsConfig{
+			Exec: config.ExecConfig{
+				AllowRemote:  true,
+				FilterEnv:    true,
+				EnvAllowlist: []string{"PICOCLAW_TEST_GRANTED"},
+			},
+		},
+	}
+	tool, err := NewExecToolWithConfig("", false, cfg)
+	require.NoError(t, err)
+
+	result := tool.Execute(context.Background(), map[string]any{
+		"action":  "run",
+		"command": `echo "[$PICOCLAW_TEST_SECRET][$PICOCLAW_TEST_GRANTED]"`,
+	})
+	require.False(t, result.IsError, result.ForLLM)
+	if !strings.Contains(result.ForLLM, "[][granted-value]") {
+		t.Errorf("expected only the granted variable, got: %s", result.ForLLM)
+	}
+}
+
+func TestParseShellCommand(t *testing.T) {
+	tests := []struct {
+		command      string
+		commands     [][]string
+		substitution bool
+		fileRedirect bool
+	}{
+		{"ls -la", [][]string{{"ls", "-la"}}, false, false},
+		{"git status && git log -1 | head", [][]string{{"git", "status"}, {"git", "log", "-1"}, {"head"}}, false, false},
+		{"FOO=1 go test ./... 2>&1", [][]string{{"go", "test", "./..."}}, false, false},
+		{`grep "a;b" 'c|d' x\;y`, [][]string{{"grep", "a;b", "c|d", "x;y"}}, false, false},
+		{"echo hi > out.txt", [][]string{{"echo", "hi"}}, false, true},
+		{"cat x 2>/dev/null", [][]string{{"cat", "x"}}, false, false},
+		{"echo $(rm -rf x)", [][]string{{"echo"}, {"rm", "-rf", "x"}}, true, false},
+		{"echo \"`id`\"", [][]string{{"echo", "`id`"}}, true, false},
+		{"(cd sub; make)", [][]string{{"cd", "sub"}, {"make"}}, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.command, func(t *testing.T) {
+			got := parseShellCommand(tt.command)
+			require.Equal(t, tt.commands, got.commands)
+			require.Equal(t, tt.substitution, got.substitution)
+			require.Equal(t, tt.fileRedirect, got.fileRedirect)
+		})
+	}
+}
+
+func TestShellTool_ReadOnlyProfile(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	cfg := &config.Config{
+		Tools: config.ToolsConfig{
+			Exec: config.ExecConfig{
+				AllowRemote: true,
+				Profile:     config.ExecProfileReadOnly,
+			},
+		},
+	}
+	tool, err := NewExecToolWithConfig(t.TempDir(), false, cfg)
+	require.NoError(t, err)
+	if !strings.Contains(tool.Description(), `Exec profile "read-only" is active`) {
+		t.Errorf("description should name the active profile, got: %s", tool.Description())
+	}
+
+	allowed := []string{
+		"echo ok | wc -l", "LC_ALL=C sort x.txt 2>/dev/null; true", "grep -o x y 2>/dev/null; true",
+		"git branch --list 2>/dev/null; true", "git branch --show-current 2>/dev/null; true",
+	}
+	for _, command := range allowed {
+		result := tool.Execute(context.Background(), map[string]any{"action": "run", "command": command})
+		require.False(t, result.IsError, result.ForLLM)
+	}
+
+	blocked := map[string]string{
+		"touch new.txt":                         "touch is not an allowed command",
+		"git commit -m x":                       "git is not an allowed command",
+		"echo x > out.txt":                      "redirecting output to files",
+		"find . -delete":                        "-delete is not allowed",
+		"ls $(printf /)":                        "command substitution",
+		"./ls":                                  "./ls is not an allowed command",
+		"ls; python3 -c 'print(1)'":             "python3 is not an allowed command",
+		"cat x && sh -c x":                      "sh is not an allowed command",
+		"PATH=. cat x":                          "setting PATH for a command is not allowed",
+		"LD_PRELOAD=./x.so ls":                  "setting LD_PRELOAD for a command is not allowed",
+		"GIT_EXTERNAL_DIFF='sh -c id' git diff": "setting GIT_EXTERNAL_DIFF for a command is not allowed",
+		"ls && GIT_PAGER=x git log":             "setting GIT_PAGER for a command is not allowed",
+		"find . -fls out.txt":                   "-fls is not allowed",
+		"find . -fprint0 out.txt":               "-fprint0 is not allowed",
+		"sort -o out.txt in.txt":                "sort -o is not allowed",
+		"sort -uoout.txt in.txt":                "sort -o is not allowed",
+		"sort --output=out.txt in.txt":          "--output is not allowed",
+		"tree -o out.txt":                       "tree -o is not allowed",
+		"rg --pre=sh x":                         "--pre is not allowed",
+		"rg --pre-glob '*' x":                   "--pre-glob is not allowed",
+		"git branch -D main":                    "git is not an allowed command",
+		"git branch -m new":                     "git is not an allowed command",
+		"git branch newname":                    "git is not an allowed command",
+		"git branch --list -D main":             "git branch -D is not allowed",
+		"git branch --list --move=x y":          "git branch --move is not allowed",
+		"git branch --list -u origin/main":      "git branch -u is not allowed",
+		"uniq in.txt out.txt":                   "uniq is not an allowed command",
+		"file -C -m magic":                      "file -C is not allowed",
+		"file --compile -m magic":               "file --compile is not allowed",
+	}
+	for command, want := range blocked {
+		result := tool.Execute(context.Background(), map[string]any{"action": "run", "command": command})
+		if !result.IsError || !strings.Contains(result.ForLLM, want) {
+			t.Errorf("%q: expected block containing %q, got: %s", command, want, result.ForLLM)
+		}
+	}
+}
+
+func TestShellTool_DeveloperProfile(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	cfg := &config.Config{
+		Tools: config.ToolsConfig{
+			Exec: config.ExecConfig{
+				AllowRemote: true,
+				Profile:     config.ExecProfileDeveloper,
+			},
+		},
+	}
+	tool, err := NewExecToolWithConfig(t.TempDir(), false, cfg)
+	require.NoError(t, err)
+
+	allowed := []string{"git status 2>/dev/null; true", "echo a | uniq", "touch a && cp -f a b"}
+	for _, command := range allowed {
+		result := tool.Execute(context.Background(), map[string]any{"action": "run", "command": command})
+		require.False(t, result.IsError, result.ForLLM)
+	}
+
+	blocked := map[string]string{
+		"git push --force":                    "--force* is not allowed",
+		"git push --force=true":               "--force* is not allowed",
+		"git push --force-with-lease":         "--force* is not allowed",
+		"git push --force-if-includes origin": "--force* is not allowed",
+		"git push -f origin main":             "git -f is not allowed",
+		"git clean -fdx":                      "git -f is not allowed",
+		"git commit --no-verify -m x":         "--no-verify is not allowed",
+		"git reset --hard HEAD":               "--hard is not allowed",
+	}
+	for command, want := range blocked {
+		result := tool.Execute(context.Background(), map[string]any{"action": "run", "command": command})
+		if !result.IsError || !strings.Contains(result.ForLLM, want) {
+			t.Errorf("%q: expected block containing %q, got: %s", command, want, result.ForLLM)
+		}
+	}
+}
+
+func TestShellTool_UnknownProfile(t *testing.T) {
+	cfg := &config.Config{Tools: config.ToolsConfig{Exec: config.ExecConfig{Profile: "root"}}}
+	_, err := NewExecToolWithConfig("", false, cfg)
+	require.ErrorContains(t, err, "unknown exec profile")
+}
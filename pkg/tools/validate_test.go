@@ -4,6 +4,8 @@ import (
 	"context"
 	"strings"
 	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
 // Ensure imports are used.
@@ -351,6 +353,65 @@ func TestValidateToolArgs_RegistryIntegration(t *testing.T) {
 	}
 }
 
+type countingMCPManager struct {
+	calls int
+}
+
+func (m *countingMCPManager) CallTool(
+	ctx context.Context,
+	serverName, toolName string,
+	arguments map[string]any,
+) (*mcp.CallToolResult, error) {
+	m.calls++
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: "ok"}},
+	}, nil
+}
+
+func TestValidateToolArgs_MCPToolRejectedBeforeServerCall(t *testing.T) {
+	manager := &countingMCPManager{}
+	r := NewToolRegistry()
+	mcpTool := NewMCPTool(manager, "github", &mcp.Tool{
+		Name: "create_issue",
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"title":  map[string]any{"type": "string"},
+				"labels": map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+			},
+			"required": []any{"title"},
+		},
+	})
+	r.Register(mcpTool)
+
+	result := r.Execute(context.Background(), mcpTool.Name(), map[string]any{"labels": []any{"bug"}})
+	if !result.IsError {
+		t.Fatal("expected validation error for missing required MCP argument")
+	}
+	if !strings.Contains(result.ForLLM, `missing required property "title"`) {
+		t.Fatalf("expected precise validation error, got %q", result.ForLLM)
+	}
+
+	result = r.Execute(context.Background(), mcpTool.Name(), map[string]any{
+		"title":  "crash",
+		"labels": []any{1.0},
+	})
+	if !result.IsError || !strings.Contains(result.ForLLM, "labels[0]") {
+		t.Fatalf("expected array item validation error, got %q", result.ForLLM)
+	}
+	if manager.calls != 0 {
+		t.Fatalf("expected malformed calls to never reach the MCP server, got %d calls", manager.calls)
+	}
+
+	result = r.Execute(context.Background(), mcpTool.Name(), map[string]any{"title": "crash"})
+	if result.IsError {
+		t.Fatalf("expected valid call to succeed, got %q", result.ForLLM)
+	}
+	if manager.calls != 1 {
+		t.Fatalf("expected valid call to reach the MCP server once, got %d calls", manager.calls)
+	}
+}
+
 func TestValidateToolArgs_RealSchemas(t *testing.T) {
 	execSchema := map[string]any{
 		"type": "object",
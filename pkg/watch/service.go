@@ -0,0 +1,467 @@
+// Package watch polls configured paths and tells the agent about files that
+// were created, modified or deleted, so it can react to new files without
+// listing directories itself.
+package watch
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sipeed/picoclaw/pkg/bus"
+	"github.com/sipeed/picoclaw/pkg/constants"
+	runtimeevents "github.com/sipeed/picoclaw/pkg/events"
+	"github.com/sipeed/picoclaw/pkg/logger"
+	"github.com/sipeed/picoclaw/pkg/state"
+)
+
+const (
+	defaultInterval     = 5 * time.Second
+	minInterval         = time.Second
+	maxTrackedFiles     = 10000
+	maxEventsPerMessage = 50
+)
+
+// Op is the kind of change observed for a file.
+type Op string
+
+const (
+	OpCreate Op = "created"
+	OpModify Op = "modified"
+	OpDelete Op = "deleted"
+)
+
+// Event describes one change to a watched file. Path is relative to the
+// workspace when the file lives inside it.
+type Event struct {
+	Op   Op
+	Path string
+}
+
+type Config struct {
+	Enabled bool
+	// Paths are the files or directories to watch. Relative paths are
+	// resolved against the workspace and a leading ~ against the home
+	// directory. Directories are watched recursively; hidden entries are
+	// ignored.
+	Paths    []string
+	Interval time.Duration
+}
+
+type fileState struct {
+	size    int64
+	modTime time.Time
+}
+
+// Service scans the watched paths every interval and publishes each batch of
+// changes as one inbound system message addressed to the last active
+// channel. Scanning is used instead of OS notifications so the service works
+// the same on every platform and for network mounts.
+//
+// Files the agent's filesystem tools report through NoteWrite are taken into
+// the snapshot without being reported, so the agent is not woken up by its
+// own writes. Other changes seen while an agent tool runs, or since one last
+// ran, may come from a tool that does not report its writes, such as exec;
+// they are held back for one poll and reported on the next one. The
+// workspace state directory is never watched.
+type Service struct {
+	workspace  string
+	roots      []string
+	interval   time.Duration
+	enabled    bool
+	bus        *bus.MessageBus
+	toolEvents runtimeevents.Bus
+	state      *state.Manager
+	snapshot   map[string]fileState
+	scannedAt  time.Time
+	cancel     context.CancelFunc
+	done       chan struct{}
+	mu         sync.RWMutex
+	// deferred holds the paths whose changes the last poll held back; the
+	// next poll reports them.
+	deferred map[string]bool
+
+	toolMu       sync.Mutex
+	toolsRunning int
+	lastToolRun  time.Time
+	written      map[string]time.Time
+}
+
+func NewService(cfg Config, workspace string, stateMgr *state.Manager) *Service {
+	interval := cfg.Interval
+	if interval <= 0 {
+		interval = defaultInterval
+	}
+	interval = max(interval, minInterval)
+
+	roots := make([]string, 0, len(cfg.Paths))
+	for _, p := range cfg.Paths {
+		if root := resolvePath(workspace, p); root != "" {
+			roots = append(roots, root)
+		}
+	}
+
+	return &Service{
+		workspace: workspace,
+		roots:     roots,
+		interval:  interval,
+		enabled:   cfg.Enabled,
+		state:     stateMgr,
+	}
+}
+
+func (s *Service) SetBus(msgBus *bus.MessageBus) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.bus = msgBus
+}
+
+// SetToolEvents sets the runtime event bus the service follows agent tool
+// executions on, to suppress the changes those tools make.
+func (s *Service) SetToolEvents(events runtimeevents.Bus) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.toolEvents = events
+}
+
+func (s *Service) Start(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.enabled || len(s.roots) == 0 {
+		logger.InfoC("watch", "File watch service disabled or no paths configured")
+		return nil
+	}
+	if s.cancel != nil {
+		return nil
+	}
+
+	runCtx, cancel := context.WithCancel(ctx)
+	if s.toolEvents != nil {
+		if err := s.followTools(runCtx); err != nil {
+			cancel()
+			return fmt.Errorf("failed to follow tool executions: %w", err)
+		}
+	}
+	s.cancel = cancel
+	s.done = make(chan struct{})
+	go s.run(runCtx, s.done)
+
+	logger.InfoCF("watch", "File watch service started", map[string]any{
+		"paths":    s.roots,
+		"interval": s.interval.String(),
+	})
+	return nil
+}
+
+// Running reports whether the service was started and has not been stopped.
+// Start returns without starting it when it is disabled or has no paths.
+func (s *Service) Running() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.cancel != nil
+}
+
+// followTools subscribes to agent tool executions until ctx is canceled.
+func (s *Service) followTools(ctx context.Context) error {
+	_, err := s.toolEvents.Channel().OfKind(
+		runtimeevents.KindAgentToolExecStart,
+		runtimeevents.KindAgentToolExecEnd,
+	).Subscribe(ctx, runtimeevents.SubscribeOptions{
+		Name:         "watch-tools",
+		Buffer:       64,
+		Backpressure: runtimeevents.Block,
+		Concurrency:  runtimeevents.Locked,
+	}, func(_ context.Context, evt runtimeevents.Event) error {
+		s.noteToolEvent(evt.Kind, time.Now())
+		return nil
+	})
+	return err
+}
+
+func (s *Service) noteToolEvent(kind runtimeevents.Kind, at time.Time) {
+	s.toolMu.Lock()
+	defer s.toolMu.Unlock()
+	switch kind {
+	case runtimeevents.KindAgentToolExecStart:
+		s.toolsRunning++
+	case runtimeevents.KindAgentToolExecEnd:
+		s.toolsRunning = max(s.toolsRunning-1, 0)
+	}
+	s.lastToolRun = at
+}
+
+// NoteWrite records that an agent tool wrote or deleted the file at path, so
+// the next poll does not report the change back to the agent.
+func (s *Service) NoteWrite(path string) {
+	s.toolMu.Lock()
+	defer s.toolMu.Unlock()
+	if s.written == nil {
+		s.written = make(map[string]time.Time)
+	}
+	s.written[filepath.Clean(path)] = time.Now()
+}
+
+// takeWrites returns the paths noted by NoteWrite and forgets those noted
+// before scanStart, which that scan has already seen.
+func (s *Service) takeWrites(scanStart time.Time) map[string]bool {
+	s.toolMu.Lock()
+	defer s.toolMu.Unlock()
+	written := make(map[string]bool, len(s.written))
+	for path, at := range s.written {
+		written[path] = true
+		if at.Before(scanStart) {
+			delete(s.written, path)
+		}
+	}
+	return written
+}
+
+// toolsActiveSince reports whether an agent tool is running or has run since t.
+func (s *Service) toolsActiveSince(t time.Time) bool {
+	s.toolMu.Lock()
+	defer s.toolMu.Unlock()
+	return s.toolsRunning > 0 || (!s.lastToolRun.IsZero() && !s.lastToolRun.Before(t))
+}
+
+func (s *Service) Stop() {
+	s.mu.Lock()
+	cancel, done := s.cancel, s.done
+	s.cancel, s.done = nil, nil
+	s.mu.Unlock()
+
+	if cancel == nil {
+		return
+	}
+	cancel()
+	<-done
+	logger.InfoC("watch", "File watch service stopped")
+}
+
+func (s *Service) run(ctx context.Context, done chan struct{}) {
+	defer close(done)
+
+	// The initial scan can take a while on large trees, so it runs here
+	// rather than in Start.
+	initial := s.scan()
+	s.mu.Lock()
+	s.snapshot, s.scannedAt = initial, time.Now()
+	s.mu.Unlock()
+	logger.DebugCF("watch", "Initial scan finished", map[string]any{"files": len(initial)})
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if events := s.poll(); len(events) > 0 {
+				s.publish(events)
+			}
+		}
+	}
+}
+
+// poll rescans the watched paths and returns the changes since the previous
+// scan. Changes to files the agent's tools reported writing are skipped.
+// Other changes made while an agent tool ran are held back: the snapshot
+// keeps their previous state, so the next poll sees and reports them.
+func (s *Service) poll() []Event {
+	startedAt := time.Now()
+	next := s.scan()
+	written := s.takeWrites(startedAt)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	prev, prevAt, wasDeferred := s.snapshot, s.scannedAt, s.deferred
+	toolsActive := s.toolsActiveSince(prevAt)
+
+	deferred := make(map[string]bool)
+	skipped := 0
+	var events []Event
+	for _, ev := range diff(prev, next) {
+		switch {
+		case written[ev.Path]:
+			skipped++
+		case toolsActive && !wasDeferred[ev.Path]:
+			deferred[ev.Path] = true
+			if old, ok := prev[ev.Path]; ok {
+				next[ev.Path] = old
+			} else {
+				delete(next, ev.Path)
+			}
+		default:
+			events = append(events, Event{Op: ev.Op, Path: s.displayPath(ev.Path)})
+		}
+	}
+	s.snapshot, s.scannedAt, s.deferred = next, startedAt, deferred
+
+	if skipped > 0 || len(deferred) > 0 {
+		logger.DebugCF("watch", "Held back changes made by agent tools", map[string]any{
+			"skipped":  skipped,
+			"deferred": len(deferred),
+		})
+	}
+	slices.SortFunc(events, func(a, b Event) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+	return events
+}
+
+func (s *Service) scan() map[string]fileState {
+	files := make(map[string]fileState)
+	stateDir := ""
+	if s.workspace != "" {
+		stateDir = filepath.Join(s.workspace, "state")
+	}
+	for _, root := range s.roots {
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				// Missing roots and unreadable subdirectories are skipped;
+				// they are picked up once they become readable.
+				return nil
+			}
+			if path != root && strings.HasPrefix(d.Name(), ".") {
+				if d.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			// Agent state such as sessions and snapshots changes on every turn.
+			if d.IsDir() && path == stateDir {
+				return filepath.SkipDir
+			}
+			if !d.Type().IsRegular() {
+				return nil
+			}
+			if len(files) >= maxTrackedFiles {
+				return fs.SkipAll
+			}
+			info, err := d.Info()
+			if err != nil {
+				return nil
+			}
+			files[path] = fileState{size: info.Size(), modTime: info.ModTime()}
+			return nil
+		})
+		if err != nil {
+			logger.WarnCF("watch", "Failed to scan watched path", map[string]any{
+				"path":  root,
+				"error": err.Error(),
+			})
+		}
+	}
+	if len(files) >= maxTrackedFiles {
+		logger.WarnCF("watch", "Watched paths exceed the file limit; some files are not tracked",
+			map[string]any{"limit": maxTrackedFiles})
+	}
+	return files
+}
+
+// diff returns the changes between two scans, with absolute paths.
+func diff(prev, next map[string]fileState) []Event {
+	var events []Event
+	for path, cur := range next {
+		old, ok := prev[path]
+		switch {
+		case !ok:
+			events = append(events, Event{Op: OpCreate, Path: path})
+		case old.size != cur.size || !old.modTime.Equal(cur.modTime):
+			events = append(events, Event{Op: OpModify, Path: path})
+		}
+	}
+	for path := range prev {
+		if _, ok := next[path]; !ok {
+			events = append(events, Event{Op: OpDelete, Path: path})
+		}
+	}
+	return events
+}
+
+func (s *Service) displayPath(path string) string {
+	if s.workspace != "" {
+		if rel, err := filepath.Rel(s.workspace, path); err == nil && filepath.IsLocal(rel) {
+			return filepath.ToSlash(rel)
+		}
+	}
+	return path
+}
+
+func (s *Service) publish(events []Event) {
+	s.mu.RLock()
+	msgBus := s.bus
+	s.mu.RUnlock()
+	if msgBus == nil {
+		return
+	}
+
+	lastChannel := s.state.GetLastChannel()
+	platform, chatID, _ := strings.Cut(lastChannel, ":")
+	if platform == "" || chatID == "" || constants.IsInternalChannel(platform) {
+		logger.DebugCF("watch", "No last channel, skipping file change notification", map[string]any{
+			"changes": len(events),
+		})
+		return
+	}
+
+	pubCtx, pubCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer pubCancel()
+	err := msgBus.PublishInbound(pubCtx, bus.InboundMessage{
+		Context: bus.InboundContext{
+			Channel:  "system",
+			ChatID:   lastChannel,
+			ChatType: "direct",
+			SenderID: "watch",
+		},
+		Content: FormatEvents(events),
+	})
+	if err != nil {
+		logger.WarnCF("watch", "Failed to publish file change notification", map[string]any{
+			"error": err.Error(),
+		})
+		return
+	}
+	logger.InfoCF("watch", "File change notification sent", map[string]any{
+		"changes": len(events),
+		"to":      platform,
+	})
+}
+
+// FormatEvents renders a batch of changes as the message sent to the agent.
+func FormatEvents(events []Event) string {
+	var b strings.Builder
+	b.WriteString("Files changed in watched paths:")
+	for i, ev := range events {
+		if i == maxEventsPerMessage {
+			fmt.Fprintf(&b, "\n- ... and %d more", len(events)-maxEventsPerMessage)
+			break
+		}
+		fmt.Fprintf(&b, "\n- %s: %s", ev.Op, ev.Path)
+	}
+	return b.String()
+}
+
+func resolvePath(workspace, path string) string {
+	path = strings.TrimSpace(path)
+	switch {
+	case path == "":
+		return ""
+	case path == "~" || strings.HasPrefix(path, "~/"):
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		path = filepath.Join(home, path[1:])
+	case !filepath.IsAbs(path):
+		path = filepath.Join(workspace, path)
+	}
+	return filepath.Clean(path)
+}
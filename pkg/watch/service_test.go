@@ -0,0 +1,205 @@
+package watch
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sipeed/picoclaw/pkg/bus"
+	runtimeevents "github.com/sipeed/picoclaw/pkg/events"
+	"github.com/sipeed/picoclaw/pkg/state"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPoll_ReportsCreateModifyDelete(t *testing.T) {
+	workspace := t.TempDir()
+	writeFile(t, filepath.Join(workspace, "inbox", "old.txt"), "old")
+	writeFile(t, filepath.Join(workspace, "inbox", "keep.txt"), "keep")
+	writeFile(t, filepath.Join(workspace, "inbox", ".hidden", "x.txt"), "x")
+	writeFile(t, filepath.Join(workspace, "elsewhere.txt"), "ignored")
+
+	s := NewService(Config{Enabled: true, Paths: []string{"inbox"}}, workspace, state.NewManager(workspace))
+	s.snapshot = s.scan()
+	if len(s.snapshot) != 2 {
+		t.Fatalf("expected 2 tracked files, got %d", len(s.snapshot))
+	}
+
+	if events := s.poll(); len(events) != 0 {
+		t.Fatalf("expected no events without changes, got %v", events)
+	}
+
+	writeFile(t, filepath.Join(workspace, "inbox", "new", "report.pdf"), "pdf")
+	writeFile(t, filepath.Join(workspace, "inbox", "keep.txt"), "changed")
+	if err := os.Remove(filepath.Join(workspace, "inbox", "old.txt")); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(workspace, "inbox", ".hidden", "y.txt"), "y")
+
+	got := s.poll()
+	want := []Event{
+		{Op: OpModify, Path: "inbox/keep.txt"},
+		{Op: OpCreate, Path: "inbox/new/report.pdf"},
+		{Op: OpDelete, Path: "inbox/old.txt"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("events = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("event %d = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestPoll_IgnoresStateDirAndToolWrites(t *testing.T) {
+	workspace := t.TempDir()
+	s := NewService(Config{Enabled: true, Paths: []string{"."}}, workspace, state.NewManager(workspace))
+	s.snapshot = s.scan()
+
+	writeFile(t, filepath.Join(workspace, "state", "state.json"), "{}")
+	if events := s.poll(); len(events) != 0 {
+		t.Fatalf("expected the state directory to be ignored, got %v", events)
+	}
+
+	s.noteToolEvent(runtimeevents.KindAgentToolExecStart, time.Now())
+	writeFile(t, filepath.Join(workspace, "out.txt"), "written by a tool")
+	s.NoteWrite(filepath.Join(workspace, "out.txt"))
+	if events := s.poll(); len(events) != 0 {
+		t.Fatalf("expected the tool's reported write to be ignored, got %v", events)
+	}
+	s.noteToolEvent(runtimeevents.KindAgentToolExecEnd, time.Now())
+	writeFile(t, filepath.Join(workspace, "out.txt"), "still the tool")
+	s.NoteWrite(filepath.Join(workspace, "out.txt"))
+	if events := s.poll(); len(events) != 0 {
+		t.Fatalf("expected the tool's reported write to be ignored, got %v", events)
+	}
+
+	writeFile(t, filepath.Join(workspace, "dropped.txt"), "from the user")
+	got := s.poll()
+	if len(got) != 1 || got[0] != (Event{Op: OpCreate, Path: "dropped.txt"}) {
+		t.Fatalf("events = %v, want dropped.txt created", got)
+	}
+}
+
+func TestPoll_DefersUnreportedChangesDuringToolRun(t *testing.T) {
+	workspace := t.TempDir()
+	writeFile(t, filepath.Join(workspace, "gone.txt"), "gone")
+	s := NewService(Config{Enabled: true, Paths: []string{"."}}, workspace, state.NewManager(workspace))
+	s.snapshot = s.scan()
+
+	// A tool runs while the user drops a file and another one is deleted;
+	// only the tool's own write is reported through NoteWrite.
+	s.noteToolEvent(runtimeevents.KindAgentToolExecStart, time.Now())
+	writeFile(t, filepath.Join(workspace, "tool.txt"), "tool")
+	s.NoteWrite(filepath.Join(workspace, "tool.txt"))
+	writeFile(t, filepath.Join(workspace, "user.txt"), "user")
+	if err := os.Remove(filepath.Join(workspace, "gone.txt")); err != nil {
+		t.Fatal(err)
+	}
+	if events := s.poll(); len(events) != 0 {
+		t.Fatalf("expected unreported changes to be held back, got %v", events)
+	}
+
+	// The tool is still running, but held back changes are not dropped.
+	got := s.poll()
+	want := []Event{
+		{Op: OpDelete, Path: "gone.txt"},
+		{Op: OpCreate, Path: "user.txt"},
+	}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Fatalf("events = %v, want %v", got, want)
+	}
+	if events := s.poll(); len(events) != 0 {
+		t.Fatalf("expected changes to be reported once, got %v", events)
+	}
+}
+
+func TestPublish_SendsSystemMessageToLastChannel(t *testing.T) {
+	workspace := t.TempDir()
+	stateMgr := state.NewManager(workspace)
+	msgBus := bus.NewMessageBus()
+	defer msgBus.Close()
+
+	s := NewService(Config{Enabled: true, Paths: []string{"."}}, workspace, stateMgr)
+	s.SetBus(msgBus)
+
+	// Without a last channel there is nobody to tell.
+	s.publish([]Event{{Op: OpCreate, Path: "a.txt"}})
+	select {
+	case msg := <-msgBus.InboundChan():
+		t.Fatalf("unexpected message without last channel: %+v", msg)
+	default:
+	}
+
+	if err := stateMgr.SetLastChannel("telegram:42"); err != nil {
+		t.Fatal(err)
+	}
+	s.publish([]Event{{Op: OpCreate, Path: "a.txt"}})
+
+	select {
+	case msg := <-msgBus.InboundChan():
+		if msg.Channel != "system" || msg.ChatID != "telegram:42" || msg.SenderID != "watch" {
+			t.Fatalf("unexpected routing: %+v", msg.Context)
+		}
+		if !strings.Contains(msg.Content, "- created: a.txt") {
+			t.Fatalf("unexpected content: %q", msg.Content)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected an inbound message")
+	}
+}
+
+func TestFormatEvents_Truncates(t *testing.T) {
+	events := make([]Event, maxEventsPerMessage+3)
+	for i := range events {
+		events[i] = Event{Op: OpCreate, Path: "f"}
+	}
+	out := FormatEvents(events)
+	if !strings.HasSuffix(out, "- ... and 3 more") {
+		t.Fatalf("expected truncation note, got %q", out[len(out)-40:])
+	}
+}
+
+func TestService_StartStop(t *testing.T) {
+	workspace := t.TempDir()
+	s := NewService(Config{Enabled: true, Paths: []string{"."}}, workspace, state.NewManager(workspace))
+	if err := s.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if !s.Running() {
+		t.Fatal("expected the service to be running")
+	}
+	s.Stop()
+	s.Stop()
+	if s.Running() {
+		t.Fatal("expected the service to be stopped")
+	}
+
+	disabled := NewService(Config{Paths: []string{"."}}, workspace, state.NewManager(workspace))
+	if err := disabled.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if disabled.Running() {
+		t.Fatal("disabled service should not start")
+	}
+
+	noPaths := NewService(Config{Enabled: true}, workspace, state.NewManager(workspace))
+	if err := noPaths.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if noPaths.Running() {
+		t.Fatal("service without paths should not start")
+	}
+}
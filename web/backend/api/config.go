@@ -413,6 +413,9 @@ func validateConfig(cfg *config.Config) []string {
 		errs = append(
 			errs,
 			validateRegexPatterns("tools.exec.custom_allow_patterns", cfg.Tools.Exec.CustomAllowPatterns)...)
+		if _, err := cfg.Tools.Exec.ActiveProfile(); err != nil {
+			errs = append(errs, fmt.Sprintf("tools.exec.profile: %v", err))
+		}
 	}
 
 	return errs
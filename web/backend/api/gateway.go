@@ -428,12 +428,57 @@ func computeConfigSignature(cfg *config.Config) string {
 	if cfg.Tools.ListDir.Enabled {
 		toolSignatures = append(toolSignatures, "list_dir")
 	}
+	if cfg.Tools.FindFiles.Enabled {
+		toolSignatures = append(toolSignatures, "find_files")
+	}
+	if cfg.Tools.Grep.Enabled {
+		toolSignatures = append(toolSignatures, "grep")
+	}
+	if cfg.Tools.FileStat.Enabled {
+		toolSignatures = append(toolSignatures, "file_stat")
+	}
+	if cfg.Tools.TailFile.Enabled {
+		toolSignatures = append(toolSignatures, "tail_file")
+	}
+	if cfg.Tools.QueryData.Enabled {
+		toolSignatures = append(toolSignatures, "query_data")
+	}
 	if cfg.Tools.EditFile.Enabled {
 		toolSignatures = append(toolSignatures, "edit_file")
 	}
+	if cfg.Tools.MultiEdit.Enabled {
+		toolSignatures = append(toolSignatures, "multi_edit")
+	}
+	if cfg.Tools.ApplyPatch.Enabled {
+		toolSignatures = append(toolSignatures, "apply_patch")
+	}
 	if cfg.Tools.AppendFile.Enabled {
 		toolSignatures = append(toolSignatures, "append_file")
 	}
+	if cfg.Tools.WriteSession.Enabled {
+		toolSignatures = append(toolSignatures, "write_session")
+	}
+	if cfg.Tools.DeleteFile.Enabled {
+		toolSignatures = append(toolSignatures, "delete_file")
+	}
+	if cfg.Tools.CopyFile.Enabled {
+		toolSignatures = append(toolSignatures, "copy_file")
+	}
+	if cfg.Tools.Archive.Enabled {
+		toolSignatures = append(toolSignatures, "archive")
+	}
+	if cfg.Tools.SetPermissions.Enabled {
+		toolSignatures = append(toolSignatures, "set_permissions")
+	}
+	if cfg.Tools.UndoFile.Enabled {
+		toolSignatures = append(toolSignatures, "undo_file")
+	}
+	if cfg.Tools.Scratch.Enabled {
+		toolSignatures = append(toolSignatures, "scratch")
+	}
+	if cfg.Tools.Snapshot.Enabled {
+		toolSignatures = append(toolSignatures, "snapshot")
+	}
 	if cfg.Tools.Exec.Enabled {
 		toolSignatures = append(toolSignatures, "exec")
 	}
@@ -88,18 +88,108 @@ var toolCatalog = []toolCatalogEntry{
 		Category:    "filesystem",
 		ConfigKey:   "list_dir",
 	},
+	{
+		Name:        "find_files",
+		Description: "Locate files by glob pattern with optional size and modification-time filters.",
+		Category:    "filesystem",
+		ConfigKey:   "find_files",
+	},
+	{
+		Name:        "grep",
+		Description: "Search file contents by regex or literal text and return matching lines with context.",
+		Category:    "filesystem",
+		ConfigKey:   "grep",
+	},
+	{
+		Name:        "file_stat",
+		Description: "Inspect file metadata such as size, type, modification time and line count.",
+		Category:    "filesystem",
+		ConfigKey:   "file_stat",
+	},
+	{
+		Name:        "tail_file",
+		Description: "Read the first or last lines of a file, ideal for inspecting growing log files.",
+		Category:    "filesystem",
+		ConfigKey:   "tail_file",
+	},
+	{
+		Name:        "query_data",
+		Description: "Filter, sort and aggregate CSV, TSV, JSON and JSON Lines files as tables.",
+		Category:    "filesystem",
+		ConfigKey:   "query_data",
+	},
 	{
 		Name:        "edit_file",
 		Description: "Apply targeted edits to existing files without rewriting everything.",
 		Category:    "filesystem",
 		ConfigKey:   "edit_file",
 	},
+	{
+		Name:        "multi_edit",
+		Description: "Apply several replacements to one file atomically in a single call.",
+		Category:    "filesystem",
+		ConfigKey:   "multi_edit",
+	},
+	{
+		Name:        "apply_patch",
+		Description: "Apply a unified diff that may touch several files, all or nothing.",
+		Category:    "filesystem",
+		ConfigKey:   "apply_patch",
+	},
 	{
 		Name:        "append_file",
 		Description: "Append content to the end of an existing file.",
 		Category:    "filesystem",
 		ConfigKey:   "append_file",
 	},
+	{
+		Name:        "write_session",
+		Description: "Write large files in chunks across several calls with an explicit finalize step.",
+		Category:    "filesystem",
+		ConfigKey:   "write_session",
+	},
+	{
+		Name:        "delete_file",
+		Description: "Delete files or empty directories the agent no longer needs.",
+		Category:    "filesystem",
+		ConfigKey:   "delete_file",
+	},
+	{
+		Name:        "copy_file",
+		Description: "Copy files or directory trees, e.g. to back up a file before editing it.",
+		Category:    "filesystem",
+		ConfigKey:   "copy_file",
+	},
+	{
+		Name:        "archive",
+		Description: "Create, extract and list zip and tar.gz archives.",
+		Category:    "filesystem",
+		ConfigKey:   "archive",
+	},
+	{
+		Name:        "set_permissions",
+		Description: "Make files executable or read-only using a safe subset of permission modes.",
+		Category:    "filesystem",
+		ConfigKey:   "set_permissions",
+	},
+	{
+		Name:        "undo_file",
+		Description: "Restore the previous version of a file changed by the filesystem tools.",
+		Category:    "filesystem",
+		ConfigKey:   "undo_file",
+	},
+	{
+		Name:        "scratch",
+		Description: "Create and clean up temporary working directories in the workspace.",
+		Category:    "filesystem",
+		ConfigKey:   "scratch",
+	},
+	{
+		Name:        "snapshot",
+		Description: "Capture the workspace before a risky task and roll back to it if needed.",
+		Category:    "filesystem",
+		ConfigKey:   "snapshot",
+	},
 	{
 		Name:        "exec",
 		Description: "Run shell commands inside the configured workspace sandbox.",
@@ -342,10 +432,40 @@ func applyToolState(cfg *config.Config, toolName string, enabled bool) error {
 		cfg.Tools.WriteFile.Enabled = enabled
 	case "list_dir":
 		cfg.Tools.ListDir.Enabled = enabled
+	case "find_files":
+		cfg.Tools.FindFiles.Enabled = enabled
+	case "grep":
+		cfg.Tools.Grep.Enabled = enabled
+	case "file_stat":
+		cfg.Tools.FileStat.Enabled = enabled
+	case "tail_file":
+		cfg.Tools.TailFile.Enabled = enabled
+	case "query_data":
+		cfg.Tools.QueryData.Enabled = enabled
 	case "edit_file":
 		cfg.Tools.EditFile.Enabled = enabled
+	case "multi_edit":
+		cfg.Tools.MultiEdit.Enabled = enabled
+	case "apply_patch":
+		cfg.Tools.ApplyPatch.Enabled = enabled
 	case "append_file":
 		cfg.Tools.AppendFile.Enabled = enabled
+	case "write_session":
+		cfg.Tools.WriteSession.Enabled = enabled
+	case "delete_file":
+		cfg.Tools.DeleteFile.Enabled = enabled
+	case "copy_file":
+		cfg.Tools.CopyFile.Enabled = enabled
+	case "archive":
+		cfg.Tools.Archive.Enabled = enabled
+	case "set_permissions":
+		cfg.Tools.SetPermissions.Enabled = enabled
+	case "undo_file":
+		cfg.Tools.UndoFile.Enabled = enabled
+	case "scratch":
+		cfg.Tools.Scratch.Enabled = enabled
+	case "snapshot":
+		cfg.Tools.Snapshot.Enabled = enabled
 	case "exec":
 		cfg.Tools.Exec.Enabled = enabled
 	case "cron":